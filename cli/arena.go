@@ -0,0 +1,270 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+const arenaNoticesLimit = 5
+
+// arenaObject is a game object decoded from the web-socket stream
+type arenaObject struct {
+	ID   uint32     `json:"id"`
+	Type string     `json:"type"`
+	Dot  *[2]uint8  `json:"dot"`
+	Dots [][2]uint8 `json:"dots"`
+}
+
+func (o *arenaObject) dots() [][2]uint8 {
+	if o.Dot != nil {
+		return [][2]uint8{*o.Dot}
+	}
+	return o.Dots
+}
+
+type arenaCell struct {
+	symbol byte
+	colour string
+}
+
+const (
+	colourReset = "\x1b[0m"
+	colourBold  = "\x1b[1m"
+)
+
+var arenaCells = map[string]arenaCell{
+	"snake":        {'o', "\x1b[32m"},
+	"apple":        {'@', "\x1b[31m"},
+	"corpse":       {'x', "\x1b[33m"},
+	"mouse":        {'m', "\x1b[37m"},
+	"watermelon":   {'W', "\x1b[35m"},
+	"wall":         {'#', "\x1b[34m"},
+	"poison":       {'!', "\x1b[35m"},
+	"golden_apple": {'$', "\x1b[33m"},
+	"fruit":        {'%', "\x1b[31m"},
+}
+
+var arenaCellUnknown = arenaCell{'?', "\x1b[36m"}
+
+// arena keeps the state of a game built from the web-socket stream
+type arena struct {
+	width   uint8
+	height  uint8
+	objects map[uint32]*arenaObject
+	snakeID uint32
+	notices []string
+	mux     *sync.RWMutex
+}
+
+func newArena() *arena {
+	return &arena{
+		objects: map[uint32]*arenaObject{},
+		mux:     &sync.RWMutex{},
+	}
+}
+
+type arenaMessage struct {
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// apply applies an output message of the server or a batch of messages sent
+// as a JSON array to the state
+func (a *arena) apply(data []byte) error {
+	data = bytes.TrimSpace(data)
+
+	if len(data) > 0 && data[0] == '[' {
+		var messages []json.RawMessage
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return err
+		}
+		for _, message := range messages {
+			if err := a.apply(message); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var message arenaMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		return err
+	}
+
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	switch message.Type {
+	case "game":
+		return a.applyGameEvent(message.Payload)
+	case "player":
+		return a.applyPlayerMessage(message.Payload)
+	case "broadcast":
+		var text string
+		if err := json.Unmarshal(message.Payload, &text); err != nil {
+			return err
+		}
+		a.notice("broadcast: " + text)
+	}
+
+	return nil
+}
+
+func (a *arena) applyGameEvent(data []byte) error {
+	var event arenaMessage
+	if err := json.Unmarshal(data, &event); err != nil {
+		return err
+	}
+
+	switch event.Type {
+	case "create", "update":
+		object := &arenaObject{}
+		if err := json.Unmarshal(event.Payload, object); err != nil {
+			return err
+		}
+		a.objects[object.ID] = object
+	case "delete":
+		object := &arenaObject{}
+		if err := json.Unmarshal(event.Payload, object); err != nil {
+			return err
+		}
+		delete(a.objects, object.ID)
+	}
+
+	return nil
+}
+
+func (a *arena) applyPlayerMessage(data []byte) error {
+	var message arenaMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		return err
+	}
+
+	switch message.Type {
+	case "size":
+		var size struct {
+			Width  uint8 `json:"width"`
+			Height uint8 `json:"height"`
+		}
+		if err := json.Unmarshal(message.Payload, &size); err != nil {
+			return err
+		}
+		a.width, a.height = size.Width, size.Height
+	case "objects":
+		var objects []*arenaObject
+		if err := json.Unmarshal(message.Payload, &objects); err != nil {
+			return err
+		}
+		a.objects = make(map[uint32]*arenaObject, len(objects))
+		for _, object := range objects {
+			a.objects[object.ID] = object
+		}
+	case "snake":
+		if err := json.Unmarshal(message.Payload, &a.snakeID); err != nil {
+			return err
+		}
+	case "notice", "error", "game_closed":
+		var text string
+		if err := json.Unmarshal(message.Payload, &text); err != nil {
+			return err
+		}
+		a.notice(message.Type + ": " + text)
+	}
+
+	return nil
+}
+
+func (a *arena) notice(text string) {
+	a.notices = append(a.notices, text)
+	if len(a.notices) > arenaNoticesLimit {
+		a.notices = a.notices[len(a.notices)-arenaNoticesLimit:]
+	}
+}
+
+// render returns a frame of the arena. Objects are coloured if colours is
+// true, the spectator's own snake is bold
+func (a *arena) render(colours bool) []byte {
+	a.mux.RLock()
+	defer a.mux.RUnlock()
+
+	grid := make([][]*arenaObject, a.height)
+	for y := range grid {
+		grid[y] = make([]*arenaObject, a.width)
+	}
+
+	ids := make([]uint32, 0, len(a.objects))
+	for id := range a.objects {
+		ids = append(ids, id)
+	}
+	// Draw objects in the same order to avoid flickering of overlaps
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+
+	counts := map[string]int{}
+
+	for _, id := range ids {
+		object := a.objects[id]
+		counts[object.Type]++
+		for _, dot := range object.dots() {
+			if dot[0] < a.width && dot[1] < a.height {
+				grid[dot[1]][dot[0]] = object
+			}
+		}
+	}
+
+	buff := &bytes.Buffer{}
+
+	fmt.Fprintf(buff, "map %dx%d, objects %d", a.width, a.height, len(a.objects))
+	if a.snakeID > 0 {
+		fmt.Fprintf(buff, ", snake %d", a.snakeID)
+	}
+	buff.WriteByte('\n')
+
+	types := make([]string, 0, len(counts))
+	for objectType := range counts {
+		types = append(types, objectType)
+	}
+	sort.Strings(types)
+	for _, objectType := range types {
+		fmt.Fprintf(buff, "%s: %d  ", objectType, counts[objectType])
+	}
+	buff.WriteByte('\n')
+
+	for _, row := range grid {
+		for _, object := range row {
+			if object == nil {
+				buff.WriteString(" .")
+				continue
+			}
+
+			cell, ok := arenaCells[object.Type]
+			if !ok {
+				cell = arenaCellUnknown
+			}
+
+			buff.WriteByte(' ')
+			if colours {
+				buff.WriteString(cell.colour)
+				if object.ID == a.snakeID {
+					buff.WriteString(colourBold)
+				}
+			}
+			buff.WriteByte(cell.symbol)
+			if colours {
+				buff.WriteString(colourReset)
+			}
+		}
+		buff.WriteByte('\n')
+	}
+
+	for _, notice := range a.notices {
+		buff.WriteString(notice)
+		buff.WriteByte('\n')
+	}
+
+	return buff.Bytes()
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_arena_AppliesMessagesAndRenders(t *testing.T) {
+	a := newArena()
+
+	messages := []string{
+		`{"type":"player","payload":{"type":"size","payload":{"width":4,"height":3}}}`,
+		`{"type":"player","payload":{"type":"objects","payload":[{"id":1,"dots":[[0,0],[1,0]],"type":"wall"},{"id":2,"dot":[3,2],"type":"apple"}]}}`,
+		`{"type":"player","payload":{"type":"snake","payload":3}}`,
+		`{"type":"game","payload":{"type":"create","payload":{"id":3,"dots":[[1,1],[2,1]],"type":"snake"}}}`,
+		`{"type":"game","payload":{"type":"update","payload":{"id":3,"dots":[[0,1],[1,1]],"type":"snake"}}}`,
+		`{"type":"game","payload":{"type":"delete","payload":{"id":2,"dot":[3,2],"type":"apple"}}}`,
+		`{"type":"game","payload":{"type":"create","payload":{"type":"mouse","id":4,"dot":[3,0],"direction":"north"}}}`,
+		`{"type":"broadcast","payload":"hello"}`,
+	}
+
+	for _, message := range messages {
+		require.Nil(t, a.apply([]byte(message)), message)
+	}
+
+	require.Equal(t, "map 4x3, objects 3, snake 3\n"+
+		"mouse: 1  snake: 1  wall: 1  \n"+
+		" # # . m\n"+
+		" o o . .\n"+
+		" . . . .\n"+
+		"broadcast: hello\n", string(a.render(false)))
+}
+
+func Test_arena_RenderColoursOwnSnake(t *testing.T) {
+	a := newArena()
+
+	require.Nil(t, a.apply([]byte(`{"type":"player","payload":{"type":"size","payload":{"width":1,"height":1}}}`)))
+	require.Nil(t, a.apply([]byte(`{"type":"player","payload":{"type":"snake","payload":1}}`)))
+	require.Nil(t, a.apply([]byte(`{"type":"game","payload":{"type":"create","payload":{"id":1,"dots":[[0,0]],"type":"snake"}}}`)))
+
+	require.Contains(t, string(a.render(true)), " "+arenaCells["snake"].colour+colourBold+"o"+colourReset+"\n")
+}
+
+func Test_arena_AppliesBatches(t *testing.T) {
+	a := newArena()
+
+	require.Nil(t, a.apply([]byte(`{"type":"player","payload":{"type":"size","payload":{"width":2,"height":1}}}`)))
+	require.Nil(t, a.apply([]byte(`[{"type":"game","payload":{"type":"create","payload":{"id":1,"dot":[0,0],"type":"apple"}}},`+
+		`{"type":"game","payload":{"type":"update","payload":{"id":1,"dot":[1,0],"type":"apple"}}}]`)))
+
+	require.Equal(t, "map 2x1, objects 1\n"+
+		"apple: 1  \n"+
+		" . @\n", string(a.render(false)))
+}
+
+func Test_websocketURL(t *testing.T) {
+	wsURL, err := websocketURL("http://localhost:8080", 3)
+	require.Nil(t, err)
+	require.Equal(t, "ws://localhost:8080/ws/games/3", wsURL)
+
+	wsURL, err = websocketURL("https://example.com/snake/", 5)
+	require.Nil(t, err)
+	require.Equal(t, "wss://example.com/snake/ws/games/5", wsURL)
+
+	_, err = websocketURL("ftp://example.com", 5)
+	require.Equal(t, errUnsupportedScheme, err)
+}
+
+func Test_spectatorURL(t *testing.T) {
+	wsURL, err := spectatorURL("http://localhost:8080/", 3)
+	require.Nil(t, err)
+	require.Equal(t, "ws://localhost:8080/ws/games/3?spectate=true", wsURL)
+
+	_, err = spectatorURL("ftp://example.com", 5)
+	require.Equal(t, errUnsupportedScheme, err)
+}
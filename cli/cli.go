@@ -0,0 +1,208 @@
+// Package cli implements subcommands of the server binary which help to
+// administer a running server
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const defaultServerURL = "http://localhost:8080"
+
+const flagUsageServerURL = "URL of the server"
+
+const (
+	exitCodeOK    = 0
+	exitCodeError = 1
+	exitCodeUsage = 2
+)
+
+// command is a subcommand of the binary. A command has either a run function
+// or subcommands
+type command struct {
+	name        string
+	usage       string
+	run         func(args []string, stdout, stderr io.Writer) error
+	subcommands []*command
+}
+
+var commands = []*command{
+	{
+		name:  "games",
+		usage: "manage games",
+		subcommands: []*command{
+			{
+				name:  "list",
+				usage: "list games",
+				run:   runGamesList,
+			},
+			{
+				name:  "create",
+				usage: "create a game",
+				run:   runGamesCreate,
+			},
+			{
+				name:  "delete",
+				usage: "delete a game",
+				run:   runGamesDelete,
+			},
+		},
+	},
+	{
+		name:  "capacity",
+		usage: "show capacity of the server",
+		run:   runCapacity,
+	},
+	{
+		name:  "broadcast",
+		usage: "send a message to all players in a game",
+		run:   runBroadcast,
+	},
+	{
+		name:  "watch",
+		usage: "watch a game in the terminal",
+		run:   runWatch,
+	},
+	{
+		name:  "loadtest",
+		usage: "generate web-socket load on the server",
+		run:   runLoadTest,
+	},
+	{
+		name:  "config",
+		usage: "work with config files",
+		subcommands: []*command{
+			{
+				name:  "check",
+				usage: "validate a config file",
+				run:   runConfigCheck,
+			},
+		},
+	},
+}
+
+var errUsage = errors.New("invalid usage")
+
+// Names returns names of the subcommands
+func Names() []string {
+	names := make([]string, 0, len(commands))
+	for _, c := range commands {
+		names = append(names, c.name)
+	}
+	return names
+}
+
+// IsCommand returns true if the argument is a name of a subcommand
+func IsCommand(name string) bool {
+	return lookup(commands, name) != nil
+}
+
+func lookup(commands []*command, name string) *command {
+	for _, c := range commands {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// Run runs the subcommand from args and returns an exit code
+func Run(program string, args []string, stdout, stderr io.Writer) int {
+	list := commands
+	path := []string{program}
+
+	for {
+		if len(args) == 0 {
+			printCommands(stderr, path, list)
+			return exitCodeUsage
+		}
+
+		c := lookup(list, args[0])
+		if c == nil {
+			fmt.Fprintf(stderr, "unknown command: %s\n\n", args[0])
+			printCommands(stderr, path, list)
+			return exitCodeUsage
+		}
+
+		path = append(path, c.name)
+		args = args[1:]
+
+		if c.run == nil {
+			list = c.subcommands
+			continue
+		}
+
+		if err := c.run(args, stdout, stderr); err != nil {
+			if err == flag.ErrHelp {
+				return exitCodeOK
+			}
+			if err == errUsage {
+				return exitCodeUsage
+			}
+			fmt.Fprintf(stderr, "%s: %s\n", strings.Join(path, " "), err)
+			return exitCodeError
+		}
+
+		return exitCodeOK
+	}
+}
+
+func printCommands(w io.Writer, path []string, commands []*command) {
+	fmt.Fprintf(w, "Usage: %s <command> [options]\n\nCommands:\n", strings.Join(path, " "))
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", c.name, c.usage)
+	}
+}
+
+// newFlagSet returns a flag set which writes errors to the output of the
+// command. Positional arguments are described by the usage line
+func newFlagSet(name, usage string, stderr io.Writer) *flag.FlagSet {
+	f := flag.NewFlagSet(name, flag.ContinueOnError)
+	f.SetOutput(stderr)
+	f.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: %s\n\n", usage)
+		f.PrintDefaults()
+	}
+	return f
+}
+
+// parseFlags parses the flag set allowing options after positional
+// arguments. The flag set prints parsing errors itself
+func parseFlags(f *flag.FlagSet, args []string) error {
+	var positional []string
+
+	for len(args) > 0 {
+		if err := f.Parse(args); err != nil {
+			if err == flag.ErrHelp {
+				return err
+			}
+			return errUsage
+		}
+
+		rest := f.Args()
+		if len(rest) == 0 {
+			break
+		}
+
+		// Everything after the terminator is positional
+		if len(args) > len(rest) && args[len(args)-len(rest)-1] == "--" {
+			positional = append(positional, rest...)
+			break
+		}
+
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+
+	return f.Parse(append([]string{"--"}, positional...))
+}
+
+// usageError prints the message and the usage of the flag set
+func usageError(f *flag.FlagSet, message string) error {
+	fmt.Fprintln(f.Output(), message)
+	f.Usage()
+	return errUsage
+}
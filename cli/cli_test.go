@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivan1993spb/snake-server/connections"
+	"github.com/ivan1993spb/snake-server/handlers"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	logger, _ := test.NewNullLogger()
+
+	groupManager, err := connections.NewConnectionGroupManager(logger, 10, 100)
+	require.Nil(t, err)
+
+	router := mux.NewRouter()
+	apiRouter := router.PathPrefix("/api").Subrouter()
+	apiRouter.Path(handlers.URLRouteCreateGame).Methods(handlers.MethodCreateGame).Handler(handlers.NewCreateGameHandler(logger, groupManager))
+	apiRouter.Path(handlers.URLRouteGetGames).Methods(handlers.MethodGetGames).Handler(handlers.NewGetGamesHandler(logger, groupManager))
+	apiRouter.Path(handlers.URLRouteDeleteGameByID).Methods(handlers.MethodDeleteGame).Handler(handlers.NewDeleteGameHandler(logger, groupManager))
+	apiRouter.Path(handlers.URLRouteGetCapacity).Methods(handlers.MethodGetCapacity).Handler(handlers.NewGetCapacityHandler(logger, groupManager))
+	apiRouter.Path(handlers.URLRouteGetConnections).Methods(handlers.MethodGetConnections).Handler(handlers.NewGetConnectionsHandler(logger, groupManager))
+	wsRouter := router.PathPrefix("/ws").Subrouter()
+	wsRouter.Path(handlers.URLRouteGameWebSocketByID).Methods(handlers.MethodGame).Handler(handlers.NewGameWebSocketHandler(logger, groupManager))
+
+	return httptest.NewServer(router)
+}
+
+func Test_Run_ManagesGames(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	run := func(args ...string) (int, string, string) {
+		stdout := &bytes.Buffer{}
+		stderr := &bytes.Buffer{}
+		code := Run("snake-server", args, stdout, stderr)
+		return code, stdout.String(), stderr.String()
+	}
+
+	code, stdout, _ := run("games", "create", "-server", server.URL, "-width", "20", "-height", "15", "-limit", "3")
+	require.Equal(t, exitCodeOK, code)
+	require.Equal(t, "game 1 created: 20x15, players limit 3\n", stdout)
+
+	code, stdout, _ = run("games", "list", "-server", server.URL)
+	require.Equal(t, exitCodeOK, code)
+	require.Contains(t, stdout, "1   0/3      20     15      0")
+	require.Contains(t, stdout, "1 of 10 games")
+
+	code, stdout, _ = run("capacity", "-server", server.URL)
+	require.Equal(t, exitCodeOK, code)
+	require.Equal(t, "0.00%\n", stdout)
+
+	code, stdout, _ = run("games", "delete", "1", "-server", server.URL)
+	require.Equal(t, exitCodeOK, code)
+	require.Equal(t, "game 1 deleted\n", stdout)
+
+	code, _, stderr := run("games", "delete", "1", "-server", server.URL)
+	require.Equal(t, exitCodeError, code)
+	require.Equal(t, "snake-server games delete: server error 404: game not found\n", stderr)
+}
+
+func Test_Run_ReturnsUsageCodeOnInvalidUsage(t *testing.T) {
+	for _, args := range [][]string{
+		{},
+		{"games"},
+		{"games", "unknown"},
+		{"games", "delete"},
+		{"games", "delete", "one"},
+		{"games", "create", "-width", "300"},
+		{"broadcast", "1"},
+		{"capacity", "-unknown-flag"},
+	} {
+		require.Equal(t, exitCodeUsage, Run("snake-server", args, ioutil.Discard, ioutil.Discard), args)
+	}
+}
+
+func Test_Run_ChecksConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snake-server-cli")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	valid := filepath.Join(dir, "valid.yaml")
+	require.Nil(t, ioutil.WriteFile(valid, []byte("server:\n  limits:\n    groups: 10\n"), 0644))
+
+	invalid := filepath.Join(dir, "invalid.yaml")
+	require.Nil(t, ioutil.WriteFile(invalid, []byte("server:\n  limits:\n    groups: -1\n"), 0644))
+
+	stdout := &bytes.Buffer{}
+	require.Equal(t, exitCodeOK, Run("snake-server", []string{"config", "check", valid}, stdout, ioutil.Discard))
+	require.Equal(t, valid+": ok\n", stdout.String())
+
+	require.Equal(t, exitCodeError, Run("snake-server", []string{"config", "check", invalid}, ioutil.Discard, ioutil.Discard))
+	require.Equal(t, exitCodeError, Run("snake-server", []string{"config", "check", filepath.Join(dir, "none.yaml")}, ioutil.Discard, ioutil.Discard))
+}
+
+func Test_parseFlags_AllowsOptionsAfterArguments(t *testing.T) {
+	f := flag.NewFlagSet("test", flag.ContinueOnError)
+	force := f.Bool("force", false, "")
+	reason := f.String("reason", "", "")
+
+	require.Nil(t, parseFlags(f, []string{"1", "-force", "two", "-reason", "bye", "--", "-three"}))
+	require.True(t, *force)
+	require.Equal(t, "bye", *reason)
+	require.Equal(t, []string{"1", "two", "-three"}, f.Args())
+}
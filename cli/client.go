@@ -0,0 +1,196 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const clientTimeout = time.Second * 30
+
+const clientName = "SnakeServerCLI"
+
+// Game is a game returned by the API
+type Game struct {
+	ID     int    `json:"id"`
+	Limit  int    `json:"limit"`
+	Count  int    `json:"count"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Rate   uint32 `json:"rate"`
+}
+
+// Games is a list of games returned by the API
+type Games struct {
+	Games []Game `json:"games"`
+	Limit int    `json:"limit"`
+	Count int    `json:"count"`
+}
+
+// ErrAPI is an error response of the API
+type ErrAPI struct {
+	Code int    `json:"code"`
+	Text string `json:"text"`
+}
+
+func (e *ErrAPI) Error() string {
+	return fmt.Sprintf("server error %d: %s", e.Code, e.Text)
+}
+
+// Client is a client of the server's REST API
+type Client struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewClient(serverURL string) (*Client, error) {
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid server URL: %s", serverURL)
+	}
+
+	return &Client{
+		baseURL: strings.TrimSuffix(serverURL, "/") + "/api",
+		client: &http.Client{
+			Timeout: clientTimeout,
+		},
+	}, nil
+}
+
+func (c *Client) do(method, path string, form url.Values, response interface{}) error {
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+
+	request, err := http.NewRequest(method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("X-Snake-Client", clientName)
+	if form != nil {
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	r, err := c.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+
+	if r.StatusCode < 200 || r.StatusCode >= 300 {
+		errAPI := &ErrAPI{}
+		if err := json.NewDecoder(r.Body).Decode(errAPI); err != nil {
+			return &ErrAPI{
+				Code: r.StatusCode,
+				Text: http.StatusText(r.StatusCode),
+			}
+		}
+		return errAPI
+	}
+
+	return json.NewDecoder(r.Body).Decode(response)
+}
+
+// Games returns games of the server
+func (c *Client) Games() (*Games, error) {
+	games := &Games{}
+	if err := c.do(http.MethodGet, "/games", nil, games); err != nil {
+		return nil, err
+	}
+	return games, nil
+}
+
+// CreateGame creates a game and returns it
+func (c *Client) CreateGame(width, height uint8, limit int, enableWalls bool) (*Game, error) {
+	game := &Game{}
+	err := c.do(http.MethodPost, "/games", url.Values{
+		"width":        {strconv.Itoa(int(width))},
+		"height":       {strconv.Itoa(int(height))},
+		"limit":        {strconv.Itoa(limit)},
+		"enable_walls": {strconv.FormatBool(enableWalls)},
+	}, game)
+	if err != nil {
+		return nil, err
+	}
+	return game, nil
+}
+
+// DeleteGame deletes a game. A game with players is deleted only if force is
+// true, the players receive the reason
+func (c *Client) DeleteGame(id int, force bool, reason string) error {
+	query := url.Values{}
+	if force {
+		query.Set("force", "true")
+	}
+	if len(reason) > 0 {
+		query.Set("reason", reason)
+	}
+
+	path := "/games/" + strconv.Itoa(id)
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var response struct {
+		ID int `json:"id"`
+	}
+	return c.do(http.MethodDelete, path, nil, &response)
+}
+
+// Capacity returns capacity of the server
+func (c *Client) Capacity() (float32, error) {
+	var response struct {
+		Capacity float32 `json:"capacity"`
+	}
+	if err := c.do(http.MethodGet, "/capacity", nil, &response); err != nil {
+		return 0, err
+	}
+	return response.Capacity, nil
+}
+
+// Broadcast sends a message to all players in a game
+func (c *Client) Broadcast(id int, message string) (bool, error) {
+	var response struct {
+		Success bool `json:"success"`
+	}
+	err := c.do(http.MethodPost, "/games/"+strconv.Itoa(id)+"/broadcast", url.Values{
+		"message": {message},
+	}, &response)
+	if err != nil {
+		return false, err
+	}
+	return response.Success, nil
+}
+
+// Connection is a web-socket connection of a game returned by the API
+type Connection struct {
+	ID               int       `json:"id"`
+	RemoteAddr       string    `json:"remote_addr,omitempty"`
+	ConnectedSince   time.Time `json:"connected_since"`
+	SnakeID          uint32    `json:"snake_id"`
+	MessagesReceived uint64    `json:"messages_received"`
+	MessagesSent     uint64    `json:"messages_sent"`
+	MessagesDropped  uint64    `json:"messages_dropped"`
+	Resyncs          uint64    `json:"resyncs"`
+}
+
+// Connections returns web-socket connections of a game
+func (c *Client) Connections(id int) ([]Connection, error) {
+	var response struct {
+		Connections []Connection `json:"connections"`
+	}
+	if err := c.do(http.MethodGet, "/games/"+strconv.Itoa(id)+"/connections", nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Connections, nil
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/ivan1993spb/snake-server/config"
+)
+
+func runConfigCheck(args []string, stdout, stderr io.Writer) error {
+	f := newFlagSet("config check", "config check <path>", stderr)
+
+	if err := parseFlags(f, args); err != nil {
+		return err
+	}
+
+	path := f.Arg(0)
+	if len(path) == 0 {
+		var ok bool
+		if path, ok = config.FilePath(); !ok {
+			return usageError(f, "path to a config file is required")
+		}
+	}
+
+	input, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if _, err := config.CheckYAML(input); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "%s: ok\n", path)
+
+	return nil
+}
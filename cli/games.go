@@ -0,0 +1,186 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	defaultGameWidth  = 40
+	defaultGameHeight = 30
+	defaultGameLimit  = 10
+)
+
+func serverFlag(f *flag.FlagSet) *string {
+	return f.String("server", defaultServerURL, flagUsageServerURL)
+}
+
+func runGamesList(args []string, stdout, stderr io.Writer) error {
+	f := newFlagSet("games list", "games list [options]", stderr)
+	serverURL := serverFlag(f)
+
+	if err := parseFlags(f, args); err != nil {
+		return err
+	}
+
+	client, err := NewClient(*serverURL)
+	if err != nil {
+		return err
+	}
+
+	games, err := client.Games()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tPLAYERS\tWIDTH\tHEIGHT\tRATE")
+	for _, game := range games.Games {
+		fmt.Fprintf(w, "%d\t%d/%d\t%d\t%d\t%d\n", game.ID, game.Count, game.Limit, game.Width, game.Height, game.Rate)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "\n%d of %d games\n", games.Count, games.Limit)
+
+	return nil
+}
+
+func runGamesCreate(args []string, stdout, stderr io.Writer) error {
+	f := newFlagSet("games create", "games create [options]", stderr)
+	serverURL := serverFlag(f)
+	width := f.Uint("width", defaultGameWidth, "map width")
+	height := f.Uint("height", defaultGameHeight, "map height")
+	limit := f.Int("limit", defaultGameLimit, "players limit")
+	enableWalls := f.Bool("enable-walls", true, "generate walls on the map")
+
+	if err := parseFlags(f, args); err != nil {
+		return err
+	}
+
+	if *width > 255 || *height > 255 {
+		return usageError(f, "map width and height must not be greater than 255")
+	}
+
+	client, err := NewClient(*serverURL)
+	if err != nil {
+		return err
+	}
+
+	game, err := client.CreateGame(uint8(*width), uint8(*height), *limit, *enableWalls)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "game %d created: %dx%d, players limit %d\n", game.ID, game.Width, game.Height, game.Limit)
+
+	return nil
+}
+
+func runGamesDelete(args []string, stdout, stderr io.Writer) error {
+	f := newFlagSet("games delete", "games delete [options] <id>", stderr)
+	serverURL := serverFlag(f)
+	force := f.Bool("force", false, "close connections of players and delete the game")
+	reason := f.String("reason", "", "a reason to be sent to players if the game is force deleted")
+
+	if err := parseFlags(f, args); err != nil {
+		return err
+	}
+
+	id, err := gameIDArg(f)
+	if err != nil {
+		return err
+	}
+
+	client, err := NewClient(*serverURL)
+	if err != nil {
+		return err
+	}
+
+	if err := client.DeleteGame(id, *force, *reason); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "game %d deleted\n", id)
+
+	return nil
+}
+
+func runCapacity(args []string, stdout, stderr io.Writer) error {
+	f := newFlagSet("capacity", "capacity [options]", stderr)
+	serverURL := serverFlag(f)
+
+	if err := parseFlags(f, args); err != nil {
+		return err
+	}
+
+	client, err := NewClient(*serverURL)
+	if err != nil {
+		return err
+	}
+
+	capacity, err := client.Capacity()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "%.2f%%\n", capacity*100)
+
+	return nil
+}
+
+func runBroadcast(args []string, stdout, stderr io.Writer) error {
+	f := newFlagSet("broadcast", "broadcast [options] <id> <message>", stderr)
+	serverURL := serverFlag(f)
+
+	if err := parseFlags(f, args); err != nil {
+		return err
+	}
+
+	if f.NArg() < 2 {
+		return usageError(f, "game id and message are required")
+	}
+
+	id, err := gameIDArg(f)
+	if err != nil {
+		return err
+	}
+
+	message := strings.Join(f.Args()[1:], " ")
+
+	client, err := NewClient(*serverURL)
+	if err != nil {
+		return err
+	}
+
+	success, err := client.Broadcast(id, message)
+	if err != nil {
+		return err
+	}
+	if !success {
+		return fmt.Errorf("message has not been broadcasted")
+	}
+
+	fmt.Fprintln(stdout, "message broadcasted")
+
+	return nil
+}
+
+// gameIDArg returns the game identifier from the first positional argument
+func gameIDArg(f *flag.FlagSet) (int, error) {
+	if f.NArg() < 1 {
+		return 0, usageError(f, "game id is required")
+	}
+
+	id, err := strconv.Atoi(f.Arg(0))
+	if err != nil {
+		return 0, usageError(f, "invalid game id: "+f.Arg(0))
+	}
+
+	return id, nil
+}
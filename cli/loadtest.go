@@ -0,0 +1,434 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	defaultLoadTestConns    = 10
+	defaultLoadTestGames    = 1
+	defaultLoadTestDuration = time.Second * 30
+	defaultLoadTestRate     = 2
+)
+
+const (
+	loadTestPingInterval = time.Second
+	loadTestWriteTimeout = time.Second * 5
+	loadTestDeleteReason = "load test is finished"
+)
+
+var loadTestCommands = []string{"north", "east", "south", "west"}
+
+type loadTestOptions struct {
+	wsURLs   []string
+	conns    int
+	duration time.Duration
+	rampUp   time.Duration
+	rate     float64
+	script   []string
+}
+
+// loadTestStats collects results of load test players
+type loadTestStats struct {
+	attempted     int
+	established   int
+	failed        int
+	closedEarly   int
+	sent          uint64
+	received      uint64
+	bytesReceived uint64
+	connectTimes  []time.Duration
+	pingTimes     []time.Duration
+	errors        map[string]int
+	mux           sync.Mutex
+}
+
+func newLoadTestStats() *loadTestStats {
+	return &loadTestStats{
+		errors: map[string]int{},
+	}
+}
+
+func (s *loadTestStats) connected(d time.Duration) {
+	s.mux.Lock()
+	s.attempted++
+	s.established++
+	s.connectTimes = append(s.connectTimes, d)
+	s.mux.Unlock()
+}
+
+func (s *loadTestStats) connectFailed(err error) {
+	s.mux.Lock()
+	s.attempted++
+	s.failed++
+	s.errors[err.Error()]++
+	s.mux.Unlock()
+}
+
+func (s *loadTestStats) closed(err error) {
+	s.mux.Lock()
+	s.closedEarly++
+	s.errors[err.Error()]++
+	s.mux.Unlock()
+}
+
+func (s *loadTestStats) messageSent() {
+	s.mux.Lock()
+	s.sent++
+	s.mux.Unlock()
+}
+
+func (s *loadTestStats) messageReceived(size int) {
+	s.mux.Lock()
+	s.received++
+	s.bytesReceived += uint64(size)
+	s.mux.Unlock()
+}
+
+func (s *loadTestStats) pong(d time.Duration) {
+	s.mux.Lock()
+	s.pingTimes = append(s.pingTimes, d)
+	s.mux.Unlock()
+}
+
+// percentile returns the nearest-rank percentile of sorted durations
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(p/100*float64(len(sorted))+0.5) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
+
+func formatPercentiles(durations []time.Duration) string {
+	if len(durations) == 0 {
+		return "n/a"
+	}
+
+	sorted := make([]time.Duration, len(durations))
+	copy(sorted, durations)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	return fmt.Sprintf("p50 %s  p90 %s  p99 %s  max %s",
+		percentile(sorted, 50).Round(time.Microsecond),
+		percentile(sorted, 90).Round(time.Microsecond),
+		percentile(sorted, 99).Round(time.Microsecond),
+		sorted[len(sorted)-1].Round(time.Microsecond))
+}
+
+// report writes the results of a load test which took the given time
+func (s *loadTestStats) report(w io.Writer, elapsed time.Duration, dropped, resyncs uint64) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	seconds := elapsed.Seconds()
+	if seconds <= 0 {
+		seconds = 1
+	}
+
+	successRate := float64(0)
+	if s.attempted > 0 {
+		successRate = float64(s.established) / float64(s.attempted) * 100
+	}
+
+	fmt.Fprintf(w, "duration:        %s\n", elapsed.Round(time.Millisecond))
+	fmt.Fprintf(w, "connections:     %d attempted, %d established (%.2f%%), %d failed, %d closed early\n",
+		s.attempted, s.established, successRate, s.failed, s.closedEarly)
+	fmt.Fprintf(w, "messages sent:   %d (%.1f/s)\n", s.sent, float64(s.sent)/seconds)
+	fmt.Fprintf(w, "messages recv:   %d (%.1f/s, %.1f KiB/s)\n", s.received, float64(s.received)/seconds,
+		float64(s.bytesReceived)/1024/seconds)
+	fmt.Fprintf(w, "connect latency: %s\n", formatPercentiles(s.connectTimes))
+	fmt.Fprintf(w, "ping latency:    %s\n", formatPercentiles(s.pingTimes))
+	fmt.Fprintf(w, "server drops:    %d\n", dropped)
+	fmt.Fprintf(w, "server resyncs:  %d\n", resyncs)
+
+	if len(s.errors) > 0 {
+		texts := make([]string, 0, len(s.errors))
+		for text := range s.errors {
+			texts = append(texts, text)
+		}
+		sort.Strings(texts)
+
+		fmt.Fprintln(w, "errors:")
+		for _, text := range texts {
+			fmt.Fprintf(w, "  %6d %s\n", s.errors[text], text)
+		}
+	}
+}
+
+// readScript reads snake commands from a file, one command per line. Empty
+// lines and lines starting with # are skipped
+func readScript(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var script []string
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		script = append(script, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(script) == 0 {
+		return nil, fmt.Errorf("script %s has no commands", path)
+	}
+
+	return script, nil
+}
+
+// loadTestPlayer plays in a game until the stop channel is closed
+func loadTestPlayer(n int, wsURL string, opts loadTestOptions, stats *loadTestStats, stop <-chan struct{}) {
+	started := time.Now()
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err != nil {
+		stats.connectFailed(err)
+		return
+	}
+	defer conn.Close()
+	stats.connected(time.Since(started))
+
+	// Pings carry the time they were sent, pongs echo it back
+	conn.SetPongHandler(func(data string) error {
+		if sent, err := strconv.ParseInt(data, 10, 64); err == nil {
+			stats.pong(time.Since(time.Unix(0, sent)))
+		}
+		return nil
+	})
+
+	done := make(chan error, 1)
+
+	go func() {
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				done <- err
+				return
+			}
+			stats.messageReceived(len(data))
+		}
+	}()
+
+	random := rand.New(rand.NewSource(time.Now().UnixNano() + int64(n)))
+
+	commandTicker := time.NewTicker(time.Duration(float64(time.Second) / opts.rate))
+	defer commandTicker.Stop()
+
+	pingTicker := time.NewTicker(loadTestPingInterval)
+	defer pingTicker.Stop()
+
+	step := 0
+
+	for {
+		select {
+		case <-commandTicker.C:
+			var command string
+			if len(opts.script) > 0 {
+				command = opts.script[step%len(opts.script)]
+				step++
+			} else {
+				command = loadTestCommands[random.Intn(len(loadTestCommands))]
+			}
+
+			data, _ := json.Marshal(map[string]string{
+				"type":    "snake",
+				"payload": command,
+			})
+
+			conn.SetWriteDeadline(time.Now().Add(loadTestWriteTimeout))
+			if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				stats.closed(err)
+				return
+			}
+			stats.messageSent()
+		case <-pingTicker.C:
+			data := []byte(strconv.FormatInt(time.Now().UnixNano(), 10))
+			if err := conn.WriteControl(websocket.PingMessage, data, time.Now().Add(loadTestWriteTimeout)); err != nil {
+				stats.closed(err)
+				return
+			}
+		case err := <-done:
+			stats.closed(err)
+			return
+		case <-stop:
+			message := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+			conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(loadTestWriteTimeout))
+			return
+		}
+	}
+}
+
+func runLoadTest(args []string, stdout, stderr io.Writer) error {
+	f := newFlagSet("loadtest", "loadtest [options]", stderr)
+	serverURL := serverFlag(f)
+	conns := f.Int("conns", defaultLoadTestConns, "number of web-socket connections")
+	games := f.Int("games", defaultLoadTestGames, "number of games to create")
+	duration := f.Duration("duration", defaultLoadTestDuration, "duration of the test")
+	rampUp := f.Duration("ramp-up", 0, "period to open the connections evenly")
+	rate := f.Float64("rate", defaultLoadTestRate, "snake commands per second per connection")
+	scriptPath := f.String("script", "", "file with snake commands to play instead of random ones, one per line")
+	width := f.Uint("width", defaultGameWidth, "map width")
+	height := f.Uint("height", defaultGameHeight, "map height")
+	enableWalls := f.Bool("enable-walls", true, "generate walls on the map")
+
+	if err := parseFlags(f, args); err != nil {
+		return err
+	}
+
+	if *conns < 1 || *games < 1 {
+		return usageError(f, "conns and games must be positive")
+	}
+	if *games > *conns {
+		return usageError(f, "games must not be greater than conns")
+	}
+	if *duration <= 0 || *rampUp < 0 || *rampUp >= *duration {
+		return usageError(f, "duration must be positive and greater than ramp-up")
+	}
+	if *rate <= 0 {
+		return usageError(f, "rate must be positive")
+	}
+	if *width > 255 || *height > 255 {
+		return usageError(f, "map width and height must not be greater than 255")
+	}
+
+	opts := loadTestOptions{
+		conns:    *conns,
+		duration: *duration,
+		rampUp:   *rampUp,
+		rate:     *rate,
+	}
+
+	if *scriptPath != "" {
+		script, err := readScript(*scriptPath)
+		if err != nil {
+			return err
+		}
+		opts.script = script
+	}
+
+	client, err := NewClient(*serverURL)
+	if err != nil {
+		return err
+	}
+
+	// Connections are spread evenly across the games
+	limit := (*conns + *games - 1) / *games
+
+	var ids []int
+
+	defer func() {
+		for _, id := range ids {
+			if err := client.DeleteGame(id, true, loadTestDeleteReason); err != nil {
+				fmt.Fprintf(stderr, "cannot delete game %d: %s\n", id, err)
+			}
+		}
+	}()
+
+	for i := 0; i < *games; i++ {
+		game, err := client.CreateGame(uint8(*width), uint8(*height), limit, *enableWalls)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, game.ID)
+
+		wsURL, err := websocketURL(*serverURL, game.ID)
+		if err != nil {
+			return err
+		}
+		opts.wsURLs = append(opts.wsURLs, wsURL)
+	}
+
+	fmt.Fprintf(stdout, "load test: %d connections, %d games %v, %s\n", *conns, *games, ids, *duration)
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	stats := newLoadTestStats()
+	stop := make(chan struct{})
+	wg := &sync.WaitGroup{}
+	started := time.Now()
+	deadline := time.NewTimer(*duration)
+	defer deadline.Stop()
+
+	interval := opts.rampUp / time.Duration(opts.conns)
+	interrupted := false
+
+ramp:
+	for i := 0; i < opts.conns; i++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			loadTestPlayer(n, opts.wsURLs[n%len(opts.wsURLs)], opts, stats, stop)
+		}(i)
+
+		if interval > 0 {
+			select {
+			case <-time.After(interval):
+			case <-interrupt:
+				interrupted = true
+				break ramp
+			}
+		}
+	}
+
+	if !interrupted {
+		select {
+		case <-deadline.C:
+		case <-interrupt:
+		}
+	}
+
+	elapsed := time.Since(started)
+
+	// Drops are counted by the server while the connections are still open
+	var dropped, resyncs uint64
+	for _, id := range ids {
+		connections, err := client.Connections(id)
+		if err != nil {
+			fmt.Fprintf(stderr, "cannot get connections of game %d: %s\n", id, err)
+			continue
+		}
+		for _, connection := range connections {
+			dropped += connection.MessagesDropped
+			resyncs += connection.Resyncs
+		}
+	}
+
+	close(stop)
+	wg.Wait()
+
+	stats.report(stdout, elapsed, dropped, resyncs)
+
+	return nil
+}
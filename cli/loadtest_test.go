@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_percentile(t *testing.T) {
+	sorted := []time.Duration{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	require.Equal(t, time.Duration(5), percentile(sorted, 50))
+	require.Equal(t, time.Duration(9), percentile(sorted, 90))
+	require.Equal(t, time.Duration(10), percentile(sorted, 99))
+	require.Equal(t, time.Duration(1), percentile(sorted, 0))
+	require.Equal(t, time.Duration(0), percentile(nil, 50))
+}
+
+func Test_readScript_SkipsCommentsAndEmptyLines(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snake-loadtest")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "script.txt")
+	require.Nil(t, ioutil.WriteFile(path, []byte("# square\nnorth\n\n east \nsouth\nwest\n"), 0600))
+
+	script, err := readScript(path)
+	require.Nil(t, err)
+	require.Equal(t, []string{"north", "east", "south", "west"}, script)
+
+	empty := filepath.Join(dir, "empty.txt")
+	require.Nil(t, ioutil.WriteFile(empty, []byte("# nothing\n"), 0600))
+
+	_, err = readScript(empty)
+	require.NotNil(t, err)
+}
+
+func Test_Run_LoadTest(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	code := Run("snake-server", []string{"loadtest", "--server", server.URL,
+		"--conns", "4", "--games", "2", "--duration", "1500ms", "--rate", "10"}, stdout, stderr)
+	require.Equal(t, exitCodeOK, code, stderr.String())
+	require.Contains(t, stdout.String(), "4 attempted, 4 established (100.00%), 0 failed, 0 closed early")
+	require.Contains(t, stdout.String(), "server drops:    0")
+	require.Contains(t, stdout.String(), "server resyncs:  0")
+
+	// The games are deleted after the test
+	code = Run("snake-server", []string{"games", "list", "--server", server.URL}, stdout, stderr)
+	require.Equal(t, exitCodeOK, code)
+	require.Contains(t, stdout.String(), "0 of 10 games")
+
+	code = Run("snake-server", []string{"loadtest", "--conns", "1", "--games", "2"}, stdout, stderr)
+	require.Equal(t, exitCodeUsage, code)
+}
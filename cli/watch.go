@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	defaultWatchFPS = 10
+	maxWatchFPS     = 60
+)
+
+const clearScreen = "\x1b[H\x1b[2J"
+
+var errUnsupportedScheme = errors.New("unsupported URL scheme")
+
+// websocketURL returns the URL of the game's web-socket endpoint
+func websocketURL(serverURL string, id int) (string, error) {
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return "", err
+	}
+
+	switch u.Scheme {
+	case "http", "ws":
+		u.Scheme = "ws"
+	case "https", "wss":
+		u.Scheme = "wss"
+	default:
+		return "", errUnsupportedScheme
+	}
+
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/ws/games/" + strconv.Itoa(id)
+
+	return u.String(), nil
+}
+
+// spectatorURL returns the URL of the game's web-socket endpoint for a
+// spectator, which takes no place in the game
+func spectatorURL(serverURL string, id int) (string, error) {
+	wsURL, err := websocketURL(serverURL, id)
+	if err != nil {
+		return "", err
+	}
+
+	return wsURL + "?spectate=true", nil
+}
+
+func runWatch(args []string, stdout, stderr io.Writer) error {
+	f := newFlagSet("watch", "watch [options] <id>", stderr)
+	serverURL := serverFlag(f)
+	fps := f.Uint("fps", defaultWatchFPS, "frames per second")
+	noColor := f.Bool("no-color", false, "disable colours")
+
+	if err := parseFlags(f, args); err != nil {
+		return err
+	}
+
+	id, err := gameIDArg(f)
+	if err != nil {
+		return err
+	}
+
+	if *fps == 0 || *fps > maxWatchFPS {
+		return usageError(f, fmt.Sprintf("fps must be between 1 and %d", maxWatchFPS))
+	}
+
+	wsURL, err := spectatorURL(*serverURL, id)
+	if err != nil {
+		return err
+	}
+
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	a := newArena()
+	done := make(chan error, 1)
+
+	go func() {
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				done <- err
+				return
+			}
+			if err := a.apply(data); err != nil {
+				done <- err
+				return
+			}
+		}
+	}()
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	ticker := time.NewTicker(time.Second / time.Duration(*fps))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := io.WriteString(stdout, clearScreen); err != nil {
+				return err
+			}
+			if _, err := stdout.Write(a.render(!*noColor)); err != nil {
+				return err
+			}
+		case <-interrupt:
+			message := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+			return conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(time.Second))
+		case err := <-done:
+			if closeErr, ok := err.(*websocket.CloseError); ok {
+				fmt.Fprintf(stdout, "connection closed: %d %s\n", closeErr.Code, closeErr.Text)
+				return nil
+			}
+			return err
+		}
+	}
+}
@@ -6,6 +6,9 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"reflect"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/spf13/afero"
@@ -30,9 +33,23 @@ const (
 	defaultFlagsEnableWeb       = false
 	defaultFlagsForbidCORS      = false
 	defaultFlagsDebug           = false
+	defaultFlagsEnableAdmin     = false
 
 	defaultSentryEnable = false
 	defaultSentryDSN    = ""
+
+	defaultGRPCEnable  = false
+	defaultGRPCAddress = ":8081"
+
+	defaultWebhooksEnable = false
+	defaultWebhooksSecret = ""
+
+	defaultDrainPeriod = time.Second * 30
+
+	defaultTracingEnable      = false
+	defaultTracingEndpoint    = "localhost:4318"
+	defaultTracingInsecure    = false
+	defaultTracingSampleRatio = 1.0
 )
 
 // Flag labels
@@ -55,9 +72,25 @@ const (
 	flagLabelFlagsEnableWeb       = "enable-web"
 	flagLabelFlagsForbidCORS      = "forbid-cors"
 	flagLabelFlagsDebug           = "debug"
+	flagLabelFlagsEnableAdmin     = "enable-admin"
 
 	flagLabelSentryEnable = "sentry-enable"
 	flagLabelSentryDSN    = "sentry-dsn"
+
+	flagLabelGRPCEnable  = "grpc-enable"
+	flagLabelGRPCAddress = "grpc-address"
+
+	flagLabelWebhooksEnable = "webhooks-enable"
+	flagLabelWebhooksURLs   = "webhooks-urls"
+	flagLabelWebhooksSecret = "webhooks-secret"
+	flagLabelWebhooksHosts  = "webhooks-allowed-hosts"
+
+	flagLabelDrainPeriod = "drain-period"
+
+	flagLabelTracingEnable      = "tracing-enable"
+	flagLabelTracingEndpoint    = "tracing-endpoint"
+	flagLabelTracingInsecure    = "tracing-insecure"
+	flagLabelTracingSampleRatio = "tracing-sample-ratio"
 )
 
 // Flag usage descriptions
@@ -80,9 +113,25 @@ const (
 	flagUsageFlagsEnableWeb       = "enable web client"
 	flagUsageFlagsForbidCORS      = "forbid cross-origin resource sharing"
 	flagUsageFlagsDebug           = "enable profiling routes"
+	flagUsageFlagsEnableAdmin     = "enable admin API methods to list and kick connections"
 
 	flagUsageSentryEnable = "enable sending logs to sentry"
 	flagUsageSentryDSN    = "sentry's DSN"
+
+	flagUsageGRPCEnable  = "enable gRPC server"
+	flagUsageGRPCAddress = "address to serve gRPC"
+
+	flagUsageWebhooksEnable = "enable outgoing webhooks"
+	flagUsageWebhooksURLs   = "comma-separated list of webhook URLs to notify about all games"
+	flagUsageWebhooksSecret = "secret key to sign webhook requests"
+	flagUsageWebhooksHosts  = "comma-separated list of hosts which can be registered as webhooks of a game"
+
+	flagUsageDrainPeriod = "period to wait for games to finish on shutdown"
+
+	flagUsageTracingEnable      = "enable exporting of OpenTelemetry traces"
+	flagUsageTracingEndpoint    = "OTLP/HTTP endpoint to export traces to: host and port"
+	flagUsageTracingInsecure    = "export traces over plain HTTP instead of HTTPS"
+	flagUsageTracingSampleRatio = "ratio of traces to sample from 0 to 1"
 )
 
 // Label names
@@ -105,13 +154,31 @@ const (
 	fieldLabelFlagsEnableWeb       = "enable-web"
 	fieldLabelFlagsForbidCORS      = "forbid-cors"
 	fieldLabelFlagsDebug           = "debug"
+	fieldLabelFlagsEnableAdmin     = "enable-admin"
 
 	fieldLabelSentryEnable = "sentry-enable"
 	fieldLabelSentryDSN    = "sentry-dsn"
+
+	fieldLabelGRPCEnable  = "grpc-enable"
+	fieldLabelGRPCAddress = "grpc-address"
+
+	fieldLabelWebhooksEnable = "webhooks-enable"
+	fieldLabelWebhooksURLs   = "webhooks-urls"
+	fieldLabelWebhooksSecret = "webhooks-secret"
+	fieldLabelWebhooksHosts  = "webhooks-allowed-hosts"
+
+	fieldLabelDrainPeriod = "drain-period"
+
+	fieldLabelTracingEnable      = "tracing-enable"
+	fieldLabelTracingEndpoint    = "tracing-endpoint"
+	fieldLabelTracingInsecure    = "tracing-insecure"
+	fieldLabelTracingSampleRatio = "tracing-sample-ratio"
 )
 
 const envVarSnakeServerConfigPath = "SNAKE_SERVER_CONFIG_PATH"
 
+const envVarPrefix = "SNAKE_SERVER_"
+
 func generateSeed() int64 {
 	return time.Now().UnixNano()
 }
@@ -140,6 +207,7 @@ type Flags struct {
 	EnableWeb       bool `yaml:"enable_web"`
 	ForbidCORS      bool `yaml:"forbid_cors"`
 	Debug           bool `yaml:"debug"`
+	EnableAdmin     bool `yaml:"enable_admin"`
 }
 
 type Sentry struct {
@@ -147,6 +215,36 @@ type Sentry struct {
 	DSN    string `yaml:"dsn"`
 }
 
+// GRPC structure sets up the gRPC listener
+type GRPC struct {
+	Enable  bool   `yaml:"enable"`
+	Address string `yaml:"address"`
+}
+
+// Webhooks structure sets up outgoing webhooks
+type Webhooks struct {
+	Enable bool     `yaml:"enable"`
+	URLs   []string `yaml:"urls"`
+	Secret string   `yaml:"secret"`
+
+	// AllowedHosts are the hosts which can be registered as webhooks of a
+	// particular game. A host which starts with a dot matches subdomains
+	AllowedHosts []string `yaml:"allowed_hosts"`
+}
+
+// Drain structure sets up the shutdown of the server
+type Drain struct {
+	Period time.Duration `yaml:"period"`
+}
+
+// Tracing structure sets up exporting of OpenTelemetry traces
+type Tracing struct {
+	Enable      bool    `yaml:"enable"`
+	Endpoint    string  `yaml:"endpoint"`
+	Insecure    bool    `yaml:"insecure"`
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
+
 // Server structure contains configurations for the server
 type Server struct {
 	Address string `yaml:"address"`
@@ -159,6 +257,14 @@ type Server struct {
 	Flags Flags `yaml:"flags"`
 
 	Sentry `yaml:"sentry"`
+
+	GRPC GRPC `yaml:"grpc"`
+
+	Webhooks Webhooks `yaml:"webhooks"`
+
+	Drain Drain `yaml:"drain"`
+
+	Tracing Tracing `yaml:"tracing"`
 }
 
 // Config is a base server configuration structure
@@ -187,12 +293,65 @@ func (c Config) Fields() map[string]interface{} {
 		fieldLabelFlagsEnableWeb:       c.Server.Flags.EnableWeb,
 		fieldLabelFlagsForbidCORS:      c.Server.Flags.ForbidCORS,
 		fieldLabelFlagsDebug:           c.Server.Flags.Debug,
+		fieldLabelFlagsEnableAdmin:     c.Server.Flags.EnableAdmin,
 
 		fieldLabelSentryEnable: c.Server.Sentry.Enable,
 		fieldLabelSentryDSN:    c.Server.Sentry.DSN,
+
+		fieldLabelGRPCEnable:  c.Server.GRPC.Enable,
+		fieldLabelGRPCAddress: c.Server.GRPC.Address,
+
+		fieldLabelWebhooksEnable: c.Server.Webhooks.Enable,
+		fieldLabelWebhooksURLs:   c.Server.Webhooks.URLs,
+		fieldLabelWebhooksSecret: c.Server.Webhooks.Secret,
+		fieldLabelWebhooksHosts:  c.Server.Webhooks.AllowedHosts,
+
+		fieldLabelDrainPeriod: c.Server.Drain.Period,
+
+		fieldLabelTracingEnable:      c.Server.Tracing.Enable,
+		fieldLabelTracingEndpoint:    c.Server.Tracing.Endpoint,
+		fieldLabelTracingInsecure:    c.Server.Tracing.Insecure,
+		fieldLabelTracingSampleRatio: c.Server.Tracing.SampleRatio,
 	}
 }
 
+// reloadableFields are the fields which can be applied without a restart
+var reloadableFields = map[string]bool{
+	fieldLabelGroupsLimit:          true,
+	fieldLabelConnsLimit:           true,
+	fieldLabelLogLevel:             true,
+	fieldLabelFlagsForbidCORS:      true,
+	fieldLabelFlagsEnableBroadcast: true,
+	fieldLabelFlagsEnableAdmin:     true,
+	fieldLabelSentryEnable:         true,
+	fieldLabelSentryDSN:            true,
+	fieldLabelDrainPeriod:          true,
+}
+
+// Changes compares configs and returns sorted labels of the changed fields
+// which can be applied live and which require a restart of the server
+func Changes(current, next Config) (live, restart []string) {
+	currentFields := current.Fields()
+	nextFields := next.Fields()
+
+	for label, value := range currentFields {
+		if reflect.DeepEqual(value, nextFields[label]) {
+			continue
+		}
+
+		if reloadableFields[label] {
+			live = append(live, label)
+		} else {
+			restart = append(restart, label)
+		}
+	}
+
+	sort.Strings(live)
+	sort.Strings(restart)
+
+	return live, restart
+}
+
 var seed = generateSeed()
 
 // Default settings
@@ -223,12 +382,34 @@ var defaultConfig = Config{
 			EnableWeb:       defaultFlagsEnableWeb,
 			ForbidCORS:      defaultFlagsForbidCORS,
 			Debug:           defaultFlagsDebug,
+			EnableAdmin:     defaultFlagsEnableAdmin,
 		},
 
 		Sentry: Sentry{
 			Enable: defaultSentryEnable,
 			DSN:    defaultSentryDSN,
 		},
+
+		GRPC: GRPC{
+			Enable:  defaultGRPCEnable,
+			Address: defaultGRPCAddress,
+		},
+
+		Webhooks: Webhooks{
+			Enable: defaultWebhooksEnable,
+			Secret: defaultWebhooksSecret,
+		},
+
+		Drain: Drain{
+			Period: defaultDrainPeriod,
+		},
+
+		Tracing: Tracing{
+			Enable:      defaultTracingEnable,
+			Endpoint:    defaultTracingEndpoint,
+			Insecure:    defaultTracingInsecure,
+			SampleRatio: defaultTracingSampleRatio,
+		},
 	},
 }
 
@@ -256,6 +437,17 @@ func ParseFlags(flagSet *flag.FlagSet, args []string, defaults Config) (Config,
 
 	config := defaults
 
+	defineFlags(flagSet, &config, defaults)
+
+	if err := flagSet.Parse(args); err != nil {
+		return defaults, fmt.Errorf("cannot parse flags: %s", err)
+	}
+
+	return config, nil
+}
+
+// defineFlags defines flags for every field of the config
+func defineFlags(flagSet *flag.FlagSet, config *Config, defaults Config) {
 	// Address
 	flagSet.StringVar(&config.Server.Address, flagLabelAddress, defaults.Server.Address, flagUsageAddress)
 
@@ -300,13 +492,179 @@ func ParseFlags(flagSet *flag.FlagSet, args []string, defaults Config) (Config,
 		defaults.Server.Flags.Debug,
 		flagUsageFlagsDebug,
 	)
+	flagSet.BoolVar(
+		&config.Server.Flags.EnableAdmin,
+		flagLabelFlagsEnableAdmin,
+		defaults.Server.Flags.EnableAdmin,
+		flagUsageFlagsEnableAdmin,
+	)
 
 	// Sentry
 	flagSet.BoolVar(&config.Server.Sentry.Enable, flagLabelSentryEnable, defaults.Server.Sentry.Enable, flagUsageSentryEnable)
 	flagSet.StringVar(&config.Server.Sentry.DSN, flagLabelSentryDSN, defaults.Server.Sentry.DSN, flagUsageSentryDSN)
 
-	if err := flagSet.Parse(args); err != nil {
-		return defaults, fmt.Errorf("cannot parse flags: %s", err)
+	// gRPC
+	flagSet.BoolVar(&config.Server.GRPC.Enable, flagLabelGRPCEnable, defaults.Server.GRPC.Enable, flagUsageGRPCEnable)
+	flagSet.StringVar(&config.Server.GRPC.Address, flagLabelGRPCAddress, defaults.Server.GRPC.Address, flagUsageGRPCAddress)
+
+	// Webhooks
+	flagSet.BoolVar(&config.Server.Webhooks.Enable, flagLabelWebhooksEnable, defaults.Server.Webhooks.Enable, flagUsageWebhooksEnable)
+	flagSet.Var((*stringList)(&config.Server.Webhooks.URLs), flagLabelWebhooksURLs, flagUsageWebhooksURLs)
+	flagSet.StringVar(&config.Server.Webhooks.Secret, flagLabelWebhooksSecret, defaults.Server.Webhooks.Secret, flagUsageWebhooksSecret)
+	flagSet.Var((*stringList)(&config.Server.Webhooks.AllowedHosts), flagLabelWebhooksHosts, flagUsageWebhooksHosts)
+
+	// Drain
+	flagSet.DurationVar(&config.Server.Drain.Period, flagLabelDrainPeriod, defaults.Server.Drain.Period, flagUsageDrainPeriod)
+
+	// Tracing
+	flagSet.BoolVar(&config.Server.Tracing.Enable, flagLabelTracingEnable, defaults.Server.Tracing.Enable, flagUsageTracingEnable)
+	flagSet.StringVar(&config.Server.Tracing.Endpoint, flagLabelTracingEndpoint, defaults.Server.Tracing.Endpoint, flagUsageTracingEndpoint)
+	flagSet.BoolVar(&config.Server.Tracing.Insecure, flagLabelTracingInsecure, defaults.Server.Tracing.Insecure, flagUsageTracingInsecure)
+	flagSet.Float64Var(
+		&config.Server.Tracing.SampleRatio,
+		flagLabelTracingSampleRatio,
+		defaults.Server.Tracing.SampleRatio,
+		flagUsageTracingSampleRatio,
+	)
+}
+
+// EnvVarName returns the name of the environment variable for the flag
+func EnvVarName(flagLabel string) string {
+	return envVarPrefix + strings.ToUpper(strings.ReplaceAll(flagLabel, "-", "_"))
+}
+
+// ParseEnv parses environment variables in the form "key=value" and returns
+// a config based on the default configuration. Every flag has a variable
+// named by EnvVarName: for example, SNAKE_SERVER_CONNS_LIMIT for the flag
+// conns-limit
+func ParseEnv(environ []string, defaults Config) (Config, error) {
+	config := defaults
+
+	flagSet := flag.NewFlagSet(envVarPrefix, flag.ContinueOnError)
+	defineFlags(flagSet, &config, defaults)
+
+	env := make(map[string]string, len(environ))
+	for _, pair := range environ {
+		if i := strings.Index(pair, "="); i > 0 {
+			env[pair[:i]] = pair[i+1:]
+		}
+	}
+
+	var err error
+
+	flagSet.VisitAll(func(f *flag.Flag) {
+		if err != nil {
+			return
+		}
+
+		name := EnvVarName(f.Name)
+		if value, ok := env[name]; ok {
+			if errSet := flagSet.Set(f.Name, value); errSet != nil {
+				err = fmt.Errorf("cannot parse environment variable %s: %s", name, errSet)
+			}
+		}
+	})
+
+	if err != nil {
+		return defaults, err
+	}
+
+	return config, nil
+}
+
+// stringList is a flag value of a comma-separated list of strings
+type stringList []string
+
+func (l *stringList) String() string {
+	if l == nil {
+		return ""
+	}
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = stringList{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); len(item) > 0 {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
+
+var logLevels = map[string]bool{
+	"panic":   true,
+	"fatal":   true,
+	"error":   true,
+	"warning": true,
+	"warn":    true,
+	"info":    true,
+	"debug":   true,
+	"trace":   true,
+}
+
+type ErrInvalidConfig string
+
+func (e ErrInvalidConfig) Error() string {
+	return "invalid config: " + string(e)
+}
+
+// Validate returns an error if the config cannot be used to start the server
+func (c Config) Validate() error {
+	if len(c.Server.Address) == 0 {
+		return ErrInvalidConfig("empty address")
+	}
+
+	if c.Server.TLS.Enable && (len(c.Server.TLS.Cert) == 0 || len(c.Server.TLS.Key) == 0) {
+		return ErrInvalidConfig("TLS is enabled but a certificate or a key is not set")
+	}
+
+	if c.Server.Limits.Groups < 1 {
+		return ErrInvalidConfig("groups limit must be positive")
+	}
+
+	if c.Server.Limits.Conns < 1 {
+		return ErrInvalidConfig("connections limit must be positive")
+	}
+
+	if !logLevels[strings.ToLower(c.Server.Log.Level)] {
+		return ErrInvalidConfig(fmt.Sprintf("unknown log level %q", c.Server.Log.Level))
+	}
+
+	if c.Server.Sentry.Enable && len(c.Server.Sentry.DSN) == 0 {
+		return ErrInvalidConfig("sentry is enabled but DSN is not set")
+	}
+
+	if c.Server.GRPC.Enable && len(c.Server.GRPC.Address) == 0 {
+		return ErrInvalidConfig("gRPC is enabled but the address is empty")
+	}
+
+	if c.Server.Drain.Period < 0 {
+		return ErrInvalidConfig("negative drain period")
+	}
+
+	if c.Server.Tracing.Enable && len(c.Server.Tracing.Endpoint) == 0 {
+		return ErrInvalidConfig("tracing is enabled but the endpoint is empty")
+	}
+
+	if c.Server.Tracing.SampleRatio < 0 || c.Server.Tracing.SampleRatio > 1 {
+		return ErrInvalidConfig("tracing sample ratio must be between 0 and 1")
+	}
+
+	return nil
+}
+
+// CheckYAML parses the input strictly: unknown fields are reported. The
+// returned config is validated
+func CheckYAML(input []byte) (Config, error) {
+	config := DefaultConfig()
+
+	if err := yaml.UnmarshalStrict(input, &config); err != nil {
+		return DefaultConfig(), fmt.Errorf("cannot parse YAML: %s", err)
+	}
+
+	if err := config.Validate(); err != nil {
+		return config, err
 	}
 
 	return config, nil
@@ -335,6 +693,11 @@ func ReadYAMLConfig(r io.Reader, defaults Config) (Config, error) {
 	return config, nil
 }
 
+// FilePath returns the path to the config file if it is set
+func FilePath() (string, bool) {
+	return os.LookupEnv(envVarSnakeServerConfigPath)
+}
+
 type errConfigurate struct {
 	err error
 }
@@ -343,12 +706,14 @@ func (e *errConfigurate) Error() string {
 	return fmt.Sprintf("cannot configurate: %s", e.err)
 }
 
-// Configurate gathers a config from a config file and a flag set
+// Configurate gathers a config from a config file, environment variables and
+// a flag set. Flags override environment variables, environment variables
+// override the config file and the config file overrides defaults
 func Configurate(fs afero.Fs, flagSet *flag.FlagSet, args []string) (Config, error) {
 	defaults := DefaultConfig()
 	config := defaults
 
-	if configPath, ok := os.LookupEnv(envVarSnakeServerConfigPath); ok {
+	if configPath, ok := FilePath(); ok {
 		f, err := fs.Open(configPath)
 		if err != nil {
 			return defaults, &errConfigurate{err}
@@ -364,7 +729,12 @@ func Configurate(fs afero.Fs, flagSet *flag.FlagSet, args []string) (Config, err
 		}
 	}
 
-	config, err := ParseFlags(flagSet, args, config)
+	config, err := ParseEnv(os.Environ(), config)
+	if err != nil {
+		return defaults, &errConfigurate{err}
+	}
+
+	config, err = ParseFlags(flagSet, args, config)
 	if err != nil {
 		return defaults, &errConfigurate{err}
 	}
@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/require"
@@ -197,6 +198,95 @@ func Test_ParseFlags_ParsesFlagsCorrectly(t *testing.T) {
 		expectErr:    false,
 	})
 
+	// Test case 11
+	configTest11 := defaultConfig
+	configTest11.Server.GRPC.Enable = true
+	configTest11.Server.GRPC.Address = "localhost:9090"
+
+	tests = append(tests, &Test{
+		msg: "enable gRPC server and change its address",
+
+		args: []string{
+			"-grpc-enable",
+			"-grpc-address", "localhost:9090",
+		},
+		defaults: defaultConfig,
+
+		expectConfig: configTest11,
+		expectErr:    false,
+	})
+
+	// Test case 12
+	configTest12 := defaultConfig
+	configTest12.Server.Webhooks.Enable = true
+	configTest12.Server.Webhooks.URLs = []string{"https://example.com/a", "https://example.com/b"}
+	configTest12.Server.Webhooks.Secret = "secret"
+
+	tests = append(tests, &Test{
+		msg: "enable webhooks with a list of URLs and a secret",
+
+		args: []string{
+			"-webhooks-enable",
+			"-webhooks-urls", "https://example.com/a, https://example.com/b",
+			"-webhooks-secret", "secret",
+		},
+		defaults: defaultConfig,
+
+		expectConfig: configTest12,
+		expectErr:    false,
+	})
+
+	// Test case 13
+	configTest13 := defaultConfig
+	configTest13.Server.Drain.Period = time.Minute
+
+	tests = append(tests, &Test{
+		msg: "set drain period",
+
+		args: []string{
+			"-drain-period", "1m",
+		},
+		defaults: defaultConfig,
+
+		expectConfig: configTest13,
+		expectErr:    false,
+	})
+
+	// Test case 14
+	tests = append(tests, &Test{
+		msg: "invalid drain period",
+
+		args: []string{
+			"-drain-period", "minute",
+		},
+		defaults: defaultConfig,
+
+		expectConfig: defaultConfig,
+		expectErr:    true,
+	})
+
+	// Test case 15
+	configTest15 := defaultConfig
+	configTest15.Server.Tracing.Enable = true
+	configTest15.Server.Tracing.Endpoint = "collector:4318"
+	configTest15.Server.Tracing.Insecure = true
+	configTest15.Server.Tracing.SampleRatio = 0.5
+
+	tests = append(tests, &Test{
+		msg: "enable tracing",
+
+		args: []string{
+			"-tracing-enable",
+			"-tracing-endpoint", "collector:4318",
+			"-tracing-insecure",
+			"-tracing-sample-ratio", "0.5",
+		},
+		defaults: defaultConfig,
+
+		expectConfig: configTest15,
+		expectErr:    false,
+	})
+
 	for n, test := range tests {
 		t.Log(test.msg)
 
@@ -309,6 +399,70 @@ func Test_ParseYAML_ParsesYAMLCorrectly(t *testing.T) {
 		expectErr:    false,
 	})
 
+	// Test case 7
+	configTest7 := defaultConfig
+	configTest7.Server.GRPC.Enable = true
+	configTest7.Server.GRPC.Address = ":9090"
+
+	tests = append(tests, &Test{
+		msg: "gRPC settings",
+
+		input:    ConfigYAMLSampleGRPC,
+		defaults: defaultConfig,
+
+		expectConfig: configTest7,
+		expectErr:    false,
+	})
+
+	// Test case 8
+	configTest8 := defaultConfig
+	configTest8.Server.Webhooks.Enable = true
+	configTest8.Server.Webhooks.URLs = []string{"https://example.com/a", "https://example.com/b"}
+	configTest8.Server.Webhooks.Secret = "secret"
+
+	tests = append(tests, &Test{
+		msg: "webhooks settings",
+
+		input:    ConfigYAMLSampleWebhooks,
+		defaults: defaultConfig,
+
+		expectConfig: configTest8,
+		expectErr:    false,
+	})
+
+	// Test case 9
+	configTest9 := defaultConfig
+	configTest9.Server.Drain.Period = time.Second * 90
+
+	tests = append(tests, &Test{
+		msg: "drain settings",
+
+		input:    ConfigYAMLSampleDrain,
+		defaults: defaultConfig,
+
+		expectConfig: configTest9,
+		expectErr:    false,
+	})
+
+	// Test case 10
+	configTest10 := defaultConfig
+	configTest10.Server.Tracing = Tracing{
+		Enable:      true,
+		Endpoint:    "otel-collector:4318",
+		Insecure:    true,
+		SampleRatio: 0.25,
+	}
+
+	tests = append(tests, &Test{
+		msg: "tracing settings",
+
+		input:    ConfigYAMLSampleTracing,
+		defaults: defaultConfig,
+
+		expectConfig: configTest10,
+		expectErr:    false,
+	})
+
 	for n, test := range tests {
 		t.Log(test.msg)
 
@@ -325,6 +479,125 @@ func Test_ParseYAML_ParsesYAMLCorrectly(t *testing.T) {
 	}
 }
 
+func Test_ParseEnv_ParsesEnvCorrectly(t *testing.T) {
+	type Test struct {
+		msg string
+
+		environ  []string
+		defaults Config
+
+		expectConfig Config
+		expectErr    bool
+	}
+
+	var tests = make([]*Test, 0)
+
+	// Test case 1
+	tests = append(tests, &Test{
+		msg: "empty environment",
+
+		environ:  []string{},
+		defaults: defaultConfig,
+
+		expectConfig: defaultConfig,
+		expectErr:    false,
+	})
+
+	// Test case 2
+	tests = append(tests, &Test{
+		msg: "unrelated variables",
+
+		environ:  []string{"HOME=/root", "SNAKE_SERVER_CONFIG_PATH=/etc/snake.yaml", "SNAKE_SERVER_UNKNOWN=1", "INVALID"},
+		defaults: defaultConfig,
+
+		expectConfig: defaultConfig,
+		expectErr:    false,
+	})
+
+	// Test case 3
+	configTest3 := defaultConfig
+	configTest3.Server.Address = ":9999"
+	configTest3.Server.TLS.Enable = true
+	configTest3.Server.TLS.Cert = "/etc/path/cert"
+	configTest3.Server.Limits.Conns = 4123
+	configTest3.Server.Seed = 77
+	configTest3.Server.Log.Level = "debug"
+	configTest3.Server.Flags.ForbidCORS = true
+	configTest3.Server.Sentry.DSN = "https://public@sentry.example.com/1"
+	configTest3.Server.GRPC.Address = ":9998"
+	configTest3.Server.Webhooks.URLs = []string{"https://example.com/a", "https://example.com/b"}
+	configTest3.Server.Drain.Period = time.Minute
+
+	tests = append(tests, &Test{
+		msg: "override settings",
+
+		environ: []string{
+			"SNAKE_SERVER_ADDRESS=:9999",
+			"SNAKE_SERVER_TLS_ENABLE=true",
+			"SNAKE_SERVER_TLS_CERT=/etc/path/cert",
+			"SNAKE_SERVER_CONNS_LIMIT=4123",
+			"SNAKE_SERVER_SEED=77",
+			"SNAKE_SERVER_LOG_LEVEL=debug",
+			"SNAKE_SERVER_FORBID_CORS=1",
+			"SNAKE_SERVER_SENTRY_DSN=https://public@sentry.example.com/1",
+			"SNAKE_SERVER_GRPC_ADDRESS=:9998",
+			"SNAKE_SERVER_WEBHOOKS_URLS=https://example.com/a,https://example.com/b",
+			"SNAKE_SERVER_DRAIN_PERIOD=1m",
+		},
+		defaults: defaultConfig,
+
+		expectConfig: configTest3,
+		expectErr:    false,
+	})
+
+	// Test case 4
+	tests = append(tests, &Test{
+		msg: "invalid integer",
+
+		environ:  []string{"SNAKE_SERVER_GROUPS_LIMIT=many"},
+		defaults: defaultConfig,
+
+		expectConfig: defaultConfig,
+		expectErr:    true,
+	})
+
+	// Test case 5
+	tests = append(tests, &Test{
+		msg: "invalid boolean",
+
+		environ:  []string{"SNAKE_SERVER_ENABLE_WEB=yes please"},
+		defaults: defaultConfig,
+
+		expectConfig: defaultConfig,
+		expectErr:    true,
+	})
+
+	for n, test := range tests {
+		t.Log(test.msg)
+
+		label := fmt.Sprintf("case number %d", n+1)
+
+		config, err := ParseEnv(test.environ, test.defaults)
+
+		if test.expectErr {
+			require.NotNil(t, err, label)
+		} else {
+			require.Nil(t, err, label)
+		}
+		require.Equal(t, test.expectConfig, config, label)
+	}
+}
+
+func Test_ParseEnv_DefinesVariableForEveryField(t *testing.T) {
+	config := defaultConfig
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	defineFlags(flagSet, &config, defaultConfig)
+
+	for label := range defaultConfig.Fields() {
+		require.NotNil(t, flagSet.Lookup(label), "no variable for field %s", label)
+	}
+}
+
 func Test_Config_Fields_ReturnsFieldsOfTheConfig(t *testing.T) {
 	require.Equal(t, map[string]interface{}{
 		fieldLabelAddress: ":9999",
@@ -345,9 +618,25 @@ func Test_Config_Fields_ReturnsFieldsOfTheConfig(t *testing.T) {
 		fieldLabelFlagsEnableWeb:       false,
 		fieldLabelFlagsForbidCORS:      true,
 		fieldLabelFlagsDebug:           true,
+		fieldLabelFlagsEnableAdmin:     true,
 
 		fieldLabelSentryEnable: true,
 		fieldLabelSentryDSN:    "https://public@sentry.example.com/1",
+
+		fieldLabelGRPCEnable:  true,
+		fieldLabelGRPCAddress: ":9998",
+
+		fieldLabelWebhooksEnable: true,
+		fieldLabelWebhooksURLs:   []string{"https://example.com/hook"},
+		fieldLabelWebhooksSecret: "secret",
+		fieldLabelWebhooksHosts:  []string{"example.com"},
+
+		fieldLabelDrainPeriod: time.Second * 10,
+
+		fieldLabelTracingEnable:      true,
+		fieldLabelTracingEndpoint:    "localhost:4318",
+		fieldLabelTracingInsecure:    true,
+		fieldLabelTracingSampleRatio: 0.1,
 	}, Config{
 		Server: Server{
 			Address: ":9999",
@@ -375,16 +664,260 @@ func Test_Config_Fields_ReturnsFieldsOfTheConfig(t *testing.T) {
 				EnableWeb:       false,
 				ForbidCORS:      true,
 				Debug:           true,
+				EnableAdmin:     true,
 			},
 
 			Sentry: Sentry{
 				Enable: true,
 				DSN:    "https://public@sentry.example.com/1",
 			},
+
+			GRPC: GRPC{
+				Enable:  true,
+				Address: ":9998",
+			},
+
+			Webhooks: Webhooks{
+				Enable:       true,
+				URLs:         []string{"https://example.com/hook"},
+				Secret:       "secret",
+				AllowedHosts: []string{"example.com"},
+			},
+
+			Drain: Drain{
+				Period: time.Second * 10,
+			},
+
+			Tracing: Tracing{
+				Enable:      true,
+				Endpoint:    "localhost:4318",
+				Insecure:    true,
+				SampleRatio: 0.1,
+			},
 		},
 	}.Fields())
 }
 
+func Test_Changes_SplitsLiveAndRestartChanges(t *testing.T) {
+	type Test struct {
+		msg string
+
+		current Config
+		next    Config
+
+		expectLive    []string
+		expectRestart []string
+	}
+
+	var tests = make([]*Test, 0)
+
+	// Test case 1
+	tests = append(tests, &Test{
+		msg: "no changes",
+
+		current: defaultConfig,
+		next:    defaultConfig,
+
+		expectLive:    nil,
+		expectRestart: nil,
+	})
+
+	// Test case 2
+	configTest2 := defaultConfig
+	configTest2.Server.Log.Level = "debug"
+	configTest2.Server.Limits.Conns = 10
+	configTest2.Server.Flags.ForbidCORS = true
+	configTest2.Server.Flags.EnableBroadcast = true
+	configTest2.Server.Flags.EnableAdmin = true
+
+	tests = append(tests, &Test{
+		msg: "live changes",
+
+		current: defaultConfig,
+		next:    configTest2,
+
+		expectLive: []string{fieldLabelConnsLimit, fieldLabelFlagsEnableAdmin, fieldLabelFlagsEnableBroadcast,
+			fieldLabelFlagsForbidCORS, fieldLabelLogLevel},
+		expectRestart: nil,
+	})
+
+	// Test case 3
+	configTest3 := defaultConfig
+	configTest3.Server.Address = ":9999"
+	configTest3.Server.Sentry.DSN = "https://public@sentry.example.com/1"
+	configTest3.Server.Webhooks.URLs = []string{"https://example.com/hook"}
+
+	tests = append(tests, &Test{
+		msg: "live and restart changes",
+
+		current: defaultConfig,
+		next:    configTest3,
+
+		expectLive:    []string{fieldLabelSentryDSN},
+		expectRestart: []string{fieldLabelAddress, fieldLabelWebhooksURLs},
+	})
+
+	for n, test := range tests {
+		t.Log(test.msg)
+
+		label := fmt.Sprintf("case number %d", n+1)
+
+		live, restart := Changes(test.current, test.next)
+
+		require.Equal(t, test.expectLive, live, label)
+		require.Equal(t, test.expectRestart, restart, label)
+	}
+}
+
+func Test_Config_Validate(t *testing.T) {
+	type Test struct {
+		msg string
+
+		config Config
+
+		expectErr bool
+	}
+
+	var tests = make([]*Test, 0)
+
+	// Test case 1
+	tests = append(tests, &Test{
+		msg: "default config",
+
+		config: defaultConfig,
+
+		expectErr: false,
+	})
+
+	// Test case 2
+	configTest2 := defaultConfig
+	configTest2.Server.TLS.Enable = true
+	configTest2.Server.TLS.Cert = "path/to/cert"
+
+	tests = append(tests, &Test{
+		msg: "TLS without a key",
+
+		config: configTest2,
+
+		expectErr: true,
+	})
+
+	// Test case 3
+	configTest3 := defaultConfig
+	configTest3.Server.Limits.Conns = 0
+
+	tests = append(tests, &Test{
+		msg: "zero connections limit",
+
+		config: configTest3,
+
+		expectErr: true,
+	})
+
+	// Test case 4
+	configTest4 := defaultConfig
+	configTest4.Server.Log.Level = "verbose"
+
+	tests = append(tests, &Test{
+		msg: "unknown log level",
+
+		config: configTest4,
+
+		expectErr: true,
+	})
+
+	// Test case 5
+	configTest5 := defaultConfig
+	configTest5.Server.Log.Level = "WARN"
+	configTest5.Server.Sentry.Enable = true
+	configTest5.Server.Sentry.DSN = "https://public@sentry.example.com/1"
+
+	tests = append(tests, &Test{
+		msg: "upper case log level and sentry",
+
+		config: configTest5,
+
+		expectErr: false,
+	})
+
+	// Test case 6
+	configTest6 := defaultConfig
+	configTest6.Server.Sentry.Enable = true
+
+	tests = append(tests, &Test{
+		msg: "sentry without DSN",
+
+		config: configTest6,
+
+		expectErr: true,
+	})
+
+	// Test case 7
+	configTest7 := defaultConfig
+	configTest7.Server.Drain.Period = -time.Second
+
+	tests = append(tests, &Test{
+		msg: "negative drain period",
+
+		config: configTest7,
+
+		expectErr: true,
+	})
+
+	// Test case 8
+	configTest8 := defaultConfig
+	configTest8.Server.Tracing.Enable = true
+	configTest8.Server.Tracing.Endpoint = ""
+
+	tests = append(tests, &Test{
+		msg: "tracing without endpoint",
+
+		config: configTest8,
+
+		expectErr: true,
+	})
+
+	// Test case 9
+	configTest9 := defaultConfig
+	configTest9.Server.Tracing.SampleRatio = 1.5
+
+	tests = append(tests, &Test{
+		msg: "invalid tracing sample ratio",
+
+		config: configTest9,
+
+		expectErr: true,
+	})
+
+	for n, test := range tests {
+		t.Log(test.msg)
+
+		label := fmt.Sprintf("case number %d", n+1)
+
+		err := test.config.Validate()
+
+		if test.expectErr {
+			require.NotNil(t, err, label)
+		} else {
+			require.Nil(t, err, label)
+		}
+	}
+}
+
+func Test_CheckYAML(t *testing.T) {
+	_, err := CheckYAML(ConfigYAMLSampleAddressAndTLSAndLimits)
+	require.Nil(t, err)
+
+	_, err = CheckYAML(ConfigYAMLSampleUnknownField)
+	require.NotNil(t, err)
+
+	_, err = CheckYAML(ConfigYAMLSampleBullshitSyntax)
+	require.NotNil(t, err)
+
+	_, err = CheckYAML(ConfigYAMLSampleInvalidLimits)
+	require.NotNil(t, err)
+}
+
 func Test_ReadYAMLConfig_ReadsConfigCorrectly(t *testing.T) {
 	type Test struct {
 		msg string
@@ -524,6 +1057,8 @@ func Test_Configurate_ReturnsCorrectConfig(t *testing.T) {
 
 		setEnv     bool
 		saveConfig bool
+
+		env map[string]string
 	}
 
 	var tests = make([]*Test, 0)
@@ -650,11 +1185,64 @@ func Test_Configurate_ReturnsCorrectConfig(t *testing.T) {
 		saveConfig: true,
 	})
 
+	// Test case 7
+	configTest7 := defaultConfig
+	configTest7.Server.Address = ":7777"
+	configTest7.Server.TLS.Enable = true
+	configTest7.Server.TLS.Cert = "path/to/cert"
+	configTest7.Server.TLS.Key = "path/to/key"
+	configTest7.Server.Limits.Groups = 422
+	configTest7.Server.Limits.Conns = 10
+	configTest7.Server.Flags.EnableBroadcast = true
+
+	tests = append(tests, &Test{
+		msg: "environment variables override file config, flags override environment variables",
+
+		input: ConfigYAMLSampleAddressAndTLSAndLimits,
+		args: []string{
+			"-groups-limit", "422",
+		},
+
+		expectConfig: configTest7,
+		expectErr:    false,
+
+		setEnv:     true,
+		saveConfig: true,
+
+		env: map[string]string{
+			"SNAKE_SERVER_ADDRESS":      ":7777",
+			"SNAKE_SERVER_GROUPS_LIMIT": "10",
+			"SNAKE_SERVER_CONNS_LIMIT":  "10",
+		},
+	})
+
+	// Test case 8
+	tests = append(tests, &Test{
+		msg: "environment variable has an invalid value",
+
+		input: ConfigYAMLSampleDefault,
+		args:  []string{},
+
+		expectConfig: defaultConfig,
+		expectErr:    true,
+
+		setEnv:     false,
+		saveConfig: true,
+
+		env: map[string]string{
+			"SNAKE_SERVER_CONNS_LIMIT": "many",
+		},
+	})
+
 	for n, test := range tests {
 		t.Log(test.msg)
 
 		label := fmt.Sprintf("case number %d", n+1)
 
+		for name, value := range test.env {
+			require.Nil(t, os.Setenv(name, value), label)
+		}
+
 		fs := afero.NewMemMapFs()
 
 		if test.saveConfig {
@@ -683,5 +1271,9 @@ func Test_Configurate_ReturnsCorrectConfig(t *testing.T) {
 			err := os.Unsetenv(envVarSnakeServerConfigPath)
 			require.Nil(t, err)
 		}
+
+		for name := range test.env {
+			require.Nil(t, os.Unsetenv(name), label)
+		}
 	}
 }
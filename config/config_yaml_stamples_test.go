@@ -85,3 +85,48 @@ server:
   flags:
     debug: True
 `)
+
+var ConfigYAMLSampleGRPC = []byte(`
+server:
+  grpc:
+    enable: True
+    address: :9090
+`)
+
+var ConfigYAMLSampleWebhooks = []byte(`
+server:
+  webhooks:
+    enable: True
+    urls:
+      - https://example.com/a
+      - https://example.com/b
+    secret: secret
+`)
+
+var ConfigYAMLSampleDrain = []byte(`
+server:
+  drain:
+    period: 1m30s
+`)
+
+var ConfigYAMLSampleTracing = []byte(`
+server:
+  tracing:
+    enable: true
+    endpoint: otel-collector:4318
+    insecure: true
+    sample_ratio: 0.25
+`)
+
+var ConfigYAMLSampleUnknownField = []byte(`
+server:
+  address: :9999
+  limit:
+    groups: 10
+`)
+
+var ConfigYAMLSampleInvalidLimits = []byte(`
+server:
+  limits:
+    groups: 0
+`)
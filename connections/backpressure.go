@@ -0,0 +1,219 @@
+package connections
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/pquerna/ffjson/ffjson"
+
+	"github.com/ivan1993spb/snake-server/player"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+// Backpressure policy of a connection. Game messages are queued for writing
+// until the write queue is too deep. Then the connection is lagging: game
+// messages are discarded until the client reads the queue, and the client
+// gets a snapshot of all objects instead of the discarded messages. Game
+// messages sent by the group before the snapshot are discarded, wherever
+// they are queued. A connection which lags too often or does not catch up in
+// time is closed with the code CloseSlowConnection
+const (
+	chanWriteMessageBuffer = 1024
+
+	connectionLagThreshold    = 512
+	connectionResyncThreshold = 64
+	connectionResyncTimeout   = time.Second * 5
+
+	connectionResyncsLimit  = 3
+	connectionResyncsPeriod = time.Minute
+
+	backpressureCheckInterval = time.Millisecond * 100
+)
+
+// CloseSlowConnection is the close code of web-socket connections which
+// cannot keep up with the game
+const CloseSlowConnection = 4000
+
+const slowConnectionReason = "connection is too slow"
+
+const (
+	backpressureActionLag        = "lag"
+	backpressureActionResync     = "resync"
+	backpressureActionDisconnect = "disconnect"
+)
+
+// backpressure merges player messages and game messages into the write queue.
+// Player messages are always queued, game messages are subject to the
+// backpressure policy
+func (cw *ConnectionWorker) backpressure(stop <-chan struct{}, chPlayer <-chan *websocket.PreparedMessage, chGame <-chan groupMessage, w world.Interface) <-chan *websocket.PreparedMessage {
+	chout := make(chan *websocket.PreparedMessage, chanWriteMessageBuffer)
+
+	go func() {
+		defer close(chout)
+
+		ticker := time.NewTicker(backpressureCheckInterval)
+		defer ticker.Stop()
+
+		var (
+			lagging      bool
+			laggingSince time.Time
+			disconnected bool
+			resyncs      []time.Time
+
+			// snapshotSeq is the sequence number of the last game message
+			// which had been sent by the group before the last snapshot
+			snapshotSeq uint64
+		)
+
+		send := func(pm *websocket.PreparedMessage) bool {
+			select {
+			case chout <- pm:
+				return true
+			case <-stop:
+				return false
+			}
+		}
+
+		disconnect := func() {
+			cw.logger.WithField("queue", len(chout)).Warn("disconnect slow connection")
+			cw.metrics.observeBackpressure(backpressureActionDisconnect)
+			cw.closeConnection(player.NewMessageNotice(slowConnectionReason), CloseSlowConnection, slowConnectionReason)
+			disconnected = true
+		}
+
+		lag := func() {
+			resyncs = recentTimes(resyncs, connectionResyncsPeriod)
+			if len(resyncs) >= connectionResyncsLimit {
+				disconnect()
+				return
+			}
+
+			cw.logger.WithField("queue", len(chout)).Warn("connection is lagging: discard game messages")
+			cw.metrics.observeBackpressure(backpressureActionLag)
+			lagging = true
+			laggingSince = time.Now()
+		}
+
+		for chPlayer != nil || chGame != nil {
+			select {
+			case pm, ok := <-chPlayer:
+				if !ok {
+					chPlayer = nil
+					continue
+				}
+				if !send(pm) {
+					return
+				}
+			case message, ok := <-chGame:
+				if !ok {
+					chGame = nil
+					continue
+				}
+
+				// Game messages sent before the snapshot are out of date
+				if lagging || disconnected || message.seq <= snapshotSeq {
+					cw.discard(1)
+					continue
+				}
+
+				if len(chout) < connectionLagThreshold {
+					if !send(message.pm) {
+						return
+					}
+					continue
+				}
+
+				cw.discard(1)
+				lag()
+			case <-ticker.C:
+			case <-stop:
+				return
+			}
+
+			if disconnected {
+				continue
+			}
+
+			// A game message has been lost before it reached the queue
+			if atomic.SwapUint32(&cw.skipped, 0) == 1 && !lagging {
+				lag()
+				if disconnected {
+					continue
+				}
+			}
+
+			if !lagging {
+				continue
+			}
+
+			if len(chout) > connectionResyncThreshold {
+				if time.Since(laggingSince) > connectionResyncTimeout {
+					disconnect()
+				}
+				continue
+			}
+
+			// The sequence number is taken before the objects, so messages
+			// of the group which come after the boundary are not lost
+			seq := cw.groupSeq()
+
+			pm, err := cw.snapshot(w)
+			if err != nil {
+				cw.logger.Errorln("prepare snapshot error:", err)
+				disconnect()
+				continue
+			}
+
+			if !send(pm) {
+				return
+			}
+
+			snapshotSeq = seq
+			lagging = false
+			resyncs = append(resyncs, time.Now())
+			atomic.AddUint64(&cw.resyncs, 1)
+			cw.metrics.observeBackpressure(backpressureActionResync)
+			cw.logger.WithField("lagged", time.Since(laggingSince)).Info("connection is resynchronized")
+		}
+	}()
+
+	return chout
+}
+
+// discard counts game messages which are not sent to the client
+func (cw *ConnectionWorker) discard(n uint64) {
+	atomic.AddUint64(&cw.messagesDropped, n)
+	cw.metrics.observeDiscard(n)
+}
+
+// skip counts a game message which has not reached the backpressure stage.
+// The connection lags, so that the client gets a snapshot instead of it
+func (cw *ConnectionWorker) skip() {
+	cw.discard(1)
+	atomic.StoreUint32(&cw.skipped, 1)
+}
+
+// snapshot returns a player message with all objects of the world
+func (cw *ConnectionWorker) snapshot(w world.Interface) (*websocket.PreparedMessage, error) {
+	data, err := ffjson.Marshal(OutputMessage{
+		Type:    OutputMessageTypePlayer,
+		Payload: player.NewMessageObjects(w.GetObjects()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return websocket.NewPreparedMessage(websocket.TextMessage, data)
+}
+
+// recentTimes returns the times which are within the period until now
+func recentTimes(times []time.Time, period time.Duration) []time.Time {
+	recent := times[:0]
+	for _, t := range times {
+		if time.Since(t) < period {
+			recent = append(recent, t)
+		}
+	}
+	return recent
+}
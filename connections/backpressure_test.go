@@ -0,0 +1,201 @@
+package connections
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+func Test_ConnectionWorker_backpressure_ResyncsAndDisconnectsSlowConnection(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	w, err := world.NewWorld(10, 10)
+	require.Nil(t, err)
+
+	var seq uint64
+
+	cw := &ConnectionWorker{
+		logger:       logger,
+		disconnect:   make(chan struct{}),
+		disconnector: &sync.Once{},
+		groupSeq: func() uint64 {
+			return seq
+		},
+	}
+
+	pm, err := websocket.NewPreparedMessage(websocket.TextMessage, []byte(`{"type":"game"}`))
+	require.Nil(t, err)
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	chPlayer := make(chan *websocket.PreparedMessage)
+	chGame := make(chan groupMessage)
+	chout := cw.backpressure(stop, chPlayer, chGame, w)
+
+	// lag fills the write queue and sends one message more than the
+	// queue takes
+	lag := func() {
+		for i := 0; i <= connectionLagThreshold; i++ {
+			seq++
+			chGame <- groupMessage{seq: seq, pm: pm}
+		}
+	}
+
+	// drain reads the write queue and returns the first message which is
+	// not a game message
+	drain := func() *websocket.PreparedMessage {
+		timeout := time.After(time.Second)
+		for {
+			select {
+			case message := <-chout:
+				if message != pm {
+					return message
+				}
+			case <-timeout:
+				return nil
+			}
+		}
+	}
+
+	for i := 0; i < connectionResyncsLimit; i++ {
+		lag()
+
+		// Messages are discarded while the connection is lagging
+		seq++
+		chGame <- groupMessage{seq: seq, pm: pm}
+		require.Eventually(t, func() bool {
+			return atomic.LoadUint64(&cw.messagesDropped) == uint64((i+1)*2)
+		}, time.Second, time.Millisecond)
+
+		require.NotNil(t, drain(), "snapshot has not been sent")
+		require.Equal(t, uint64(i+1), atomic.LoadUint64(&cw.resyncs))
+	}
+
+	select {
+	case <-cw.disconnect:
+		t.Fatal("connection is disconnected too early")
+	default:
+	}
+
+	lag()
+
+	select {
+	case <-cw.disconnect:
+	case <-time.After(time.Second):
+		t.Fatal("slow connection has not been disconnected")
+	}
+	require.Equal(t, CloseSlowConnection, cw.disconnectCode)
+}
+
+func Test_ConnectionWorker_backpressure_DeliversPlayerMessages(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	w, err := world.NewWorld(10, 10)
+	require.Nil(t, err)
+
+	var seq uint64
+
+	cw := &ConnectionWorker{
+		logger:       logger,
+		disconnect:   make(chan struct{}),
+		disconnector: &sync.Once{},
+		groupSeq: func() uint64 {
+			return seq
+		},
+	}
+
+	gamePM, err := websocket.NewPreparedMessage(websocket.TextMessage, []byte(`{"type":"game"}`))
+	require.Nil(t, err)
+	playerPM, err := websocket.NewPreparedMessage(websocket.TextMessage, []byte(`{"type":"player"}`))
+	require.Nil(t, err)
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	chPlayer := make(chan *websocket.PreparedMessage)
+	chGame := make(chan groupMessage)
+	chout := cw.backpressure(stop, chPlayer, chGame, w)
+
+	for i := 0; i <= connectionLagThreshold; i++ {
+		seq++
+		chGame <- groupMessage{seq: seq, pm: gamePM}
+	}
+	chPlayer <- playerPM
+
+	for i := 0; i < connectionLagThreshold; i++ {
+		require.Equal(t, gamePM, <-chout)
+	}
+	require.Equal(t, playerPM, <-chout)
+}
+
+func Test_ConnectionWorker_backpressure_ResyncsOnSkippedMessage(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	w, err := world.NewWorld(10, 10)
+	require.Nil(t, err)
+
+	const snapshotSeq = 10
+
+	cw := &ConnectionWorker{
+		logger:       logger,
+		disconnect:   make(chan struct{}),
+		disconnector: &sync.Once{},
+		groupSeq: func() uint64 {
+			return snapshotSeq
+		},
+	}
+
+	pm, err := websocket.NewPreparedMessage(websocket.TextMessage, []byte(`{"type":"game"}`))
+	require.Nil(t, err)
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	chPlayer := make(chan *websocket.PreparedMessage)
+	chGame := make(chan groupMessage)
+	chout := cw.backpressure(stop, chPlayer, chGame, w)
+
+	cw.skip()
+
+	select {
+	case message := <-chout:
+		require.NotEqual(t, pm, message, "snapshot has not been sent")
+	case <-time.After(time.Second):
+		t.Fatal("snapshot has not been sent")
+	}
+	require.Equal(t, uint64(1), atomic.LoadUint64(&cw.resyncs))
+
+	// Messages which have been sent by the group before the snapshot are
+	// discarded wherever they were queued
+	chGame <- groupMessage{seq: snapshotSeq, pm: pm}
+	next, err := websocket.NewPreparedMessage(websocket.TextMessage, []byte(`{"type":"game"}`))
+	require.Nil(t, err)
+	chGame <- groupMessage{seq: snapshotSeq + 1, pm: next}
+
+	select {
+	case message := <-chout:
+		require.Equal(t, next, message)
+	case <-time.After(time.Second):
+		t.Fatal("message after the snapshot has not been sent")
+	}
+	require.Equal(t, uint64(2), atomic.LoadUint64(&cw.messagesDropped))
+}
+
+func Test_recentTimes(t *testing.T) {
+	now := time.Now()
+	times := []time.Time{now.Add(-time.Hour), now.Add(-time.Second), now}
+
+	require.Equal(t, []time.Time{now.Add(-time.Second), now}, recentTimes(times, time.Minute))
+	require.Empty(t, recentTimes(nil, time.Minute))
+}
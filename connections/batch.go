@@ -0,0 +1,122 @@
+package connections
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// A batch is sent before the window expires if it is too big
+	batchMaxMessages = 512
+	batchMaxSize     = 1 << 16
+
+	batchedMessageBufferMonitoringDelay = time.Second * 30
+)
+
+// joinMessages joins encoded messages into a JSON array. The size is the
+// total length of the messages
+func joinMessages(messages [][]byte, size int) []byte {
+	data := make([]byte, 0, size+len(messages)+1)
+
+	data = append(data, '[')
+	for i, message := range messages {
+		if i > 0 {
+			data = append(data, ',')
+		}
+		data = append(data, message...)
+	}
+	data = append(data, ']')
+
+	return data
+}
+
+// batch collects encoded messages which arrive within the window after the
+// first message of a batch and sends them as a single JSON array
+func (cg *ConnectionGroup) batch(stop <-chan struct{}, chin <-chan []byte, window time.Duration) <-chan []byte {
+	chout := make(chan []byte, cap(chin))
+
+	go func() {
+		defer close(chout)
+
+		ticker := time.NewTicker(batchedMessageBufferMonitoringDelay)
+		defer ticker.Stop()
+
+		var (
+			messages = make([][]byte, 0, batchMaxMessages)
+			size     = 0
+			timer    *time.Timer
+			chTimer  <-chan time.Time
+			count    = 0
+		)
+
+		defer func() {
+			if timer != nil {
+				timer.Stop()
+			}
+		}()
+
+		for {
+			closed := false
+
+			select {
+			case data, ok := <-chin:
+				if !ok {
+					if len(messages) == 0 {
+						return
+					}
+					closed = true
+					break
+				}
+
+				messages = append(messages, data)
+				size += len(data)
+
+				if len(messages) == 1 {
+					timer = time.NewTimer(window)
+					chTimer = timer.C
+				}
+
+				if len(messages) < batchMaxMessages && size < batchMaxSize {
+					continue
+				}
+
+				timer.Stop()
+			case <-chTimer:
+			case <-stop:
+				return
+			case <-ticker.C:
+				cg.logger.WithFields(logrus.Fields{
+					"buffered_messages": len(chout),
+					"buffer_size":       cap(chout),
+					"time_frame":        batchedMessageBufferMonitoringDelay,
+					"count":             count,
+				}).Debug("batched messages buffer monitoring")
+
+				count = 0
+				continue
+			}
+
+			chTimer = nil
+
+			select {
+			case chout <- joinMessages(messages, size):
+				count++
+			case <-stop:
+				return
+			}
+
+			if closed {
+				return
+			}
+
+			for i := range messages {
+				messages[i] = nil
+			}
+			messages = messages[:0]
+			size = 0
+		}
+	}()
+
+	return chout
+}
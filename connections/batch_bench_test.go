@@ -0,0 +1,142 @@
+package connections
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+var benchmarkGameMessage = []byte(`{"type":"game","payload":{"type":"update","payload":{"id":12,"dots":[[4,3],[3,3],[2,3],[1,3]],"type":"snake"}}}`)
+
+// countingConn counts writes to the network connection: every write is a
+// system call
+type countingConn struct {
+	net.Conn
+	writes *uint64
+}
+
+func (c countingConn) Write(p []byte) (int, error) {
+	atomic.AddUint64(c.writes, 1)
+	return c.Conn.Write(p)
+}
+
+type countingListener struct {
+	net.Listener
+	writes *uint64
+}
+
+func (l countingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return countingConn{conn, l.writes}, nil
+}
+
+// benchmarkConn returns a server side web-socket connection which writes are
+// counted. The client side discards received messages
+func benchmarkConn(b *testing.B, writes *uint64) *websocket.Conn {
+	chConn := make(chan *websocket.Conn, 1)
+
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			b.Error(err)
+			return
+		}
+		chConn <- conn
+	}))
+	server.Listener = countingListener{server.Listener, writes}
+	server.Start()
+	b.Cleanup(server.Close)
+
+	client, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() {
+		client.Close()
+	})
+
+	go func() {
+		for {
+			if _, _, err := client.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	conn := <-chConn
+	b.Cleanup(func() {
+		conn.Close()
+	})
+
+	return conn
+}
+
+func rawBenchmarkSendTick(b *testing.B, messagesPerTick int, batch bool) {
+	b.ReportAllocs()
+
+	var writes uint64
+	conn := benchmarkConn(b, &writes)
+
+	messages := make([][]byte, messagesPerTick)
+	size := 0
+	for i := range messages {
+		messages[i] = benchmarkGameMessage
+		size += len(benchmarkGameMessage)
+	}
+
+	atomic.StoreUint64(&writes, 0)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if batch {
+			pm, err := websocket.NewPreparedMessage(websocket.TextMessage, joinMessages(messages, size))
+			if err != nil {
+				b.Fatal(err)
+			}
+			if err := conn.WritePreparedMessage(pm); err != nil {
+				b.Fatal(err)
+			}
+			continue
+		}
+
+		for _, message := range messages {
+			pm, err := websocket.NewPreparedMessage(websocket.TextMessage, message)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if err := conn.WritePreparedMessage(pm); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+
+	b.StopTimer()
+
+	b.ReportMetric(float64(atomic.LoadUint64(&writes))/float64(b.N), "writes/op")
+}
+
+func Benchmark_SendTick_16Messages_OneByOne(b *testing.B) {
+	rawBenchmarkSendTick(b, 16, false)
+}
+
+func Benchmark_SendTick_16Messages_Batched(b *testing.B) {
+	rawBenchmarkSendTick(b, 16, true)
+}
+
+func Benchmark_SendTick_128Messages_OneByOne(b *testing.B) {
+	rawBenchmarkSendTick(b, 128, false)
+}
+
+func Benchmark_SendTick_128Messages_Batched(b *testing.B) {
+	rawBenchmarkSendTick(b, 128, true)
+}
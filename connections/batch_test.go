@@ -0,0 +1,161 @@
+package connections
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_joinMessages(t *testing.T) {
+	require.Equal(t, `[]`, string(joinMessages(nil, 0)))
+	require.Equal(t, `[{"a":1}]`, string(joinMessages([][]byte{[]byte(`{"a":1}`)}, 7)))
+	require.Equal(t, `[{"a":1},"b",3]`, string(joinMessages([][]byte{
+		[]byte(`{"a":1}`),
+		[]byte(`"b"`),
+		[]byte(`3`),
+	}, 11)))
+}
+
+func Test_ConnectionGroup_batch_JoinsMessagesWithinWindow(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	cg := &ConnectionGroup{
+		logger: logger,
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	chin := make(chan []byte, 8)
+	chout := cg.batch(stop, chin, time.Millisecond*50)
+
+	chin <- []byte(`1`)
+	chin <- []byte(`2`)
+	chin <- []byte(`3`)
+
+	select {
+	case data := <-chout:
+		require.Equal(t, `[1,2,3]`, string(data))
+	case <-time.After(time.Second):
+		t.Fatal("batch has not been sent")
+	}
+
+	chin <- []byte(`4`)
+
+	select {
+	case data := <-chout:
+		require.Equal(t, `[4]`, string(data))
+	case <-time.After(time.Second):
+		t.Fatal("batch has not been sent")
+	}
+}
+
+func Test_ConnectionGroup_encode_CountsMessagesBeforeBatching(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	cg := &ConnectionGroup{
+		logger: logger,
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	chin := make(chan OutputMessage, 8)
+	chout := cg.batch(stop, cg.encode(stop, chin), time.Millisecond*50)
+
+	for i := 0; i < 3; i++ {
+		chin <- OutputMessage{
+			Type:    OutputMessageTypeBroadcast,
+			Payload: "message",
+		}
+	}
+
+	select {
+	case <-chout:
+	case <-time.After(time.Second):
+		t.Fatal("batch has not been sent")
+	}
+
+	// The rate is found from the number of messages, not frames
+	require.Eventually(t, func() bool {
+		return atomic.LoadUint32(&cg.messages) == 3
+	}, time.Second, time.Millisecond*10)
+}
+
+func Test_ConnectionGroup_batch_SendsFullBatchBeforeWindowExpires(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	cg := &ConnectionGroup{
+		logger: logger,
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	chin := make(chan []byte, batchMaxMessages)
+	chout := cg.batch(stop, chin, time.Hour)
+
+	for i := 0; i < batchMaxMessages; i++ {
+		chin <- []byte(`0`)
+	}
+
+	select {
+	case data := <-chout:
+		require.Len(t, data, batchMaxMessages*2+1)
+	case <-time.After(time.Second):
+		t.Fatal("full batch has not been sent")
+	}
+}
+
+func Test_ConnectionGroup_batch_FlushesOnClose(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	cg := &ConnectionGroup{
+		logger: logger,
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	chin := make(chan []byte, 2)
+	chout := cg.batch(stop, chin, time.Hour)
+
+	chin <- []byte(`"a"`)
+	chin <- []byte(`"b"`)
+	close(chin)
+
+	data, ok := <-chout
+	require.True(t, ok)
+	require.Equal(t, `["a","b"]`, string(data))
+
+	_, ok = <-chout
+	require.False(t, ok)
+}
+
+func Test_NewConnectionGroup_ValidatesBatchWindow(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	_, err := NewConnectionGroup(logger, 2, 20, 20, GroupConfig{
+		BatchWindow: -time.Millisecond,
+	})
+	require.NotNil(t, err)
+
+	_, err = NewConnectionGroup(logger, 2, 20, 20, GroupConfig{
+		BatchWindow: maxBatchWindow + time.Millisecond,
+	})
+	require.NotNil(t, err)
+
+	group, err := NewConnectionGroup(logger, 2, 20, 20, GroupConfig{
+		BatchWindow: time.Millisecond * 20,
+	})
+	require.Nil(t, err)
+	require.Equal(t, time.Millisecond*20, group.GetBatchWindow())
+}
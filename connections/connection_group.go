@@ -1,17 +1,25 @@
 package connections
 
 import (
+	"context"
 	"errors"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/pquerna/ffjson/ffjson"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/ivan1993spb/snake-server/broadcast"
 	"github.com/ivan1993spb/snake-server/game"
+	"github.com/ivan1993spb/snake-server/logfields"
+	"github.com/ivan1993spb/snake-server/objects/snake"
+	"github.com/ivan1993spb/snake-server/tracing"
+	"github.com/ivan1993spb/snake-server/world"
 )
 
 const (
@@ -33,23 +41,73 @@ const (
 	preparedMessageBufferMonitoringDelay        = time.Second * preparedMessageBufferMonitoringDelaySeconds
 
 	minimalConnectionLimit = 1
+
+	closeNoticeTimeout = time.Second
+	closeNoticeDelay   = time.Second
+	closeCheckInterval = time.Millisecond * 100
+
+	// closeStopTimeout is the time for which Close waits for players to
+	// leave after the group has been stopped
+	closeStopTimeout = time.Second
+)
+
+const (
+	// DefaultCloseReason is sent to players of a group which is closed by a
+	// client of the API without a reason
+	DefaultCloseReason = "the game has been closed by the server"
+
+	// MaxCloseReasonLength limits reasons passed by clients of the API
+	MaxCloseReasonLength = 128
+
+	// ForceDeleteDrainTimeout is the time for which players are waited to
+	// leave a force deleted group. With the close notice the deletion takes
+	// at most about 13 seconds
+	ForceDeleteDrainTimeout = time.Second * 10
 )
 
 type ConnectionGroup struct {
+	id     int
+	notify func(event GroupEvent)
+
 	limit      int
 	counter    int
+	closed     bool
 	counterMux *sync.RWMutex
 
 	rate uint32
 
+	// messages is the number of messages of the game and broadcasts encoded
+	// since the rate has been found. Batching does not change it
+	messages uint32
+
+	// seq is the sequence number of the last message sent to connections
+	seq uint64
+
+	batchWindow time.Duration
+
 	logger logrus.FieldLogger
 
 	game      *game.Game
 	broadcast *broadcast.GroupBroadcast
 
-	chs    []chan *websocket.PreparedMessage
+	chs    []chan groupMessage
 	chsMux *sync.RWMutex
 
+	workers        map[int]*ConnectionWorker
+	workersCounter int
+	workersMux     *sync.RWMutex
+
+	metrics *groupMetrics
+
+	// span lasts from the group is added until it is stopped. Spans of
+	// events of the game are its children
+	span    trace.Span
+	spanCtx context.Context
+
+	// closing is closed when players must leave the group
+	closing chan struct{}
+	closer  *sync.Once
+
 	stop    chan struct{}
 	stopper *sync.Once
 }
@@ -60,10 +118,26 @@ func (e errCreateConnectionGroup) Error() string {
 	return "cannot create connection group: " + string(e)
 }
 
-func NewConnectionGroup(logger logrus.FieldLogger, connectionLimit int, width, height uint8, enableWalls bool) (*ConnectionGroup, error) {
-	g, err := game.NewGame(logger, width, height, game.Config{
-		EnableWalls: enableWalls,
-	})
+// maxBatchWindow limits the delay which batching adds to game messages
+const maxBatchWindow = time.Second
+
+// GroupConfig describes the game of a group and how messages of the game are
+// sent to connections
+type GroupConfig struct {
+	Game game.Config
+
+	// BatchWindow is the period during which messages of the game are
+	// collected to be sent to clients in a single web-socket frame as a JSON
+	// array. Messages are sent one by one if the window is zero
+	BatchWindow time.Duration
+}
+
+func NewConnectionGroup(logger logrus.FieldLogger, connectionLimit int, width, height uint8, config GroupConfig) (*ConnectionGroup, error) {
+	if config.BatchWindow < 0 || config.BatchWindow > maxBatchWindow {
+		return nil, errCreateConnectionGroup("invalid batch window")
+	}
+
+	g, err := game.NewGame(logger, width, height, config.Game)
 	if err != nil {
 		return nil, errCreateConnectionGroup(err.Error())
 	}
@@ -73,18 +147,51 @@ func NewConnectionGroup(logger logrus.FieldLogger, connectionLimit int, width, h
 	}
 
 	return &ConnectionGroup{
-		limit:      connectionLimit,
-		counterMux: &sync.RWMutex{},
-		game:       g,
-		broadcast:  broadcast.NewGroupBroadcast(),
-		logger:     logger,
-		chs:        make([]chan *websocket.PreparedMessage, 0),
-		chsMux:     &sync.RWMutex{},
-		stop:       make(chan struct{}),
-		stopper:    &sync.Once{},
+		limit:       connectionLimit,
+		counterMux:  &sync.RWMutex{},
+		batchWindow: config.BatchWindow,
+		game:        g,
+		broadcast:   broadcast.NewGroupBroadcast(),
+		logger:      logger,
+		chs:         make([]chan groupMessage, 0),
+		chsMux:      &sync.RWMutex{},
+		workers:     map[int]*ConnectionWorker{},
+		workersMux:  &sync.RWMutex{},
+		metrics:     newGroupMetrics(),
+		closing:     make(chan struct{}),
+		closer:      &sync.Once{},
+		stop:        make(chan struct{}),
+		stopper:     &sync.Once{},
 	}, nil
 }
 
+// setID is called by the group manager when the group is added. Log lines
+// of the group and its game get the identifier. The span of the group starts
+func (cg *ConnectionGroup) setID(id int, notify func(event GroupEvent)) {
+	cg.id = id
+	cg.notify = notify
+
+	cg.spanCtx, cg.span = tracing.Tracer().Start(context.Background(), "game",
+		trace.WithAttributes(tracing.AttributeGameID.Int(id)))
+
+	if cg.logger != nil {
+		cg.logger = cg.logger.WithField(logfields.GameID, id)
+		if cg.game != nil {
+			cg.game.SetLogger(cg.logger)
+		}
+	}
+}
+
+func (cg *ConnectionGroup) emit(eventType GroupEventType, payload interface{}) {
+	if cg.notify != nil {
+		cg.notify(GroupEvent{
+			Type:    eventType,
+			GameID:  cg.id,
+			Payload: payload,
+		})
+	}
+}
+
 func (cg *ConnectionGroup) GetLimit() int {
 	cg.counterMux.RLock()
 	defer cg.counterMux.RUnlock()
@@ -133,10 +240,99 @@ func (e *ErrHandleConnection) Error() string {
 	return "handle connection error: " + e.Err.Error()
 }
 
-var ErrGroupIsFull = errors.New("group is full")
+var (
+	ErrGroupIsFull   = errors.New("group is full")
+	ErrGroupIsClosed = errors.New("group is closed")
+)
 
 func (cg *ConnectionGroup) Handle(connectionWorker *ConnectionWorker) error {
+	return cg.HandleFunc(func(stop <-chan struct{}, game *game.Game, broadcast *broadcast.GroupBroadcast) error {
+		chStopHandle := make(chan struct{})
+		defer close(chStopHandle)
+
+		cg.addWorker(connectionWorker)
+		defer cg.deleteWorker(connectionWorker)
+
+		chout := cg.proxyCh(chStopHandle, chanPreparedMessageOutBuffer, connectionWorker.skip)
+
+		return connectionWorker.Start(stop, game, broadcast, chout)
+	})
+}
+
+func (cg *ConnectionGroup) addWorker(connectionWorker *ConnectionWorker) {
+	cg.workersMux.Lock()
+	defer cg.workersMux.Unlock()
+
+	cg.workersCounter += 1
+	connectionWorker.id = cg.workersCounter
+	connectionWorker.metrics = cg.metrics
+	connectionWorker.logger = connectionWorker.logger.WithFields(logrus.Fields{
+		logfields.GameID:       cg.id,
+		logfields.ConnectionID: connectionWorker.id,
+	})
+	connectionWorker.groupSeq = cg.lastSeq
+	cg.workers[connectionWorker.id] = connectionWorker
+}
+
+func (cg *ConnectionGroup) deleteWorker(connectionWorker *ConnectionWorker) {
+	cg.workersMux.Lock()
+	defer cg.workersMux.Unlock()
+
+	delete(cg.workers, connectionWorker.id)
+}
+
+// GetConnections returns information about web-socket connections of the
+// group ordered by identifiers
+func (cg *ConnectionGroup) GetConnections() []ConnectionInfo {
+	cg.workersMux.RLock()
+	defer cg.workersMux.RUnlock()
+
+	connections := make([]ConnectionInfo, 0, len(cg.workers))
+
+	for _, connectionWorker := range cg.workers {
+		connections = append(connections, connectionWorker.Info())
+	}
+
+	sort.Slice(connections, func(i, j int) bool {
+		return connections[i].ID < connections[j].ID
+	})
+
+	return connections
+}
+
+var ErrNotFoundConnection = errors.New("connection not found")
+
+// KickConnection sends the reason to the connection with the given
+// identifier and closes it
+func (cg *ConnectionGroup) KickConnection(id int, reason string) error {
+	cg.workersMux.RLock()
+	connectionWorker, ok := cg.workers[id]
+	cg.workersMux.RUnlock()
+
+	if !ok {
+		return ErrNotFoundConnection
+	}
+
+	connectionWorker.Kick(reason)
+
+	return nil
+}
+
+// HandlerFunc is a function which takes part in the game of a group. The
+// function must return when the stop channel is closed. The channel is closed
+// when the group is closed or stopped
+type HandlerFunc func(stop <-chan struct{}, game *game.Game, broadcast *broadcast.GroupBroadcast) error
+
+// HandleFunc occupies a place in the group and runs the handler f until it
+// returns. It is used for players connected not through web-sockets
+func (cg *ConnectionGroup) HandleFunc(f HandlerFunc) error {
 	cg.counterMux.Lock()
+	if cg.closed {
+		cg.counterMux.Unlock()
+		return &ErrHandleConnection{
+			Err: ErrGroupIsClosed,
+		}
+	}
 	if cg.unsafeIsFull() {
 		cg.counterMux.Unlock()
 		return &ErrHandleConnection{
@@ -144,20 +340,25 @@ func (cg *ConnectionGroup) Handle(connectionWorker *ConnectionWorker) error {
 		}
 	}
 	cg.counter += 1
+	count := cg.counter
 	cg.counterMux.Unlock()
 
+	cg.emit(GroupEventTypePlayerJoined, GroupEventPlayers{
+		Count: count,
+	})
+
 	defer func() {
 		cg.counterMux.Lock()
 		cg.counter -= 1
+		count := cg.counter
 		cg.counterMux.Unlock()
-	}()
-
-	chStopHandle := make(chan struct{})
-	defer close(chStopHandle)
 
-	chout := cg.proxyCh(chStopHandle, chanPreparedMessageOutBuffer)
+		cg.emit(GroupEventTypePlayerLeft, GroupEventPlayers{
+			Count: count,
+		})
+	}()
 
-	if err := connectionWorker.Start(cg.stop, cg.game, cg.broadcast, chout); err != nil {
+	if err := f(cg.closing, cg.game, cg.broadcast); err != nil {
 		return &ErrHandleConnection{
 			Err: err,
 		}
@@ -170,11 +371,65 @@ func (cg *ConnectionGroup) Start() {
 	cg.broadcast.Start(cg.stop)
 	cg.game.Start(cg.stop)
 
+	cg.emit(GroupEventTypeGameCreated, GroupEventGameCreated{
+		Limit:  cg.GetLimit(),
+		Width:  cg.GetWorldWidth(),
+		Height: cg.GetWorldHeight(),
+	})
+
 	chMessagesGame := cg.listenGame(cg.stop, cg.game.ListenEvents(cg.stop, chanGameEventsBuffer))
 	chMessagesBroadcast := cg.listenBroadcast(cg.stop, cg.broadcast.ListenMessages(cg.stop, chanBroadcastBuffer))
-	chBytes := cg.encode(cg.stop, chMessagesGame, chMessagesBroadcast)
+	chEncoded := cg.encode(cg.stop, chMessagesGame, chMessagesBroadcast)
+	chBytes := chEncoded
+	if cg.batchWindow > 0 {
+		chBytes = cg.batch(cg.stop, chEncoded, cg.batchWindow)
+		cg.metrics.watchBuffer(bufferBatchedMessages, func() (int, int) {
+			return len(chBytes), cap(chBytes)
+		})
+	}
 	chPreparedMessages := cg.prepare(cg.stop, chBytes)
 	cg.broadcastPreparedMessages(chPreparedMessages)
+
+	cg.metrics.watchBuffer(bufferGameMessages, func() (int, int) {
+		return len(chMessagesGame), cap(chMessagesGame)
+	})
+	cg.metrics.watchBuffer(bufferBroadcastMessages, func() (int, int) {
+		return len(chMessagesBroadcast), cap(chMessagesBroadcast)
+	})
+	cg.metrics.watchBuffer(bufferEncodedMessages, func() (int, int) {
+		return len(chEncoded), cap(chEncoded)
+	})
+	cg.metrics.watchBuffer(bufferPreparedMessages, func() (int, int) {
+		return len(chPreparedMessages), cap(chPreparedMessages)
+	})
+	cg.metrics.watchBuffer(bufferConnectionsMax, cg.connectionsBufferFill)
+}
+
+// connectionsBufferFill returns the fill level of the most filled buffer of
+// messages to connections
+func (cg *ConnectionGroup) connectionsBufferFill() (int, int) {
+	cg.chsMux.RLock()
+	defer cg.chsMux.RUnlock()
+
+	length := 0
+	for _, ch := range cg.chs {
+		if len(ch) > length {
+			length = len(ch)
+		}
+	}
+
+	return length, chanPreparedMessageProxyBuffer
+}
+
+// collectMetrics sends metrics of the group to the prometheus collector
+func (cg *ConnectionGroup) collectMetrics(ch chan<- prometheus.Metric, send metricSender, gameId string) error {
+	if cg.game != nil {
+		for label, count := range countObjects(cg.game.World().GetObjects()) {
+			send(metricServerGamesObjectsDesc, prometheus.GaugeValue, float64(count), gameId, label)
+		}
+	}
+
+	return cg.metrics.collect(ch, send, gameId)
 }
 
 func (cg *ConnectionGroup) broadcastPreparedMessages(chin <-chan *websocket.PreparedMessage) {
@@ -198,19 +453,109 @@ func (cg *ConnectionGroup) doBroadcast(pm *websocket.PreparedMessage) {
 	cg.chsMux.RLock()
 	defer cg.chsMux.RUnlock()
 
+	message := groupMessage{
+		seq: atomic.AddUint64(&cg.seq, 1),
+		pm:  pm,
+	}
+
 	for _, ch := range cg.chs {
 		select {
-		case ch <- pm:
+		case ch <- message:
 		case <-cg.stop:
 			return
 		}
 	}
 }
 
+// refuse makes the group refuse new connections
+func (cg *ConnectionGroup) refuse() {
+	cg.counterMux.Lock()
+	cg.closed = true
+	cg.counterMux.Unlock()
+}
+
+// IsClosed returns true if the group does not accept new connections
+func (cg *ConnectionGroup) IsClosed() bool {
+	cg.counterMux.RLock()
+	defer cg.counterMux.RUnlock()
+	return cg.closed
+}
+
+// Close closes the group for new connections, notifies players, closes
+// web-socket connections with the reason, stops handlers of other players and
+// waits for the group to become empty. The group is stopped if it is not empty
+// when the timeout expires. Close returns false if the group has not drained.
+// Close takes at most closeNoticeTimeout+closeNoticeDelay+timeout+
+// closeStopTimeout
+func (cg *ConnectionGroup) Close(reason string, timeout time.Duration) bool {
+	cg.refuse()
+
+	if cg.BroadcastMessageTimeout("game is closing: "+reason, closeNoticeTimeout) {
+		// Give the notice time to reach the clients
+		time.Sleep(closeNoticeDelay)
+	}
+
+	cg.workersMux.RLock()
+	for _, connectionWorker := range cg.workers {
+		connectionWorker.CloseGame(reason)
+	}
+	cg.workersMux.RUnlock()
+
+	cg.closeHandlers()
+
+	if cg.waitEmpty(timeout) {
+		return true
+	}
+
+	cg.logger.WithField("count", cg.GetCount()).Warn("group has not drained: stop group")
+	cg.Stop()
+
+	return cg.waitEmpty(closeStopTimeout)
+}
+
+// closeHandlers makes handlers of players connected through HandleFunc return
+func (cg *ConnectionGroup) closeHandlers() {
+	cg.closer.Do(func() {
+		close(cg.closing)
+	})
+}
+
+func (cg *ConnectionGroup) waitEmpty(timeout time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	ticker := time.NewTicker(closeCheckInterval)
+	defer ticker.Stop()
+
+	for !cg.IsEmpty() {
+		select {
+		case <-ticker.C:
+		case <-timer.C:
+			return cg.IsEmpty()
+		}
+	}
+
+	return true
+}
+
 func (cg *ConnectionGroup) Stop() {
 	cg.stopper.Do(func() {
 		close(cg.stop)
+		if cg.span != nil {
+			cg.span.End()
+		}
 	})
+	cg.closeHandlers()
+}
+
+// isStopped returns true if the group has been stopped
+func (cg *ConnectionGroup) isStopped() bool {
+	select {
+	case <-cg.stop:
+		return true
+	default:
+		return false
+	}
 }
 
 func (cg *ConnectionGroup) GetWorldWidth() uint8 {
@@ -221,12 +566,31 @@ func (cg *ConnectionGroup) GetWorldHeight() uint8 {
 	return cg.game.World().Area().Height()
 }
 
+// GetBatchWindow returns the period during which game messages are collected
+// into one web-socket frame. Zero means that batching is disabled
+func (cg *ConnectionGroup) GetBatchWindow() time.Duration {
+	return cg.batchWindow
+}
+
 func (cg *ConnectionGroup) GetObjects() interface{} {
 	return cg.game.World().GetObjects()
 }
 
-func (cg *ConnectionGroup) createChan() chan *websocket.PreparedMessage {
-	ch := make(chan *websocket.PreparedMessage, chanPreparedMessageProxyBuffer)
+// groupMessage is a message of the group numbered in the order in which
+// messages are sent to connections
+type groupMessage struct {
+	seq uint64
+	pm  *websocket.PreparedMessage
+}
+
+// lastSeq returns the sequence number of the last message which has been sent
+// to connections
+func (cg *ConnectionGroup) lastSeq() uint64 {
+	return atomic.LoadUint64(&cg.seq)
+}
+
+func (cg *ConnectionGroup) createChan() chan groupMessage {
+	ch := make(chan groupMessage, chanPreparedMessageProxyBuffer)
 
 	cg.chsMux.Lock()
 	cg.chs = append(cg.chs, ch)
@@ -235,7 +599,7 @@ func (cg *ConnectionGroup) createChan() chan *websocket.PreparedMessage {
 	return ch
 }
 
-func (cg *ConnectionGroup) deleteChan(ch chan *websocket.PreparedMessage) {
+func (cg *ConnectionGroup) deleteChan(ch chan groupMessage) {
 	go func() {
 		for range ch {
 		}
@@ -252,9 +616,12 @@ func (cg *ConnectionGroup) deleteChan(ch chan *websocket.PreparedMessage) {
 	cg.chsMux.Unlock()
 }
 
-func (cg *ConnectionGroup) proxyCh(stop <-chan struct{}, buffer uint) <-chan *websocket.PreparedMessage {
+// proxyCh returns a channel of messages of the group for a connection. The
+// function skip is called for every message which has not been sent because
+// the connection has not taken it in time
+func (cg *ConnectionGroup) proxyCh(stop <-chan struct{}, buffer uint, skip func()) <-chan groupMessage {
 	ch := cg.createChan()
-	chOut := make(chan *websocket.PreparedMessage, buffer)
+	chOut := make(chan groupMessage, buffer)
 
 	go func() {
 		defer close(chOut)
@@ -270,7 +637,9 @@ func (cg *ConnectionGroup) proxyCh(stop <-chan struct{}, buffer uint) <-chan *we
 				if !ok {
 					return
 				}
-				cg.sendTimeout(chOut, message, stop, sendPreparedMessageTimeout)
+				if !cg.sendTimeout(chOut, message, stop, sendPreparedMessageTimeout) {
+					skip()
+				}
 			}
 		}
 	}()
@@ -278,22 +647,60 @@ func (cg *ConnectionGroup) proxyCh(stop <-chan struct{}, buffer uint) <-chan *we
 	return chOut
 }
 
-func (cg *ConnectionGroup) sendTimeout(ch chan *websocket.PreparedMessage, pm *websocket.PreparedMessage, stop <-chan struct{}, timeout time.Duration) {
+func (cg *ConnectionGroup) sendTimeout(ch chan groupMessage, message groupMessage, stop <-chan struct{}, timeout time.Duration) bool {
 	const warnFormat = "game group message was not send to connection: %s"
 	var timer = time.NewTimer(timeout)
 	defer timer.Stop()
 	select {
-	case ch <- pm:
+	case ch <- message:
+		return true
 	case <-cg.stop:
 		cg.logger.Warnf(warnFormat, "game group stopped")
 	case <-stop:
 		cg.logger.Warnf(warnFormat, "connection handler stopped")
 	case <-timer.C:
 		cg.logger.Warnf(warnFormat, "time is out")
+		cg.metrics.observeSendTimeout(sendTimeoutStageGroup)
 		if len(ch) == cap(ch) {
 			cg.logger.Warn("connection group output channel buffer is overflow for connection")
 		}
 	}
+	return false
+}
+
+// ListenGameEvents returns a channel of game events which are sent to
+// clients. It does not occupy a place in the group
+func (cg *ConnectionGroup) ListenGameEvents(stop <-chan struct{}, buffer uint) <-chan game.Event {
+	chin := cg.game.ListenEvents(stop, buffer)
+	chout := make(chan game.Event, buffer)
+
+	go func() {
+		defer close(chout)
+		defer func() {
+			for range chin {
+			}
+		}()
+
+		for event := range chin {
+			if !isClientGameEvent(event) {
+				continue
+			}
+
+			select {
+			case chout <- event:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	return chout
+}
+
+func isClientGameEvent(event game.Event) bool {
+	// Do not send internal game errors, checked and eat events to clients
+	return event.Type != game.EventTypeError && event.Type != game.EventTypeObjectChecked &&
+		event.Type != game.EventTypeObjectEat
 }
 
 func (cg *ConnectionGroup) listenGame(stop <-chan struct{}, chin <-chan game.Event) <-chan OutputMessage {
@@ -314,14 +721,33 @@ func (cg *ConnectionGroup) listenGame(stop <-chan struct{}, chin <-chan game.Eve
 					return
 				}
 
-				// Do not send internal game errors to clients
-				if event.Type == game.EventTypeError {
+				cg.metrics.observeEvent(event)
+
+				if !isClientGameEvent(event) {
 					continue
 				}
 
-				// Do not send checked events to clients
-				if event.Type == game.EventTypeObjectChecked {
-					continue
+				if event.Type == game.EventTypeObjectCreate {
+					if s, ok := event.Payload.(*snake.Snake); ok {
+						cg.traceSnakeEvent("snake.spawn", s)
+					}
+				}
+
+				if event.Type == game.EventTypeObjectDelete {
+					if s, ok := event.Payload.(*snake.Snake); ok {
+						cg.traceSnakeEvent("snake.death", s)
+						cg.emit(GroupEventTypeSnakeDied, GroupEventSnakeDied{
+							SnakeID: s.GetID(),
+						})
+					}
+				}
+
+				if event.Type == game.EventTypeArena {
+					if arena, ok := event.Payload.(world.Arena); ok && arena.State == world.ArenaStateOver {
+						cg.emit(GroupEventTypeMatchFinished, GroupEventMatchFinished{
+							WinnerID: arena.WinnerID,
+						})
+					}
 				}
 
 				outputMessage := OutputMessage{
@@ -353,6 +779,20 @@ func (cg *ConnectionGroup) listenGame(stop <-chan struct{}, chin <-chan game.Eve
 	return chout
 }
 
+// traceSnakeEvent records a significant event of a snake as a span of the game
+func (cg *ConnectionGroup) traceSnakeEvent(name string, s *snake.Snake) {
+	ctx := cg.spanCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	_, span := tracing.Tracer().Start(ctx, name, trace.WithAttributes(
+		tracing.AttributeGameID.Int(cg.id),
+		tracing.AttributeSnakeID.Int64(int64(s.GetID())),
+	))
+	span.End()
+}
+
 func (cg *ConnectionGroup) listenBroadcast(stop <-chan struct{}, chin <-chan broadcast.Message) <-chan OutputMessage {
 	chout := make(chan OutputMessage, cap(chin))
 
@@ -430,6 +870,7 @@ func (cg *ConnectionGroup) encode(stop <-chan struct{}, chins ...<-chan OutputMe
 						select {
 						case chout <- data:
 							count++
+							atomic.AddUint32(&cg.messages, 1)
 						case <-stop:
 							return
 						}
@@ -495,8 +936,9 @@ func (cg *ConnectionGroup) prepare(stop <-chan struct{}, chin <-chan []byte) <-c
 					"count":             count,
 				}).Debug("prepared messages buffer monitoring")
 
-				// Find rate per second
-				atomic.StoreUint32(&cg.rate, count/preparedMessageBufferMonitoringDelaySeconds)
+				// Find rate per second. Messages are counted, not frames
+				messages := atomic.SwapUint32(&cg.messages, 0)
+				atomic.StoreUint32(&cg.rate, messages/preparedMessageBufferMonitoringDelaySeconds)
 
 				count = 0
 			}
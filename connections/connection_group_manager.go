@@ -2,22 +2,37 @@ package connections
 
 import (
 	"errors"
+	"fmt"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
+
+	"github.com/ivan1993spb/snake-server/logfields"
 )
 
 const firstGroupId = 1
 
+const (
+	drainNoticeInterval = time.Second * 10
+	drainNoticeTimeout  = time.Second
+	drainCloseTimeout   = time.Second * 5
+	drainCloseReason    = "the server is shutting down"
+)
+
 type ConnectionGroupManager struct {
 	groups      map[int]*ConnectionGroup
 	groupsMutex *sync.RWMutex
 	groupLimit  int
 	connsLimit  int
 	connsCount  int
+	draining    bool
 	logger      logrus.FieldLogger
+
+	listeners    []chan GroupEvent
+	listenersMux sync.RWMutex
 }
 
 func NewConnectionGroupManager(logger logrus.FieldLogger, groupLimit, connsLimit int) (*ConnectionGroupManager, error) {
@@ -35,7 +50,7 @@ func NewConnectionGroupManager(logger logrus.FieldLogger, groupLimit, connsLimit
 }
 
 func (m *ConnectionGroupManager) unsafeIsFull() bool {
-	return len(m.groups) == m.groupLimit
+	return len(m.groups) >= m.groupLimit
 }
 
 func (m *ConnectionGroupManager) IsFull() bool {
@@ -54,6 +69,7 @@ var (
 	ErrGroupLimitReached = ErrAddGroup("limit group count reached")
 	ErrCannotGetID       = ErrAddGroup("cannot get id for group")
 	ErrConnsLimitReached = ErrAddGroup("cannot reserve connections for group: connections count reached")
+	ErrServerDraining    = ErrAddGroup("server is shutting down")
 )
 
 func (m *ConnectionGroupManager) Add(group *ConnectionGroup) (int, error) {
@@ -66,6 +82,10 @@ func (m *ConnectionGroupManager) Add(group *ConnectionGroup) (int, error) {
 	m.groupsMutex.Lock()
 	defer m.groupsMutex.Unlock()
 
+	if m.draining {
+		return 0, ErrServerDraining
+	}
+
 	if m.unsafeIsFull() {
 		return 0, ErrGroupLimitReached
 	}
@@ -82,6 +102,7 @@ func (m *ConnectionGroupManager) Add(group *ConnectionGroup) (int, error) {
 	for id := firstGroupId; id <= len(m.groups)+firstGroupId; id++ {
 		if _, occupied := m.groups[id]; !occupied {
 			m.groups[id] = group
+			group.setID(id, m.notify)
 			return id, nil
 		}
 	}
@@ -107,7 +128,8 @@ func (m *ConnectionGroupManager) Delete(group *ConnectionGroup) error {
 	defer m.groupsMutex.Unlock()
 
 	// TODO: Move that checking in the core module.
-	if !group.IsEmpty() {
+	// A stopped group is deleted even if its players have not left yet
+	if !group.IsEmpty() && !group.isStopped() {
 		return ErrDeleteNotEmptyGroup
 	}
 
@@ -115,6 +137,10 @@ func (m *ConnectionGroupManager) Delete(group *ConnectionGroup) error {
 		if m.groups[id] == group {
 			delete(m.groups, id)
 			m.connsCount -= group.GetLimit()
+			m.notify(GroupEvent{
+				Type:   GroupEventTypeGameDeleted,
+				GameID: id,
+			})
 
 			return nil
 		}
@@ -147,9 +173,40 @@ func (m *ConnectionGroupManager) Groups() map[int]*ConnectionGroup {
 }
 
 func (m *ConnectionGroupManager) GroupLimit() int {
+	m.groupsMutex.RLock()
+	defer m.groupsMutex.RUnlock()
 	return m.groupLimit
 }
 
+var ErrInvalidLimits = errors.New("invalid limits")
+
+// SetLimits changes the limits of groups and connections. The limits apply
+// to new groups only: existing groups and their players are kept if the new
+// limits are lower than the current numbers, but new groups cannot be added
+// until the numbers decrease
+func (m *ConnectionGroupManager) SetLimits(groupLimit, connsLimit int) error {
+	if groupLimit < 1 || connsLimit < 1 {
+		return ErrInvalidLimits
+	}
+
+	m.groupsMutex.Lock()
+	defer m.groupsMutex.Unlock()
+
+	if len(m.groups) > groupLimit || m.connsCount > connsLimit {
+		m.logger.WithFields(logrus.Fields{
+			"groups":      len(m.groups),
+			"group_limit": groupLimit,
+			"conns":       m.connsCount,
+			"conns_limit": connsLimit,
+		}).Warn("limits are lower than the current usage: they apply to new games only")
+	}
+
+	m.groupLimit = groupLimit
+	m.connsLimit = connsLimit
+
+	return nil
+}
+
 func (m *ConnectionGroupManager) unsafeGroupCount() int {
 	return len(m.groups)
 }
@@ -168,6 +225,12 @@ func (m *ConnectionGroupManager) unsafeConnCount() int {
 	return count
 }
 
+func (m *ConnectionGroupManager) connCount() int {
+	m.groupsMutex.RLock()
+	defer m.groupsMutex.RUnlock()
+	return m.unsafeConnCount()
+}
+
 func (m *ConnectionGroupManager) unsafeCapacity() float32 {
 	var count = m.unsafeConnCount()
 	return float32(count) / float32(m.connsLimit)
@@ -179,6 +242,134 @@ func (m *ConnectionGroupManager) Capacity() float32 {
 	return m.unsafeCapacity()
 }
 
+// IsDraining returns true if the manager does not accept new groups
+func (m *ConnectionGroupManager) IsDraining() bool {
+	m.groupsMutex.RLock()
+	defer m.groupsMutex.RUnlock()
+	return m.draining
+}
+
+// Drain stops accepting new groups and connections, notifies players about
+// the shutdown and waits for the groups to become empty until the period
+// expires. Then connections left are closed. Drain returns when all groups
+// are empty or closing is timed out
+func (m *ConnectionGroupManager) Drain(period time.Duration) {
+	m.groupsMutex.Lock()
+	m.draining = true
+	m.groupsMutex.Unlock()
+
+	groups := m.Groups()
+
+	for _, group := range groups {
+		group.refuse()
+	}
+
+	m.logger.WithFields(logrus.Fields{
+		"period": period,
+		"groups": len(groups),
+	}).Info("drain groups")
+
+	deadline := time.Now().Add(period)
+
+	timer := time.NewTimer(period)
+	defer timer.Stop()
+
+	notice := time.NewTicker(drainNoticeInterval)
+	defer notice.Stop()
+
+	check := time.NewTicker(closeCheckInterval)
+	defer check.Stop()
+
+	m.noticeShutdown(groups, time.Until(deadline))
+
+wait:
+	for m.connCount() > 0 {
+		select {
+		case <-notice.C:
+			m.noticeShutdown(groups, time.Until(deadline))
+		case <-check.C:
+		case <-timer.C:
+			break wait
+		}
+	}
+
+	wg := &sync.WaitGroup{}
+
+	for id, group := range groups {
+		if group.IsEmpty() {
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(id int, group *ConnectionGroup) {
+			defer wg.Done()
+
+			if !group.Close(drainCloseReason, drainCloseTimeout) {
+				m.logger.WithField(logfields.GameID, id).Warn("group has not drained")
+			}
+		}(id, group)
+	}
+
+	wg.Wait()
+
+	m.logger.Info("groups drained")
+}
+
+func (m *ConnectionGroupManager) noticeShutdown(groups map[int]*ConnectionGroup, left time.Duration) {
+	message := fmt.Sprintf("the server is shutting down in %d seconds", int(left.Round(time.Second).Seconds()))
+
+	for _, group := range groups {
+		if !group.IsEmpty() {
+			group.BroadcastMessageTimeout(message, drainNoticeTimeout)
+		}
+	}
+}
+
+// ListenGroupEvents returns a channel of lifecycle events of all groups. The
+// channel is closed when the stop channel is closed. Events are dropped if
+// the channel buffer is full
+func (m *ConnectionGroupManager) ListenGroupEvents(stop <-chan struct{}, buffer uint) <-chan GroupEvent {
+	ch := make(chan GroupEvent, buffer)
+
+	m.listenersMux.Lock()
+	m.listeners = append(m.listeners, ch)
+	m.listenersMux.Unlock()
+
+	go func() {
+		<-stop
+
+		m.listenersMux.Lock()
+		defer m.listenersMux.Unlock()
+
+		for i := range m.listeners {
+			if m.listeners[i] == ch {
+				m.listeners = append(m.listeners[:i], m.listeners[i+1:]...)
+				close(ch)
+				break
+			}
+		}
+	}()
+
+	return ch
+}
+
+func (m *ConnectionGroupManager) notify(event GroupEvent) {
+	m.listenersMux.RLock()
+	defer m.listenersMux.RUnlock()
+
+	for _, ch := range m.listeners {
+		select {
+		case ch <- event:
+		default:
+			m.logger.WithFields(logrus.Fields{
+				logfields.GameID: event.GameID,
+				"event_type":     event.Type,
+			}).Warn("group event listener buffer is full: event dropped")
+		}
+	}
+}
+
 const (
 	metricServerCapacityFQName     = "server_capacity"
 	metricServerGamesFQName        = "server_games"
@@ -232,6 +423,9 @@ func (m *ConnectionGroupManager) Describe(ch chan<- *prometheus.Desc) {
 	for _, desc := range descriptors {
 		ch <- desc
 	}
+	for _, desc := range groupMetricsDescriptors {
+		ch <- desc
+	}
 }
 
 // Collect implements prometheus.Collector.Collect by sending const metrics
@@ -255,5 +449,9 @@ func (m *ConnectionGroupManager) Collect(ch chan<- prometheus.Metric) {
 		gameId := strconv.Itoa(id)
 		send(metricServerGamesPlayersDesc, prometheus.GaugeValue, float64(group.GetCount()), gameId)
 		send(metricServerGamesRateDesc, prometheus.GaugeValue, float64(group.GetRate()), gameId)
+
+		if err := group.collectMetrics(ch, send, gameId); err != nil {
+			m.logger.Errorln("cannot collect game metrics:", err)
+		}
 	}
 }
@@ -1,9 +1,15 @@
 package connections
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
 	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/require"
 )
@@ -334,6 +340,35 @@ func Test_ConnectionGroupManager_Delete_MethodDeletesGroupSuccessfully(t *testin
 	require.Empty(t, m.groups)
 }
 
+func Test_ConnectionGroupManager_Delete_DeletesStoppedNotEmptyGroup(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	m, err := NewConnectionGroupManager(logger, 10, 100)
+	require.Nil(t, err)
+
+	group := &ConnectionGroup{
+		limit:      10,
+		counter:    1,
+		counterMux: &sync.RWMutex{},
+		logger:     logger,
+		closing:    make(chan struct{}),
+		closer:     &sync.Once{},
+		stop:       make(chan struct{}),
+		stopper:    &sync.Once{},
+	}
+
+	_, err = m.Add(group)
+	require.Nil(t, err)
+
+	require.Equal(t, ErrDeleteNotEmptyGroup, m.Delete(group))
+
+	group.Stop()
+
+	require.Nil(t, m.Delete(group))
+	require.Zero(t, m.GroupCount())
+}
+
 func Test_ConnectionGroupManager_GroupCount_ReturnsValidGroupCount(t *testing.T) {
 	const groupLimit = 10
 	const connsLimit = 100
@@ -377,3 +412,102 @@ func Test_ConnectionGroupManager_GroupCount_ReturnsValidGroupCount(t *testing.T)
 
 	require.Equal(t, 2, actualCount)
 }
+
+func Test_ConnectionGroupManager_Drain_ClosesConnectionsAndRefusesGroups(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	m, err := NewConnectionGroupManager(logger, 2, 10)
+	require.Nil(t, err)
+
+	group, err := NewConnectionGroup(logger, 2, 20, 20, GroupConfig{})
+	require.Nil(t, err)
+	_, err = m.Add(group)
+	require.Nil(t, err)
+	group.Start()
+	defer group.Stop()
+
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		require.Nil(t, err)
+		group.Handle(NewConnectionWorker(conn, logger))
+	}))
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.Nil(t, err)
+	defer conn.Close()
+
+	require.Eventually(t, func() bool {
+		return !group.IsEmpty()
+	}, time.Second, time.Millisecond*10)
+
+	chMessages := make(chan string, 64)
+
+	go func() {
+		defer close(chMessages)
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			chMessages <- string(data)
+		}
+	}()
+
+	m.Drain(time.Millisecond * 200)
+
+	require.True(t, m.IsDraining())
+	require.True(t, group.IsClosed())
+	require.True(t, group.IsEmpty())
+
+	var notice bool
+	for message := range chMessages {
+		if strings.HasPrefix(message, `{"type":"broadcast","payload":"the server is shutting down in `) {
+			notice = true
+		}
+	}
+	require.True(t, notice, "shutdown notice has not been received")
+
+	_, err = m.Add(&ConnectionGroup{
+		limit:      1,
+		counterMux: &sync.RWMutex{},
+	})
+	require.Equal(t, ErrServerDraining, err)
+}
+
+func Test_ConnectionGroupManager_SetLimits_AppliesNewLimits(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	m, err := NewConnectionGroupManager(logger, 2, 10)
+	require.Nil(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err := m.Add(&ConnectionGroup{
+			limit:      2,
+			counterMux: &sync.RWMutex{},
+		})
+		require.Nil(t, err)
+	}
+
+	require.Equal(t, ErrInvalidLimits, m.SetLimits(0, 10))
+	require.Equal(t, ErrInvalidLimits, m.SetLimits(1, 0))
+
+	require.Nil(t, m.SetLimits(1, 10))
+	require.Equal(t, 1, m.GroupLimit())
+	require.True(t, m.IsFull())
+	require.Len(t, m.Groups(), 2)
+	require.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+
+	require.Nil(t, m.SetLimits(3, 5))
+	require.False(t, m.IsFull())
+
+	_, err = m.Add(&ConnectionGroup{
+		limit:      2,
+		counterMux: &sync.RWMutex{},
+	})
+	require.Nil(t, err)
+	require.Equal(t, 1, m.Groups()[3].GetLimit())
+}
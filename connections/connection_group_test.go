@@ -0,0 +1,306 @@
+package connections
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivan1993spb/snake-server/broadcast"
+	"github.com/ivan1993spb/snake-server/game"
+	"github.com/ivan1993spb/snake-server/logfields"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+func Test_ConnectionGroup_KickConnection_SendsNoticeAndClosesConnection(t *testing.T) {
+	const reason = "bye"
+
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	group, err := NewConnectionGroup(logger, 2, 20, 20, GroupConfig{})
+	require.Nil(t, err)
+	group.Start()
+	defer group.Stop()
+
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		require.Nil(t, err)
+		group.Handle(NewConnectionWorker(conn, logger))
+	}))
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.Nil(t, err)
+	defer conn.Close()
+
+	require.Eventually(t, func() bool {
+		return len(group.GetConnections()) == 1
+	}, time.Second, time.Millisecond*10)
+
+	info := group.GetConnections()[0]
+	require.Equal(t, 1, info.ID)
+	require.Equal(t, conn.LocalAddr().String(), info.RemoteAddr)
+
+	require.Equal(t, ErrNotFoundConnection, group.KickConnection(2, reason))
+	require.Nil(t, group.KickConnection(1, reason))
+
+	var notice bool
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			require.True(t, websocket.IsCloseError(err, websocket.ClosePolicyViolation), err.Error())
+			require.Equal(t, reason, err.(*websocket.CloseError).Text)
+			break
+		}
+		if string(data) == `{"type":"player","payload":{"type":"notice","payload":"bye"}}` {
+			notice = true
+		}
+	}
+	require.True(t, notice, "notice has not been received")
+
+	require.Eventually(t, func() bool {
+		return len(group.GetConnections()) == 0 && group.IsEmpty()
+	}, time.Second, time.Millisecond*10)
+}
+
+func Test_ConnectionGroup_Close_ClosesConnectionsAndDrains(t *testing.T) {
+	const reason = "maintenance"
+
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	group, err := NewConnectionGroup(logger, 2, 20, 20, GroupConfig{})
+	require.Nil(t, err)
+	group.Start()
+	defer group.Stop()
+
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		require.Nil(t, err)
+		group.Handle(NewConnectionWorker(conn, logger))
+	}))
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.Nil(t, err)
+	defer conn.Close()
+
+	require.Eventually(t, func() bool {
+		return !group.IsEmpty()
+	}, time.Second, time.Millisecond*10)
+
+	chMessages := make(chan string, 64)
+	chErr := make(chan error, 1)
+
+	go func() {
+		defer close(chMessages)
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				chErr <- err
+				return
+			}
+			chMessages <- string(data)
+		}
+	}()
+
+	require.True(t, group.Close(reason, time.Second))
+	require.True(t, group.IsClosed())
+	require.True(t, group.IsEmpty())
+
+	var notice, closed bool
+	for message := range chMessages {
+		switch message {
+		case `{"type":"broadcast","payload":"game is closing: maintenance"}`:
+			notice = true
+		case `{"type":"player","payload":{"type":"game_closed","payload":"maintenance"}}`:
+			closed = true
+		}
+	}
+	require.True(t, notice, "notice has not been received")
+	require.True(t, closed, "game closed message has not been received")
+
+	err = <-chErr
+	require.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), err.Error())
+
+	err = group.HandleFunc(func(stop <-chan struct{}, game *game.Game, broadcast *broadcast.GroupBroadcast) error {
+		return nil
+	})
+	require.Equal(t, &ErrHandleConnection{Err: ErrGroupIsClosed}, err)
+}
+
+func Test_ConnectionGroup_Close_StopsHandlers(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	group, err := NewConnectionGroup(logger, 2, 20, 20, GroupConfig{})
+	require.Nil(t, err)
+	group.Start()
+	defer group.Stop()
+
+	chErr := make(chan error, 1)
+
+	go func() {
+		chErr <- group.HandleFunc(func(stop <-chan struct{}, game *game.Game, broadcast *broadcast.GroupBroadcast) error {
+			<-stop
+			return nil
+		})
+	}()
+
+	require.Eventually(t, func() bool {
+		return !group.IsEmpty()
+	}, time.Second, time.Millisecond*10)
+
+	require.True(t, group.Close("maintenance", time.Second))
+	require.Nil(t, <-chErr)
+
+	select {
+	case <-group.stop:
+		t.Fatal("drained group has been stopped")
+	default:
+	}
+}
+
+func Test_ConnectionGroup_Logs_HaveGameAndConnectionIDs(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	m, err := NewConnectionGroupManager(logger, 10, 100)
+	require.Nil(t, err)
+
+	group, err := NewConnectionGroup(logger, 2, 20, 20, GroupConfig{})
+	require.Nil(t, err)
+
+	id, err := m.Add(group)
+	require.Nil(t, err)
+	group.Start()
+	defer group.Stop()
+
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		require.Nil(t, err)
+		group.Handle(NewConnectionWorker(conn, logger))
+	}))
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.Nil(t, err)
+	defer conn.Close()
+
+	require.Eventually(t, func() bool {
+		return len(group.GetConnections()) == 1
+	}, time.Second, time.Millisecond*10)
+
+	require.Nil(t, group.KickConnection(1, "bye"))
+
+	require.Eventually(t, func() bool {
+		for _, entry := range hook.AllEntries() {
+			if entry.Message == "close connection" {
+				return entry.Data[logfields.GameID] == id && entry.Data[logfields.ConnectionID] == 1
+			}
+		}
+		return false
+	}, time.Second, time.Millisecond*10)
+	require.Eventually(t, func() bool {
+		return group.IsEmpty()
+	}, time.Second, time.Millisecond*10)
+}
+
+func Test_ConnectionGroup_BatchWindow_SendsGameMessagesAsArrays(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	group, err := NewConnectionGroup(logger, 2, 20, 20, GroupConfig{
+		BatchWindow: time.Millisecond * 20,
+	})
+	require.Nil(t, err)
+	group.Start()
+	defer group.Stop()
+
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		require.Nil(t, err)
+		group.Handle(NewConnectionWorker(conn, logger))
+	}))
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.Nil(t, err)
+	defer conn.Close()
+
+	var batch []map[string]interface{}
+
+	conn.SetReadDeadline(time.Now().Add(time.Second * 5))
+	for {
+		_, data, err := conn.ReadMessage()
+		require.Nil(t, err)
+		if strings.HasPrefix(string(data), "[") {
+			require.Nil(t, json.Unmarshal(data, &batch))
+			break
+		}
+	}
+
+	require.NotEmpty(t, batch)
+	for _, message := range batch {
+		require.Contains(t, []interface{}{"game", "broadcast"}, message["type"])
+	}
+
+	conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+
+	require.Eventually(t, func() bool {
+		return group.IsEmpty()
+	}, time.Second, time.Millisecond*10)
+}
+
+func Test_ConnectionGroup_listenGame_EmitsMatchFinished(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	group, err := NewConnectionGroup(logger, 2, 20, 20, GroupConfig{})
+	require.Nil(t, err)
+
+	events := make(chan GroupEvent, 2)
+	group.setID(1, func(event GroupEvent) {
+		events <- event
+	})
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	chin := make(chan game.Event, 2)
+	chout := group.listenGame(stop, chin)
+
+	chin <- game.Event{
+		Type: game.EventTypeArena,
+		Payload: world.Arena{
+			State: world.ArenaStateShrink,
+		},
+	}
+	chin <- game.Event{
+		Type: game.EventTypeArena,
+		Payload: world.Arena{
+			State:    world.ArenaStateOver,
+			WinnerID: 42,
+		},
+	}
+
+	<-chout
+	<-chout
+
+	require.Len(t, events, 1)
+	event := <-events
+	require.Equal(t, GroupEventTypeMatchFinished, event.Type)
+	require.Equal(t, GroupEventMatchFinished{WinnerID: 42}, event.Payload)
+}
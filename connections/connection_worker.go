@@ -2,7 +2,9 @@ package connections
 
 import (
 	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	"github.com/gorilla/websocket"
 	"github.com/pquerna/ffjson/ffjson"
@@ -11,14 +13,13 @@ import (
 	"github.com/ivan1993spb/snake-server/broadcast"
 	"github.com/ivan1993spb/snake-server/game"
 	"github.com/ivan1993spb/snake-server/player"
+	"github.com/ivan1993spb/snake-server/world"
 )
 
 const (
 	chanPlayerOutputMessageBuffer  = 256
 	chanPlayerEncodedMessageBuffer = 1024
 
-	chanMergePreparedMessageBuffer = 8192
-
 	chanReadMessagesBuffer           = 64
 	chanDecodeMessageBuffer          = 64
 	chanProxyInputMessageBuffer      = 64
@@ -26,44 +27,125 @@ const (
 	chanInputMessagesBroadcastBuffer = 64
 	chanSnakeCommandsBuffer          = 64
 
-	sendInputMessageTimeout  = time.Millisecond * 5
-	sendOutputMessageTimeout = time.Millisecond * 25
+	sendInputMessageTimeout = time.Millisecond * 5
 
 	broadcastDelay = time.Second * 15
 
 	ignoredBroadcastsCountToDisconnect = 40
+
+	kickCloseTimeout = time.Second
+
+	// Control frames cannot carry more than 125 bytes, two of them are
+	// taken by the close code
+	closeReasonMaxLength = 123
 )
 
 type ConnectionWorker struct {
+	id     int
 	conn   *websocket.Conn
 	logger logrus.FieldLogger
 
+	connectedSince time.Time
+
+	snakeID          uint32
+	messagesReceived uint64
+	messagesSent     uint64
+	messagesDropped  uint64
+	resyncs          uint64
+
+	// skipped is set if a game message has not reached the backpressure
+	// stage of the connection
+	skipped uint32
+
+	metrics *groupMetrics
+
+	// groupSeq returns the sequence number of the last message of the group
+	groupSeq func() uint64
+
 	chsInput    []chan InputMessage
 	chsInputMux *sync.RWMutex
 
 	flagStarted bool
 	startedMux  *sync.Mutex
+
+	disconnect        chan struct{}
+	disconnectMessage player.Message
+	disconnectCode    int
+	disconnectReason  string
+	disconnector      *sync.Once
 }
 
 func NewConnectionWorker(conn *websocket.Conn, logger logrus.FieldLogger) *ConnectionWorker {
 	return &ConnectionWorker{
-		conn:        conn,
-		logger:      logger,
-		chsInput:    make([]chan InputMessage, 0),
-		chsInputMux: &sync.RWMutex{},
+		conn:           conn,
+		logger:         logger,
+		connectedSince: time.Now(),
+		chsInput:       make([]chan InputMessage, 0),
+		chsInputMux:    &sync.RWMutex{},
 
 		flagStarted: false,
 		startedMux:  &sync.Mutex{},
+
+		disconnect:   make(chan struct{}),
+		disconnector: &sync.Once{},
 	}
 }
 
+// ConnectionInfo describes a connection of a group
+type ConnectionInfo struct {
+	ID               int              `json:"id"`
+	RemoteAddr       string           `json:"remote_addr,omitempty"`
+	ConnectedSince   time.Time        `json:"connected_since"`
+	SnakeID          world.Identifier `json:"snake_id,omitempty"`
+	MessagesReceived uint64           `json:"messages_received"`
+	MessagesSent     uint64           `json:"messages_sent"`
+	MessagesDropped  uint64           `json:"messages_dropped"`
+	Resyncs          uint64           `json:"resyncs"`
+}
+
+// Info returns information about the connection. The snake identifier is
+// zero if the player has no snake at the moment
+func (cw *ConnectionWorker) Info() ConnectionInfo {
+	return ConnectionInfo{
+		ID:               cw.id,
+		RemoteAddr:       cw.conn.RemoteAddr().String(),
+		ConnectedSince:   cw.connectedSince,
+		SnakeID:          world.Identifier(atomic.LoadUint32(&cw.snakeID)),
+		MessagesReceived: atomic.LoadUint64(&cw.messagesReceived),
+		MessagesSent:     atomic.LoadUint64(&cw.messagesSent),
+		MessagesDropped:  atomic.LoadUint64(&cw.messagesDropped),
+		Resyncs:          atomic.LoadUint64(&cw.resyncs),
+	}
+}
+
+// Kick sends the reason to the client as a notice and closes the connection.
+// Only the first call of Kick or CloseGame has an effect
+func (cw *ConnectionWorker) Kick(reason string) {
+	cw.closeConnection(player.NewMessageNotice(reason), websocket.ClosePolicyViolation, reason)
+}
+
+// CloseGame tells the client that the game is closed and closes the
+// connection
+func (cw *ConnectionWorker) CloseGame(reason string) {
+	cw.closeConnection(player.NewMessageGameClosed(reason), websocket.CloseGoingAway, reason)
+}
+
+func (cw *ConnectionWorker) closeConnection(message player.Message, code int, reason string) {
+	cw.disconnector.Do(func() {
+		cw.disconnectMessage = message
+		cw.disconnectCode = code
+		cw.disconnectReason = reason
+		close(cw.disconnect)
+	})
+}
+
 type ErrStartConnectionWorker string
 
 func (e ErrStartConnectionWorker) Error() string {
 	return "error start connection worker: " + string(e)
 }
 
-func (cw *ConnectionWorker) Start(stop <-chan struct{}, game *game.Game, broadcast *broadcast.GroupBroadcast, gamePreparedMessages <-chan *websocket.PreparedMessage) error {
+func (cw *ConnectionWorker) Start(stop <-chan struct{}, game *game.Game, broadcast *broadcast.GroupBroadcast, gameMessages <-chan groupMessage) error {
 	cw.startedMux.Lock()
 	if cw.flagStarted {
 		cw.startedMux.Unlock()
@@ -81,15 +163,14 @@ func (cw *ConnectionWorker) Start(stop <-chan struct{}, game *game.Game, broadca
 	chCommands := cw.listenSnakeCommands(chStop, cw.input(chStop, chanInputMessagesSnakeBuffer))
 	cw.listenPlayerBroadcasts(chStop, cw.input(chStop, chanInputMessagesBroadcastBuffer), broadcast, broadcastDelay)
 
-	p := player.NewPlayer(cw.logger, game.World())
+	p := player.NewPlayer(cw.logger, game.World(), game.Config().Snake)
 
 	// Output
 	chPlayer := p.Start(chStop, chCommands)
 	chOutputBytes := cw.encode(chStop, cw.listenPlayer(chStop, chPlayer))
 	chPlayerPreparedMessages := cw.prepare(chStop, chOutputBytes)
-	chPreparedMessages := cw.mergePreparedMessagesChs(chStop, chPlayerPreparedMessages, gamePreparedMessages)
-	chPreparedMessagesTimeout := cw.chPreparedMessageTimeout(chPreparedMessages, chStop, sendOutputMessageTimeout)
-	cw.write(chPreparedMessagesTimeout, chStop)
+	chPreparedMessages := cw.backpressure(chStop, chPlayerPreparedMessages, gameMessages, game.World())
+	cw.write(chPreparedMessages, chStop)
 
 	select {
 	case <-chStop:
@@ -139,6 +220,8 @@ func (cw *ConnectionWorker) read() (<-chan []byte, <-chan struct{}) {
 				continue
 			}
 
+			atomic.AddUint64(&cw.messagesReceived, 1)
+
 			chout <- data
 		}
 	}()
@@ -322,11 +405,8 @@ func (cw *ConnectionWorker) listenPlayerBroadcasts(stop <-chan struct{}, chin <-
 						cw.logger.Warn("ignore broadcast: delay")
 
 						if ignored > ignoredBroadcastsCountToDisconnect {
-							// TODO: Send an error message to the connection before closing
 							cw.logger.Warn("ignored broadcasts limit reached")
-							if err := cw.conn.Close(); err != nil {
-								cw.logger.WithError(err).Error("close connection error")
-							}
+							cw.Kick("too many broadcasts")
 						}
 					}
 				}
@@ -350,6 +430,12 @@ func (cw *ConnectionWorker) listenPlayer(stop <-chan struct{}, chin <-chan playe
 					return
 				}
 
+				if event.Type == player.MessageTypeSnake {
+					if id, ok := event.Payload.(player.MessageSnake); ok {
+						atomic.StoreUint32(&cw.snakeID, uint32(id))
+					}
+				}
+
 				outputMessage := OutputMessage{
 					Type:    OutputMessageTypePlayer,
 					Payload: event,
@@ -441,78 +527,6 @@ func (cw *ConnectionWorker) prepare(stop <-chan struct{}, chin <-chan []byte) <-
 	return chout
 }
 
-func (cw *ConnectionWorker) mergePreparedMessagesChs(stop <-chan struct{}, chins ...<-chan *websocket.PreparedMessage) <-chan *websocket.PreparedMessage {
-	chout := make(chan *websocket.PreparedMessage, chanMergePreparedMessageBuffer)
-
-	wg := sync.WaitGroup{}
-	wg.Add(len(chins))
-
-	for _, chin := range chins {
-		go func(chin <-chan *websocket.PreparedMessage) {
-			defer wg.Done()
-
-			for {
-				select {
-				case <-stop:
-					return
-				case data, ok := <-chin:
-					if !ok {
-						return
-					}
-
-					select {
-					case chout <- data:
-					case <-stop:
-						return
-					}
-				}
-			}
-		}(chin)
-	}
-
-	go func() {
-		wg.Wait()
-		close(chout)
-	}()
-
-	return chout
-}
-
-func (cw *ConnectionWorker) chPreparedMessageTimeout(chin <-chan *websocket.PreparedMessage, stop <-chan struct{}, timeout time.Duration) <-chan *websocket.PreparedMessage {
-	chout := make(chan *websocket.PreparedMessage, cap(chin))
-
-	go func() {
-		defer close(chout)
-
-		for {
-			select {
-			case pm, ok := <-chin:
-				if !ok {
-					return
-				}
-
-				timer := time.NewTimer(timeout)
-
-				select {
-				case chout <- pm:
-					timer.Stop()
-				case <-timer.C:
-					cw.logger.Warn("send message for writing time is out")
-					timer.Stop()
-					continue
-				case <-stop:
-					timer.Stop()
-					return
-				}
-			case <-stop:
-				return
-			}
-		}
-	}()
-
-	return chout
-}
-
 func (cw *ConnectionWorker) write(chin <-chan *websocket.PreparedMessage, stop <-chan struct{}) {
 	go func() {
 		for {
@@ -522,15 +536,62 @@ func (cw *ConnectionWorker) write(chin <-chan *websocket.PreparedMessage, stop <
 					return
 				}
 
+				started := time.Now()
 				if err := cw.conn.WritePreparedMessage(pm); err != nil {
 					if !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
 						cw.logger.Errorln("write output message error:", err)
 					}
 					return
 				}
+				cw.metrics.observeWrite(time.Since(started))
+
+				atomic.AddUint64(&cw.messagesSent, 1)
+			case <-cw.disconnect:
+				cw.writeDisconnect()
+				return
 			case <-stop:
 				return
 			}
 		}
 	}()
 }
+
+// writeDisconnect writes the disconnect message and a close frame and
+// closes the connection
+func (cw *ConnectionWorker) writeDisconnect() {
+	cw.logger.WithField("reason", cw.disconnectReason).Warn("close connection")
+
+	data, err := ffjson.Marshal(OutputMessage{
+		Type:    OutputMessageTypePlayer,
+		Payload: cw.disconnectMessage,
+	})
+	if err != nil {
+		cw.logger.Errorln("encode disconnect message error:", err)
+	} else if err := cw.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+		cw.logger.Errorln("write disconnect message error:", err)
+	}
+
+	message := websocket.FormatCloseMessage(cw.disconnectCode, truncateCloseReason(cw.disconnectReason))
+	if err := cw.conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(kickCloseTimeout)); err != nil {
+		cw.logger.Errorln("write close message error:", err)
+	}
+
+	if err := cw.conn.Close(); err != nil {
+		cw.logger.WithError(err).Error("close connection error")
+	}
+}
+
+// truncateCloseReason cuts the reason to fit a close frame. The reason is cut
+// on a rune boundary to remain valid UTF-8
+func truncateCloseReason(reason string) string {
+	if len(reason) <= closeReasonMaxLength {
+		return reason
+	}
+
+	n := closeReasonMaxLength
+	for n > 0 && !utf8.RuneStart(reason[n]) {
+		n--
+	}
+
+	return reason[:n]
+}
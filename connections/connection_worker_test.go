@@ -0,0 +1,43 @@
+package connections
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_truncateCloseReason(t *testing.T) {
+	tests := []struct {
+		reason   string
+		expected string
+	}{
+		// Test case 1
+		{
+			reason:   "maintenance",
+			expected: "maintenance",
+		},
+		// Test case 2
+		{
+			reason:   strings.Repeat("a", closeReasonMaxLength+1),
+			expected: strings.Repeat("a", closeReasonMaxLength),
+		},
+		// Test case 3
+		{
+			reason:   strings.Repeat("a", closeReasonMaxLength-1) + "ж",
+			expected: strings.Repeat("a", closeReasonMaxLength-1),
+		},
+		// Test case 4
+		{
+			reason:   strings.Repeat("ж", closeReasonMaxLength),
+			expected: strings.Repeat("ж", closeReasonMaxLength/2),
+		},
+	}
+
+	for i, test := range tests {
+		reason := truncateCloseReason(test.reason)
+		require.Equal(t, test.expected, reason, "test case %d", i+1)
+		require.True(t, utf8.ValidString(reason), "test case %d", i+1)
+	}
+}
@@ -0,0 +1,64 @@
+package connections
+
+import "github.com/ivan1993spb/snake-server/world"
+
+type GroupEventType uint8
+
+const (
+	GroupEventTypeGameCreated GroupEventType = iota
+	GroupEventTypeGameDeleted
+	GroupEventTypePlayerJoined
+	GroupEventTypePlayerLeft
+	GroupEventTypeSnakeDied
+	GroupEventTypeMatchFinished
+)
+
+var groupEventTypeLabels = map[GroupEventType]string{
+	GroupEventTypeGameCreated:   "game_created",
+	GroupEventTypeGameDeleted:   "game_deleted",
+	GroupEventTypePlayerJoined:  "player_joined",
+	GroupEventTypePlayerLeft:    "player_left",
+	GroupEventTypeSnakeDied:     "snake_died",
+	GroupEventTypeMatchFinished: "match_finished",
+}
+
+func (t GroupEventType) String() string {
+	if label, ok := groupEventTypeLabels[t]; ok {
+		return label
+	}
+	return "unknown"
+}
+
+func (t GroupEventType) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + t.String() + `"`), nil
+}
+
+// GroupEvent is a lifecycle event of a game group
+type GroupEvent struct {
+	Type    GroupEventType
+	GameID  int
+	Payload interface{}
+}
+
+// GroupEventGameCreated is the payload of the game created event
+type GroupEventGameCreated struct {
+	Limit  int   `json:"limit"`
+	Width  uint8 `json:"width"`
+	Height uint8 `json:"height"`
+}
+
+// GroupEventPlayers is the payload of player joined and player left events
+type GroupEventPlayers struct {
+	Count int `json:"count"`
+}
+
+// GroupEventSnakeDied is the payload of the snake died event
+type GroupEventSnakeDied struct {
+	SnakeID world.Identifier `json:"snake_id"`
+}
+
+// GroupEventMatchFinished is the payload of the match finished event. The
+// winner identifier is zero if nobody has survived
+type GroupEventMatchFinished struct {
+	WinnerID world.Identifier `json:"winner_id"`
+}
@@ -0,0 +1,346 @@
+package connections
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ivan1993spb/snake-server/engine"
+	"github.com/ivan1993spb/snake-server/game"
+	"github.com/ivan1993spb/snake-server/objects/apple"
+	"github.com/ivan1993spb/snake-server/objects/corpse"
+	"github.com/ivan1993spb/snake-server/objects/fruit"
+	"github.com/ivan1993spb/snake-server/objects/goldenapple"
+	"github.com/ivan1993spb/snake-server/objects/mouse"
+	"github.com/ivan1993spb/snake-server/objects/poison"
+	"github.com/ivan1993spb/snake-server/objects/snake"
+	"github.com/ivan1993spb/snake-server/objects/wall"
+	"github.com/ivan1993spb/snake-server/objects/watermelon"
+)
+
+const (
+	metricServerGamesEventsFQName        = "server_games_events_total"
+	metricServerGamesSnakesSpawnedFQName = "server_games_snakes_spawned_total"
+	metricServerGamesSnakesDiedFQName    = "server_games_snakes_died_total"
+	metricServerGamesFoodEatenFQName     = "server_games_food_eaten_total"
+	metricServerGamesObjectsFQName       = "server_games_objects"
+	metricServerGamesSendTimeoutsFQName  = "server_games_send_timeouts_total"
+	metricServerGamesBufferFillFQName    = "server_games_buffer_fill_ratio"
+	metricServerGamesWriteLatencyFQName  = "server_games_ws_write_duration_seconds"
+	metricServerGamesBackpressureFQName  = "server_games_backpressure_actions_total"
+	metricServerGamesDiscardedFQName     = "server_games_messages_discarded_total"
+
+	metricServerGamesEventsHelp        = "Game events by type"
+	metricServerGamesSnakesSpawnedHelp = "Snakes spawned"
+	metricServerGamesSnakesDiedHelp    = "Snakes died"
+	metricServerGamesFoodEatenHelp     = "Food eaten by snakes by type"
+	metricServerGamesObjectsHelp       = "Objects on the map by type"
+	metricServerGamesSendTimeoutsHelp  = "Messages not sent to connections in time by stage"
+	metricServerGamesBufferFillHelp    = "Fill level of message buffers"
+	metricServerGamesWriteLatencyHelp  = "Latency of web-socket writes"
+	metricServerGamesBackpressureHelp  = "Backpressure actions applied to slow connections"
+	metricServerGamesDiscardedHelp     = "Game messages discarded for lagging connections"
+
+	metricGameIdLabel = "game_id"
+	metricTypeLabel   = "type"
+	metricStageLabel  = "stage"
+	metricBufferLabel = "buffer"
+	metricActionLabel = "action"
+)
+
+const sendTimeoutStageGroup = "group"
+
+const (
+	bufferGameMessages      = "game"
+	bufferBroadcastMessages = "broadcast"
+	bufferEncodedMessages   = "encoded"
+	bufferBatchedMessages   = "batched"
+	bufferPreparedMessages  = "prepared"
+	bufferConnectionsMax    = "connections_max"
+)
+
+const objectTypeUnknown = "unknown"
+
+var writeLatencyBuckets = []float64{0.0001, 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1}
+
+var (
+	metricServerGamesEventsDesc = prometheus.NewDesc(
+		metricServerGamesEventsFQName,
+		metricServerGamesEventsHelp,
+		[]string{metricGameIdLabel, metricTypeLabel},
+		nil,
+	)
+	metricServerGamesSnakesSpawnedDesc = prometheus.NewDesc(
+		metricServerGamesSnakesSpawnedFQName,
+		metricServerGamesSnakesSpawnedHelp,
+		[]string{metricGameIdLabel},
+		nil,
+	)
+	metricServerGamesSnakesDiedDesc = prometheus.NewDesc(
+		metricServerGamesSnakesDiedFQName,
+		metricServerGamesSnakesDiedHelp,
+		[]string{metricGameIdLabel},
+		nil,
+	)
+	metricServerGamesFoodEatenDesc = prometheus.NewDesc(
+		metricServerGamesFoodEatenFQName,
+		metricServerGamesFoodEatenHelp,
+		[]string{metricGameIdLabel, metricTypeLabel},
+		nil,
+	)
+	metricServerGamesObjectsDesc = prometheus.NewDesc(
+		metricServerGamesObjectsFQName,
+		metricServerGamesObjectsHelp,
+		[]string{metricGameIdLabel, metricTypeLabel},
+		nil,
+	)
+	metricServerGamesSendTimeoutsDesc = prometheus.NewDesc(
+		metricServerGamesSendTimeoutsFQName,
+		metricServerGamesSendTimeoutsHelp,
+		[]string{metricGameIdLabel, metricStageLabel},
+		nil,
+	)
+	metricServerGamesBufferFillDesc = prometheus.NewDesc(
+		metricServerGamesBufferFillFQName,
+		metricServerGamesBufferFillHelp,
+		[]string{metricGameIdLabel, metricBufferLabel},
+		nil,
+	)
+	metricServerGamesWriteLatencyDesc = prometheus.NewDesc(
+		metricServerGamesWriteLatencyFQName,
+		metricServerGamesWriteLatencyHelp,
+		[]string{metricGameIdLabel},
+		nil,
+	)
+	metricServerGamesBackpressureDesc = prometheus.NewDesc(
+		metricServerGamesBackpressureFQName,
+		metricServerGamesBackpressureHelp,
+		[]string{metricGameIdLabel, metricActionLabel},
+		nil,
+	)
+	metricServerGamesDiscardedDesc = prometheus.NewDesc(
+		metricServerGamesDiscardedFQName,
+		metricServerGamesDiscardedHelp,
+		[]string{metricGameIdLabel},
+		nil,
+	)
+)
+
+var groupMetricsDescriptors = [...]*prometheus.Desc{
+	metricServerGamesEventsDesc,
+	metricServerGamesSnakesSpawnedDesc,
+	metricServerGamesSnakesDiedDesc,
+	metricServerGamesFoodEatenDesc,
+	metricServerGamesObjectsDesc,
+	metricServerGamesSendTimeoutsDesc,
+	metricServerGamesBufferFillDesc,
+	metricServerGamesWriteLatencyDesc,
+	metricServerGamesBackpressureDesc,
+	metricServerGamesDiscardedDesc,
+}
+
+// objectTypeLabel returns the type label of a game object as it is named in
+// the JSON representation
+func objectTypeLabel(object interface{}) string {
+	switch object.(type) {
+	case *apple.Apple:
+		return "apple"
+	case *corpse.Corpse:
+		return "corpse"
+	case *fruit.Fruit:
+		return "fruit"
+	case *goldenapple.GoldenApple:
+		return "golden_apple"
+	case *mouse.Mouse:
+		return "mouse"
+	case *poison.Poison:
+		return "poison"
+	case *snake.Snake:
+		return "snake"
+	case *wall.Wall:
+		return "wall"
+	case *watermelon.Watermelon:
+		return "watermelon"
+	}
+	return objectTypeUnknown
+}
+
+type bufferFill func() (length, capacity int)
+
+// groupMetrics counts what happens in a group to be exported by the
+// prometheus collector of the group manager. A nil *groupMetrics ignores
+// observations
+type groupMetrics struct {
+	events        map[string]uint64
+	snakesSpawned uint64
+	snakesDied    uint64
+	foodEaten     map[string]uint64
+	sendTimeouts  map[string]uint64
+	backpressure  map[string]uint64
+	discarded     uint64
+
+	buffers map[string]bufferFill
+
+	writeCount   uint64
+	writeSum     float64
+	writeBuckets []uint64
+
+	mux *sync.Mutex
+}
+
+func newGroupMetrics() *groupMetrics {
+	return &groupMetrics{
+		events:       map[string]uint64{},
+		foodEaten:    map[string]uint64{},
+		sendTimeouts: map[string]uint64{},
+		backpressure: map[string]uint64{},
+		buffers:      map[string]bufferFill{},
+		writeBuckets: make([]uint64, len(writeLatencyBuckets)),
+		mux:          &sync.Mutex{},
+	}
+}
+
+// observeEvent counts a game event
+func (gm *groupMetrics) observeEvent(event game.Event) {
+	if gm == nil {
+		return
+	}
+
+	gm.mux.Lock()
+	defer gm.mux.Unlock()
+
+	gm.events[event.Type.String()]++
+
+	switch event.Type {
+	case game.EventTypeObjectCreate:
+		if _, ok := event.Payload.(*snake.Snake); ok {
+			gm.snakesSpawned++
+		}
+	case game.EventTypeObjectDelete:
+		if _, ok := event.Payload.(*snake.Snake); ok {
+			gm.snakesDied++
+		}
+	case game.EventTypeObjectEat:
+		gm.foodEaten[objectTypeLabel(event.Payload)]++
+	}
+}
+
+// observeSendTimeout counts a message which was not sent in time
+func (gm *groupMetrics) observeSendTimeout(stage string) {
+	if gm == nil {
+		return
+	}
+
+	gm.mux.Lock()
+	gm.sendTimeouts[stage]++
+	gm.mux.Unlock()
+}
+
+// observeBackpressure counts an action applied to a slow connection
+func (gm *groupMetrics) observeBackpressure(action string) {
+	if gm == nil {
+		return
+	}
+
+	gm.mux.Lock()
+	gm.backpressure[action]++
+	gm.mux.Unlock()
+}
+
+// observeDiscard counts game messages discarded for a lagging connection
+func (gm *groupMetrics) observeDiscard(n uint64) {
+	if gm == nil {
+		return
+	}
+
+	gm.mux.Lock()
+	gm.discarded += n
+	gm.mux.Unlock()
+}
+
+// observeWrite records latency of a web-socket write
+func (gm *groupMetrics) observeWrite(d time.Duration) {
+	if gm == nil {
+		return
+	}
+
+	seconds := d.Seconds()
+
+	gm.mux.Lock()
+	defer gm.mux.Unlock()
+
+	gm.writeCount++
+	gm.writeSum += seconds
+	for i, bound := range writeLatencyBuckets {
+		if seconds <= bound {
+			gm.writeBuckets[i]++
+		}
+	}
+}
+
+// watchBuffer registers a buffer to report its fill level
+func (gm *groupMetrics) watchBuffer(name string, fill bufferFill) {
+	if gm == nil {
+		return
+	}
+
+	gm.mux.Lock()
+	gm.buffers[name] = fill
+	gm.mux.Unlock()
+}
+
+type metricSender func(desc *prometheus.Desc, valueType prometheus.ValueType, value float64, labelValues ...string)
+
+func sendCounters(send metricSender, desc *prometheus.Desc, gameId string, counters map[string]uint64) {
+	for label, value := range counters {
+		send(desc, prometheus.CounterValue, float64(value), gameId, label)
+	}
+}
+
+// collect sends metrics of the group
+func (gm *groupMetrics) collect(ch chan<- prometheus.Metric, send metricSender, gameId string) error {
+	if gm == nil {
+		return nil
+	}
+
+	gm.mux.Lock()
+	defer gm.mux.Unlock()
+
+	sendCounters(send, metricServerGamesEventsDesc, gameId, gm.events)
+	send(metricServerGamesSnakesSpawnedDesc, prometheus.CounterValue, float64(gm.snakesSpawned), gameId)
+	send(metricServerGamesSnakesDiedDesc, prometheus.CounterValue, float64(gm.snakesDied), gameId)
+	sendCounters(send, metricServerGamesFoodEatenDesc, gameId, gm.foodEaten)
+	sendCounters(send, metricServerGamesSendTimeoutsDesc, gameId, gm.sendTimeouts)
+	sendCounters(send, metricServerGamesBackpressureDesc, gameId, gm.backpressure)
+	send(metricServerGamesDiscardedDesc, prometheus.CounterValue, float64(gm.discarded), gameId)
+
+	for name, fill := range gm.buffers {
+		length, capacity := fill()
+		if capacity > 0 {
+			send(metricServerGamesBufferFillDesc, prometheus.GaugeValue, float64(length)/float64(capacity), gameId, name)
+		}
+	}
+
+	buckets := make(map[float64]uint64, len(writeLatencyBuckets))
+	for i, bound := range writeLatencyBuckets {
+		buckets[bound] = gm.writeBuckets[i]
+	}
+
+	metric, err := prometheus.NewConstHistogram(metricServerGamesWriteLatencyDesc, gm.writeCount, gm.writeSum, buckets, gameId)
+	if err != nil {
+		return err
+	}
+	ch <- metric
+
+	return nil
+}
+
+// countObjects returns numbers of objects by type labels
+func countObjects(objects []engine.Object) map[string]int {
+	counts := map[string]int{}
+	for _, object := range objects {
+		counts[objectTypeLabel(object)]++
+	}
+	return counts
+}
@@ -0,0 +1,95 @@
+package connections
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivan1993spb/snake-server/game"
+	"github.com/ivan1993spb/snake-server/objects/apple"
+	"github.com/ivan1993spb/snake-server/objects/snake"
+)
+
+func Test_ConnectionGroupManager_Collect_GameMetrics(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+
+	m, err := NewConnectionGroupManager(logger, 10, 100)
+	require.Nil(t, err)
+
+	group, err := NewConnectionGroup(logger, 10, 10, 10, GroupConfig{})
+	require.Nil(t, err)
+
+	_, err = m.Add(group)
+	require.Nil(t, err)
+
+	group.metrics.observeEvent(game.Event{Type: game.EventTypeObjectCreate, Payload: &snake.Snake{}})
+	group.metrics.observeEvent(game.Event{Type: game.EventTypeObjectDelete, Payload: &snake.Snake{}})
+	group.metrics.observeEvent(game.Event{Type: game.EventTypeObjectDelete, Payload: &apple.Apple{}})
+	group.metrics.observeEvent(game.Event{Type: game.EventTypeObjectEat, Payload: &apple.Apple{}})
+	group.metrics.observeEvent(game.Event{Type: game.EventTypeObjectEat, Payload: &apple.Apple{}})
+	group.metrics.observeSendTimeout(sendTimeoutStageGroup)
+	group.metrics.observeBackpressure(backpressureActionLag)
+	group.metrics.observeBackpressure(backpressureActionResync)
+	group.metrics.observeDiscard(7)
+	group.metrics.observeWrite(time.Millisecond * 3)
+	group.metrics.watchBuffer(bufferGameMessages, func() (int, int) {
+		return 2, 8
+	})
+
+	registry := prometheus.NewPedanticRegistry()
+	require.Nil(t, registry.Register(m))
+
+	families, err := registry.Gather()
+	require.Nil(t, err)
+
+	metrics := map[string][]*dto.Metric{}
+	for _, family := range families {
+		metrics[family.GetName()] = family.GetMetric()
+	}
+
+	label := func(metric *dto.Metric, name string) string {
+		for _, pair := range metric.GetLabel() {
+			if pair.GetName() == name {
+				return pair.GetValue()
+			}
+		}
+		return ""
+	}
+
+	require.Len(t, metrics[metricServerGamesEventsFQName], 3)
+	require.Equal(t, float64(1), metrics[metricServerGamesSnakesSpawnedFQName][0].GetCounter().GetValue())
+	require.Equal(t, float64(1), metrics[metricServerGamesSnakesDiedFQName][0].GetCounter().GetValue())
+
+	require.Len(t, metrics[metricServerGamesFoodEatenFQName], 1)
+	require.Equal(t, "apple", label(metrics[metricServerGamesFoodEatenFQName][0], metricTypeLabel))
+	require.Equal(t, float64(2), metrics[metricServerGamesFoodEatenFQName][0].GetCounter().GetValue())
+
+	require.Len(t, metrics[metricServerGamesSendTimeoutsFQName], 1)
+	require.Equal(t, sendTimeoutStageGroup, label(metrics[metricServerGamesSendTimeoutsFQName][0], metricStageLabel))
+
+	require.Len(t, metrics[metricServerGamesBackpressureFQName], 2)
+	require.Equal(t, float64(7), metrics[metricServerGamesDiscardedFQName][0].GetCounter().GetValue())
+
+	require.Len(t, metrics[metricServerGamesBufferFillFQName], 1)
+	require.Equal(t, 0.25, metrics[metricServerGamesBufferFillFQName][0].GetGauge().GetValue())
+
+	histogram := metrics[metricServerGamesWriteLatencyFQName][0].GetHistogram()
+	require.Equal(t, uint64(1), histogram.GetSampleCount())
+	for _, bucket := range histogram.GetBucket() {
+		if bucket.GetUpperBound() < 0.003 {
+			require.Equal(t, uint64(0), bucket.GetCumulativeCount())
+		} else {
+			require.Equal(t, uint64(1), bucket.GetCumulativeCount())
+		}
+	}
+}
+
+func Test_objectTypeLabel(t *testing.T) {
+	require.Equal(t, "apple", objectTypeLabel(&apple.Apple{}))
+	require.Equal(t, "snake", objectTypeLabel(&snake.Snake{}))
+	require.Equal(t, objectTypeUnknown, objectTypeLabel(struct{}{}))
+}
@@ -0,0 +1,167 @@
+package connections
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ivan1993spb/snake-server/game"
+	"github.com/ivan1993spb/snake-server/objects/corpse"
+	"github.com/ivan1993spb/snake-server/objects/snake"
+)
+
+const (
+	MinMapWidth  = 8
+	MinMapHeight = 8
+)
+
+const (
+	DefaultSpeedCurve = snake.SpeedCurveConstant
+	DefaultHeadToHead = snake.CollisionRuleForce
+	DefaultHeadToBody = snake.CollisionRuleForce
+	DefaultSelfBite   = snake.CollisionRuleDie
+)
+
+const (
+	MinCorpseLifetime = time.Second
+	MaxCorpseLifetime = time.Minute
+)
+
+// MaxBatchWindow limits the delay which batching adds to game messages
+const MaxBatchWindow = maxBatchWindow
+
+// ErrGroupParams is returned if parameters of a new group are invalid. The
+// text of the error can be shown to the client which has passed them
+type ErrGroupParams string
+
+func (e ErrGroupParams) Error() string {
+	return string(e)
+}
+
+var (
+	ErrInvalidLimit          = ErrGroupParams("invalid limit")
+	ErrInvalidMapWidth       = ErrGroupParams(fmt.Sprintf("map width must be from %d to %d", MinMapWidth, math.MaxUint8))
+	ErrInvalidMapHeight      = ErrGroupParams(fmt.Sprintf("map height must be from %d to %d", MinMapHeight, math.MaxUint8))
+	ErrInvalidBatchWindow    = ErrGroupParams(fmt.Sprintf("batch window must be from 0 to %d milliseconds", MaxBatchWindow/time.Millisecond))
+	ErrInvalidSpeedCurve     = ErrGroupParams(fmt.Sprintf("speed curve must be one of: %s", strings.Join(snake.SpeedCurveNames(), ", ")))
+	ErrInvalidHeadToHead     = ErrGroupParams(fmt.Sprintf("head to head rule must be one of: %s", strings.Join(snake.HeadToHeadRuleNames(), ", ")))
+	ErrInvalidHeadToBody     = ErrGroupParams(fmt.Sprintf("head to body rule must be one of: %s", strings.Join(snake.HeadToBodyRuleNames(), ", ")))
+	ErrInvalidSelfBite       = ErrGroupParams(fmt.Sprintf("self bite rule must be one of: %s", strings.Join(snake.SelfBiteRuleNames(), ", ")))
+	ErrInvalidCorpseLifetime = ErrGroupParams(fmt.Sprintf("corpse lifetime must be from %d to %d seconds", MinCorpseLifetime/time.Second, MaxCorpseLifetime/time.Second))
+)
+
+// GroupParams are the parameters of a new group as they are passed by
+// clients of the API. Empty names select the default rules, the zero corpse
+// lifetime selects the default lifetime
+type GroupParams struct {
+	Limit  int
+	Width  int
+	Height int
+
+	EnableWalls        bool
+	EnablePoison       bool
+	EnableGoldenApples bool
+	EnableFruits       bool
+	BattleRoyale       bool
+
+	BatchWindow time.Duration
+
+	SpeedCurve  string
+	EnableBoost bool
+
+	HeadToHead string
+	HeadToBody string
+	SelfBite   string
+
+	CorpseLifetime time.Duration
+	CorpseDecay    bool
+}
+
+// Config validates the parameters and returns the configuration of the group.
+// The error is of the type ErrGroupParams
+func (p GroupParams) Config() (GroupConfig, error) {
+	if p.Limit < minimalConnectionLimit {
+		return GroupConfig{}, ErrInvalidLimit
+	}
+	if p.Width < MinMapWidth || p.Width > math.MaxUint8 {
+		return GroupConfig{}, ErrInvalidMapWidth
+	}
+	if p.Height < MinMapHeight || p.Height > math.MaxUint8 {
+		return GroupConfig{}, ErrInvalidMapHeight
+	}
+	if p.BatchWindow < 0 || p.BatchWindow > MaxBatchWindow {
+		return GroupConfig{}, ErrInvalidBatchWindow
+	}
+	if p.CorpseLifetime != 0 && (p.CorpseLifetime < MinCorpseLifetime || p.CorpseLifetime > MaxCorpseLifetime) {
+		return GroupConfig{}, ErrInvalidCorpseLifetime
+	}
+
+	speedCurve, ok := snake.GetSpeedCurve(nameOrDefault(p.SpeedCurve, DefaultSpeedCurve))
+	if !ok {
+		return GroupConfig{}, ErrInvalidSpeedCurve
+	}
+
+	headToHead, ok := snake.GetHeadToHeadRule(nameOrDefault(p.HeadToHead, DefaultHeadToHead))
+	if !ok {
+		return GroupConfig{}, ErrInvalidHeadToHead
+	}
+
+	headToBody, ok := snake.GetHeadToBodyRule(nameOrDefault(p.HeadToBody, DefaultHeadToBody))
+	if !ok {
+		return GroupConfig{}, ErrInvalidHeadToBody
+	}
+
+	selfBite, ok := snake.GetSelfBiteRule(nameOrDefault(p.SelfBite, DefaultSelfBite))
+	if !ok {
+		return GroupConfig{}, ErrInvalidSelfBite
+	}
+
+	snakeConfig := snake.Config{
+		Speed: speedCurve,
+		Collision: snake.CollisionConfig{
+			HeadToHead: headToHead,
+			HeadToBody: headToBody,
+			SelfBite:   selfBite,
+		},
+		Corpse: corpse.Decay{
+			Lifetime: p.CorpseLifetime,
+			Gradual:  p.CorpseDecay,
+		},
+	}
+	if p.EnableBoost {
+		snakeConfig.Boost = snake.DefaultBoost
+	}
+
+	return GroupConfig{
+		Game: game.Config{
+			EnableWalls:        p.EnableWalls,
+			EnablePoison:       p.EnablePoison,
+			EnableGoldenApples: p.EnableGoldenApples,
+			EnableFruits:       p.EnableFruits,
+			BattleRoyale:       p.BattleRoyale,
+			Snake:              snakeConfig,
+		},
+		BatchWindow: p.BatchWindow,
+	}, nil
+}
+
+// NewConnectionGroupParams creates a group with the parameters passed by a
+// client of the API
+func NewConnectionGroupParams(logger logrus.FieldLogger, params GroupParams) (*ConnectionGroup, error) {
+	config, err := params.Config()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewConnectionGroup(logger, params.Limit, uint8(params.Width), uint8(params.Height), config)
+}
+
+func nameOrDefault(name, defaultName string) string {
+	if name == "" {
+		return defaultName
+	}
+	return name
+}
@@ -0,0 +1,102 @@
+package connections
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivan1993spb/snake-server/objects/snake"
+)
+
+func Test_GroupParams_Config_ValidatesParams(t *testing.T) {
+	tests := []struct {
+		params GroupParams
+		err    error
+	}{
+		// Test case 1
+		{
+			params: GroupParams{Limit: 10, Width: 40, Height: 30},
+			err:    nil,
+		},
+		// Test case 2
+		{
+			params: GroupParams{Limit: 0, Width: 40, Height: 30},
+			err:    ErrInvalidLimit,
+		},
+		// Test case 3
+		{
+			params: GroupParams{Limit: 10, Width: 4, Height: 30},
+			err:    ErrInvalidMapWidth,
+		},
+		// Test case 4
+		{
+			params: GroupParams{Limit: 10, Width: 40, Height: 300},
+			err:    ErrInvalidMapHeight,
+		},
+		// Test case 5
+		{
+			params: GroupParams{Limit: 10, Width: 40, Height: 30, BatchWindow: time.Millisecond * 1001},
+			err:    ErrInvalidBatchWindow,
+		},
+		// Test case 6
+		{
+			params: GroupParams{Limit: 10, Width: 40, Height: 30, SpeedCurve: "warp"},
+			err:    ErrInvalidSpeedCurve,
+		},
+		// Test case 7
+		{
+			params: GroupParams{Limit: 10, Width: 40, Height: 30, HeadToHead: "nobody_dies"},
+			err:    ErrInvalidHeadToHead,
+		},
+		// Test case 8
+		{
+			params: GroupParams{Limit: 10, Width: 40, Height: 30, HeadToBody: "longer_wins"},
+			err:    ErrInvalidHeadToBody,
+		},
+		// Test case 9
+		{
+			params: GroupParams{Limit: 10, Width: 40, Height: 30, SelfBite: "sever"},
+			err:    ErrInvalidSelfBite,
+		},
+		// Test case 10
+		{
+			params: GroupParams{Limit: 10, Width: 40, Height: 30, CorpseLifetime: time.Second * 61},
+			err:    ErrInvalidCorpseLifetime,
+		},
+		// Test case 11
+		{
+			params: GroupParams{Limit: 10, Width: 40, Height: 30, CorpseLifetime: time.Millisecond},
+			err:    ErrInvalidCorpseLifetime,
+		},
+	}
+
+	for i, test := range tests {
+		_, err := test.params.Config()
+		require.Equal(t, test.err, err, "test case %d", i+1)
+	}
+}
+
+func Test_GroupParams_Config_ReturnsConfig(t *testing.T) {
+	config, err := GroupParams{
+		Limit:          10,
+		Width:          40,
+		Height:         30,
+		EnableWalls:    true,
+		BattleRoyale:   true,
+		BatchWindow:    time.Millisecond * 25,
+		EnableBoost:    true,
+		HeadToBody:     snake.CollisionRuleSever,
+		CorpseLifetime: time.Second * 30,
+		CorpseDecay:    true,
+	}.Config()
+	require.Nil(t, err)
+
+	require.True(t, config.Game.EnableWalls)
+	require.True(t, config.Game.BattleRoyale)
+	require.Equal(t, time.Millisecond*25, config.BatchWindow)
+	require.Equal(t, snake.DefaultBoost, config.Game.Snake.Boost)
+	require.Equal(t, time.Second*30, config.Game.Snake.Corpse.Lifetime)
+	require.True(t, config.Game.Snake.Corpse.Gradual)
+	require.NotNil(t, config.Game.Snake.Speed)
+}
@@ -0,0 +1,103 @@
+package connections
+
+import (
+	"errors"
+	"sort"
+)
+
+const (
+	GroupsSortingSmart  = "smart"
+	GroupsSortingRandom = "random"
+)
+
+const groupsSortingDefault = GroupsSortingRandom
+
+var ErrInvalidGroupsSorting = errors.New("invalid sorting")
+
+// GroupInfo describes the state of a group in a list of groups
+type GroupInfo struct {
+	ID     int
+	Limit  int
+	Count  int
+	Width  uint8
+	Height uint8
+	Rate   uint32
+}
+
+// ListGroups returns the groups ordered by the sorting. The empty sorting
+// selects the default one. If the limit is not negative, no more than limit
+// groups are returned
+func (m *ConnectionGroupManager) ListGroups(sorting string, limit int) ([]GroupInfo, error) {
+	if sorting == "" {
+		sorting = groupsSortingDefault
+	}
+	if sorting != GroupsSortingSmart && sorting != GroupsSortingRandom {
+		return nil, ErrInvalidGroupsSorting
+	}
+
+	groups := m.Groups()
+	infos := make([]GroupInfo, 0, len(groups))
+
+	for id, group := range groups {
+		infos = append(infos, GroupInfo{
+			ID:     id,
+			Limit:  group.GetLimit(),
+			Count:  group.GetCount(),
+			Width:  group.GetWorldWidth(),
+			Height: group.GetWorldHeight(),
+			Rate:   group.GetRate(),
+		})
+	}
+
+	if sorting == GroupsSortingSmart {
+		sortGroupsSmart(infos)
+	}
+
+	if limit >= 0 && limit < len(infos) {
+		infos = infos[:limit]
+	}
+
+	return infos, nil
+}
+
+// sortGroupsSmart puts groups with players and free places first, then empty
+// groups, then full groups
+func sortGroupsSmart(infos []GroupInfo) {
+	emptyGroups := filterGroups(infos, func(info GroupInfo) bool {
+		return info.Count == 0
+	})
+
+	fullGroups := filterGroups(infos, func(info GroupInfo) bool {
+		return info.Count == info.Limit
+	})
+
+	relevantGroups := filterGroups(infos, func(info GroupInfo) bool {
+		return info.Count > 0 && info.Count < info.Limit
+	})
+
+	sort.Slice(emptyGroups, func(i, j int) bool {
+		return emptyGroups[i].Rate < emptyGroups[j].Rate
+	})
+
+	sort.Slice(fullGroups, func(i, j int) bool {
+		return fullGroups[i].Limit < fullGroups[j].Limit
+	})
+
+	sort.Slice(relevantGroups, func(i, j int) bool {
+		return relevantGroups[i].Count < relevantGroups[j].Count
+	})
+
+	copy(infos, relevantGroups)
+	copy(infos[len(relevantGroups):], emptyGroups)
+	copy(infos[len(relevantGroups)+len(emptyGroups):], fullGroups)
+}
+
+func filterGroups(infos []GroupInfo, filter func(info GroupInfo) bool) []GroupInfo {
+	result := make([]GroupInfo, 0)
+	for _, info := range infos {
+		if filter(info) {
+			result = append(result, info)
+		}
+	}
+	return result
+}
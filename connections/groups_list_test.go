@@ -0,0 +1,48 @@
+package connections
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ConnectionGroupManager_ListGroups_SortsAndLimitsGroups(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	m, err := NewConnectionGroupManager(logger, 5, 100)
+	require.Nil(t, err)
+
+	// Players of the groups: empty, full, with free places
+	for _, count := range []int{0, 2, 1} {
+		group, err := NewConnectionGroup(logger, 2, 20, 20, GroupConfig{})
+		require.Nil(t, err)
+		group.counter = count
+
+		_, err = m.Add(group)
+		require.Nil(t, err)
+	}
+
+	infos, err := m.ListGroups("", -1)
+	require.Nil(t, err)
+	require.Len(t, infos, 3)
+
+	infos, err = m.ListGroups(GroupsSortingSmart, -1)
+	require.Nil(t, err)
+	require.Len(t, infos, 3)
+	require.Equal(t, []int{1, 0, 2}, []int{infos[0].Count, infos[1].Count, infos[2].Count})
+	require.Equal(t, uint8(20), infos[0].Width)
+
+	infos, err = m.ListGroups(GroupsSortingSmart, 1)
+	require.Nil(t, err)
+	require.Len(t, infos, 1)
+	require.Equal(t, 3, infos[0].ID)
+
+	infos, err = m.ListGroups(GroupsSortingRandom, 0)
+	require.Nil(t, err)
+	require.Empty(t, infos)
+
+	_, err = m.ListGroups("invalid", -1)
+	require.Equal(t, ErrInvalidGroupsSorting, err)
+}
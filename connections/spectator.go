@@ -0,0 +1,105 @@
+package connections
+
+import (
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/pquerna/ffjson/ffjson"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ivan1993spb/snake-server/player"
+)
+
+const (
+	chanSpectatorGameEventsBuffer = 512
+
+	spectatorWriteTimeout = time.Second * 5
+
+	spectatorCloseReason = "game is closed"
+)
+
+// Spectate streams the game to the web-socket connection without occupying a
+// place in the group and without creating a snake. The map size and all
+// objects are sent first, then game events. Messages of the spectator are
+// ignored. Spectate returns when the connection is lost or the group closes
+func (cg *ConnectionGroup) Spectate(conn *websocket.Conn, logger logrus.FieldLogger) error {
+	defer conn.Close()
+
+	disconnected := make(chan struct{})
+
+	go func() {
+		defer close(disconnected)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	// Listen before the snapshot is taken to not miss events
+	events := cg.ListenGameEvents(stop, chanSpectatorGameEventsBuffer)
+
+	if err := writeSpectatorMessage(conn, OutputMessage{
+		Type:    OutputMessageTypePlayer,
+		Payload: player.NewMessageSize(cg.GetWorldWidth(), cg.GetWorldHeight()),
+	}); err != nil {
+		return err
+	}
+
+	if err := writeSpectatorMessage(conn, OutputMessage{
+		Type:    OutputMessageTypePlayer,
+		Payload: player.NewMessageObjects(cg.GetObjects()),
+	}); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if err := writeSpectatorMessage(conn, OutputMessage{
+				Type:    OutputMessageTypeGame,
+				Payload: event,
+			}); err != nil {
+				return err
+			}
+		case <-disconnected:
+			logger.Info("spectator disconnected")
+			return nil
+		case <-cg.closing:
+			logger.Info("close spectator connection")
+			return writeSpectatorClose(conn)
+		}
+	}
+}
+
+func writeSpectatorMessage(conn *websocket.Conn, message OutputMessage) error {
+	data, err := ffjson.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	if err := conn.SetWriteDeadline(time.Now().Add(spectatorWriteTimeout)); err != nil {
+		return err
+	}
+
+	return conn.WriteMessage(websocket.TextMessage, data)
+}
+
+func writeSpectatorClose(conn *websocket.Conn) error {
+	if err := writeSpectatorMessage(conn, OutputMessage{
+		Type:    OutputMessageTypePlayer,
+		Payload: player.NewMessageGameClosed(spectatorCloseReason),
+	}); err != nil {
+		return err
+	}
+
+	message := websocket.FormatCloseMessage(websocket.CloseGoingAway, spectatorCloseReason)
+
+	return conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(kickCloseTimeout))
+}
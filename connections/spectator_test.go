@@ -0,0 +1,65 @@
+package connections
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ConnectionGroup_Spectate_TakesNoPlaceAndClosesWithGroup(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	group, err := NewConnectionGroup(logger, 1, 20, 20, GroupConfig{})
+	require.Nil(t, err)
+	group.Start()
+	defer group.Stop()
+
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		require.Nil(t, err)
+		group.Spectate(conn, logger)
+	}))
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.Nil(t, err)
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+
+	_, data, err := conn.ReadMessage()
+	require.Nil(t, err)
+	require.Equal(t, `{"type":"player","payload":{"type":"size","payload":{"width":20,"height":20}}}`, string(data))
+
+	_, data, err = conn.ReadMessage()
+	require.Nil(t, err)
+	require.True(t, strings.HasPrefix(string(data), `{"type":"player","payload":{"type":"objects","payload":`), string(data))
+
+	require.True(t, group.IsEmpty())
+	require.False(t, group.IsFull())
+	require.Empty(t, group.GetConnections())
+
+	go group.Close("maintenance", time.Second)
+
+	var closed bool
+	conn.SetReadDeadline(time.Now().Add(time.Second * 5))
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			require.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), err.Error())
+			break
+		}
+		if string(data) == `{"type":"player","payload":{"type":"game_closed","payload":"game is closed"}}` {
+			closed = true
+		}
+	}
+	require.True(t, closed, "game closed message has not been received")
+}
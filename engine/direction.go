@@ -137,3 +137,37 @@ func (dir Direction) Reverse() (Direction, error) {
 		},
 	}
 }
+
+type ErrTurnDirection struct {
+	Err error
+}
+
+func (e ErrTurnDirection) Error() string {
+	return "cannot turn direction"
+}
+
+// TurnLeft returns the direction to the left of the direction
+func (dir Direction) TurnLeft() (Direction, error) {
+	if !ValidDirection(dir) {
+		return 0, &ErrTurnDirection{
+			Err: &ErrInvalidDirection{
+				Direction: dir,
+			},
+		}
+	}
+
+	return (dir + directionCount - 1) % directionCount, nil
+}
+
+// TurnRight returns the direction to the right of the direction
+func (dir Direction) TurnRight() (Direction, error) {
+	if !ValidDirection(dir) {
+		return 0, &ErrTurnDirection{
+			Err: &ErrInvalidDirection{
+				Direction: dir,
+			},
+		}
+	}
+
+	return (dir + 1) % directionCount, nil
+}
@@ -78,6 +78,35 @@ func Test_Direction_Reverse_ReversesDirection(t *testing.T) {
 	}
 }
 
+func Test_Direction_Turn_TurnsDirection(t *testing.T) {
+	tests := []struct {
+		directionInput Direction
+		expectedLeft   Direction
+		expectedRight  Direction
+		expectError    bool
+	}{
+		{DirectionNorth, DirectionWest, DirectionEast, false},
+		{DirectionEast, DirectionNorth, DirectionSouth, false},
+		{DirectionSouth, DirectionEast, DirectionWest, false},
+		{DirectionWest, DirectionSouth, DirectionNorth, false},
+		{22, 0, 0, true},
+	}
+
+	for i, test := range tests {
+		left, errLeft := test.directionInput.TurnLeft()
+		right, errRight := test.directionInput.TurnRight()
+		if test.expectError {
+			require.NotNil(t, errLeft, fmt.Sprintf("number %d not error", i))
+			require.NotNil(t, errRight, fmt.Sprintf("number %d not error", i))
+		} else {
+			require.Nil(t, errLeft, fmt.Sprintf("number %d error", i))
+			require.Nil(t, errRight, fmt.Sprintf("number %d error", i))
+		}
+		require.Equal(t, test.expectedLeft, left, fmt.Sprintf("number %d", i))
+		require.Equal(t, test.expectedRight, right, fmt.Sprintf("number %d", i))
+	}
+}
+
 func Test_Direction_MarshalJSON(t *testing.T) {
 	tests := []struct {
 		direction    Direction
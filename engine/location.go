@@ -16,6 +16,18 @@ func (l Location) Contains(dot Dot) bool {
 	return false
 }
 
+// IndexOf returns the index of the passed dot in the location or -1 if the
+// location does not contain the dot
+func (l Location) IndexOf(dot Dot) int {
+	for i := range l {
+		if l[i].Equals(dot) {
+			return i
+		}
+	}
+
+	return -1
+}
+
 // Delete deletes dot from object
 func (l Location) Delete(dot Dot) Location {
 	newLocation := l.Copy()
@@ -26,6 +26,15 @@ func Test_Location_ContainsDot(t *testing.T) {
 	}
 }
 
+func Test_Location_IndexOf(t *testing.T) {
+	location := Location{Dot{X: 5, Y: 2}, Dot{X: 5, Y: 1}, Dot{X: 5, Y: 0}}
+
+	require.Equal(t, 0, location.IndexOf(Dot{X: 5, Y: 2}))
+	require.Equal(t, 2, location.IndexOf(Dot{X: 5, Y: 0}))
+	require.Equal(t, -1, location.IndexOf(Dot{X: 5, Y: 3}))
+	require.Equal(t, -1, Location{}.IndexOf(Dot{X: 0, Y: 0}))
+}
+
 func Test_Location_Copy(t *testing.T) {
 	locations := []Location{
 		{Dot{0, 0}, Dot{0, 1}, Dot{0, 2}},
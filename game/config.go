@@ -1,5 +1,19 @@
 package game
 
+import "github.com/ivan1993spb/snake-server/objects/snake"
+
 type Config struct {
 	EnableWalls bool
+
+	// Extra kinds of food which are spawned on the map
+	EnablePoison       bool
+	EnableGoldenApples bool
+	EnableFruits       bool
+
+	// BattleRoyale shrinks the arena with rings of walls until one snake
+	// remains
+	BattleRoyale bool
+
+	// Snake contains the rules for snakes of the game
+	Snake snake.Config
 }
@@ -11,6 +11,9 @@ const (
 	EventTypeObjectDelete
 	EventTypeObjectUpdate
 	EventTypeObjectChecked
+	EventTypeObjectBite
+	EventTypeArena
+	EventTypeObjectEat
 )
 
 var eventsLabels = map[EventType]string{
@@ -19,6 +22,9 @@ var eventsLabels = map[EventType]string{
 	EventTypeObjectDelete:  "delete",
 	EventTypeObjectUpdate:  "update",
 	EventTypeObjectChecked: "checked",
+	EventTypeObjectBite:    "bite",
+	EventTypeArena:         "arena",
+	EventTypeObjectEat:     "eat",
 }
 
 func (event EventType) String() string {
@@ -34,6 +40,9 @@ var eventTypesJSONs = map[EventType][]byte{
 	EventTypeObjectDelete:  []byte(`"delete"`),
 	EventTypeObjectUpdate:  []byte(`"update"`),
 	EventTypeObjectChecked: []byte(`"checked"`),
+	EventTypeObjectBite:    []byte(`"bite"`),
+	EventTypeArena:         []byte(`"arena"`),
+	EventTypeObjectEat:     []byte(`"eat"`),
 }
 
 func (event EventType) MarshalJSON() ([]byte, error) {
@@ -55,6 +64,9 @@ var eventTypesCasting = map[world.EventType]EventType{
 	world.EventTypeObjectDelete:  EventTypeObjectDelete,
 	world.EventTypeObjectUpdate:  EventTypeObjectUpdate,
 	world.EventTypeObjectChecked: EventTypeObjectChecked,
+	world.EventTypeObjectBite:    EventTypeObjectBite,
+	world.EventTypeArena:         EventTypeArena,
+	world.EventTypeObjectEat:     EventTypeObjectEat,
 }
 
 func worldEventTypeToGameEventType(worldEventType world.EventType) EventType {
@@ -6,8 +6,12 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/ivan1993spb/snake-server/observers/apple"
+	"github.com/ivan1993spb/snake-server/observers/arena"
+	"github.com/ivan1993spb/snake-server/observers/fruit"
+	"github.com/ivan1993spb/snake-server/observers/goldenapple"
 	"github.com/ivan1993spb/snake-server/observers/logger"
 	"github.com/ivan1993spb/snake-server/observers/mouse"
+	"github.com/ivan1993spb/snake-server/observers/poison"
 	"github.com/ivan1993spb/snake-server/observers/snake"
 	"github.com/ivan1993spb/snake-server/observers/wall"
 	"github.com/ivan1993spb/snake-server/observers/watermelon"
@@ -41,6 +45,11 @@ func NewGame(logger logrus.FieldLogger, width, height uint8, config Config) (*Ga
 	}, nil
 }
 
+// SetLogger replaces the logger of the game. It must be called before Start
+func (g *Game) SetLogger(logger logrus.FieldLogger) {
+	g.logger = logger
+}
+
 func (g *Game) Start(stop <-chan struct{}) {
 	g.world.Start(stop)
 
@@ -49,9 +58,26 @@ func (g *Game) Start(stop <-chan struct{}) {
 		wall_observer.NewWallObserver(g.world, g.logger).Observe(stop)
 	}
 	apple_observer.NewAppleObserver(g.world, g.logger).Observe(stop)
-	snake_observer.NewSnakeObserver(g.world, g.logger).Observe(stop)
+	snake_observer.NewSnakeObserver(g.world, g.logger, g.config.Snake.Corpse).Observe(stop)
 	watermelon_observer.NewWatermelonObserver(g.world, g.logger).Observe(stop)
 	mouse_observer.NewMouseObserver(g.world, g.logger).Observe(stop)
+	if g.config.EnablePoison {
+		poison_observer.NewPoisonObserver(g.world, g.logger).Observe(stop)
+	}
+	if g.config.EnableGoldenApples {
+		goldenapple_observer.NewGoldenAppleObserver(g.world, g.logger).Observe(stop)
+	}
+	if g.config.EnableFruits {
+		fruit_observer.NewFruitObserver(g.world, g.logger).Observe(stop)
+	}
+	if g.config.BattleRoyale {
+		arena_observer.NewArenaObserver(g.world, g.logger).Observe(stop)
+	}
+}
+
+// Config returns the rules of the game
+func (g *Game) Config() Config {
+	return g.config
 }
 
 func (g *Game) World() world.Interface {
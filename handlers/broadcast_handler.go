@@ -10,6 +10,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/ivan1993spb/snake-server/connections"
+	"github.com/ivan1993spb/snake-server/middlewares"
 )
 
 const URLRouteBroadcast = "/games/{id}/broadcast"
@@ -50,9 +51,11 @@ func NewBroadcastHandler(logger logrus.FieldLogger, groupManager *connections.Co
 }
 
 func (h *broadcastHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := middlewares.RequestLogger(h.logger, r)
+
 	r.Body = http.MaxBytesReader(w, r.Body, broadcastMaxBodySize)
 	if err := r.ParseForm(); err != nil {
-		h.logger.Error(ErrBroadcastHandler(err.Error()))
+		logger.Error(ErrBroadcastHandler(err.Error()))
 		h.writeResponseJSON(w, http.StatusBadRequest, &responseBroadcastHandlerError{
 			Code: http.StatusBadRequest,
 			Text: "bad request",
@@ -64,7 +67,7 @@ func (h *broadcastHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		h.logger.Error(ErrBroadcastHandler(err.Error()))
+		logger.Error(ErrBroadcastHandler(err.Error()))
 		h.writeResponseJSON(w, http.StatusBadRequest, &responseBroadcastHandlerError{
 			Code: http.StatusBadRequest,
 			Text: "invalid game id",
@@ -72,11 +75,11 @@ func (h *broadcastHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.logger.Infoln("group id to broadcast:", id)
+	logger.Infoln("group id to broadcast:", id)
 
 	group, err := h.groupManager.Get(id)
 	if err != nil {
-		h.logger.Errorln("cannot get group:", err.Error())
+		logger.Errorln("cannot get group:", err.Error())
 
 		switch err {
 		case connections.ErrNotFoundGroup:
@@ -5,10 +5,13 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/ivan1993spb/snake-server/connections"
+	"github.com/ivan1993spb/snake-server/logfields"
+	"github.com/ivan1993spb/snake-server/middlewares"
 )
 
 const URLRouteCreateGame = "/games"
@@ -20,19 +23,46 @@ const (
 	postFieldMapWidth        = "width"
 	postFieldMapHeight       = "height"
 	postFieldEnableWalls     = "enable_walls"
+	postFieldBatchWindow     = "batch_window"
+	postFieldSpeedCurve      = "speed_curve"
+	postFieldEnableBoost     = "enable_boost"
+	postFieldHeadToHead      = "head_to_head"
+	postFieldHeadToBody      = "head_to_body"
+	postFieldSelfBite        = "self_bite"
+	postFieldCorpseLifetime  = "corpse_lifetime"
+	postFieldCorpseDecay     = "corpse_decay"
+	postFieldEnablePoison    = "enable_poison"
+	postFieldEnableGolden    = "enable_golden_apples"
+	postFieldEnableFruits    = "enable_fruits"
+	postFieldBattleRoyale    = "battle_royale"
 )
 
+const defaultParamValueEnableWalls = true
+
+const defaultParamValueEnableBoost = false
+
+const defaultParamValueCorpseDecay = false
+
 const (
-	minMapWidth  = 8
-	minMapHeight = 8
+	defaultParamValueEnablePoison = false
+	defaultParamValueEnableGolden = false
+	defaultParamValueEnableFruits = false
 )
 
-const defaultParamValueEnableWalls = true
+const defaultParamValueBattleRoyale = false
 
-var (
-	strErrLessThanMinMapWidth  = fmt.Sprintf("map width less than %d", minMapWidth)
-	strErrLessThanMinMapHeight = fmt.Sprintf("map height less than %d", minMapHeight)
-)
+// Corpse lifetime is passed in seconds
+const corpseLifetimeUnit = time.Second
+
+// Batch window is passed in milliseconds
+const batchWindowUnit = time.Millisecond
+
+// Errors of the map size keep the texts which clients of the API already
+// know. Widths and heights greater than 255 are rejected on parsing
+var errGroupParamsTexts = map[connections.ErrGroupParams]string{
+	connections.ErrInvalidMapWidth:  fmt.Sprintf("map width less than %d", connections.MinMapWidth),
+	connections.ErrInvalidMapHeight: fmt.Sprintf("map height less than %d", connections.MinMapHeight),
+}
 
 type responseCreateGameHandler struct {
 	ID     int    `json:"id"`
@@ -67,75 +97,162 @@ func NewCreateGameHandler(logger logrus.FieldLogger, groupManager *connections.C
 }
 
 func (h *createGameHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := middlewares.RequestLogger(h.logger, r)
+
 	connectionLimit, err := strconv.Atoi(r.PostFormValue(postFieldConnectionLimit))
 	if err != nil {
-		h.logger.Error(ErrCreateGameHandler(err.Error()))
+		logger.Error(ErrCreateGameHandler(err.Error()))
 		h.writeResponseJSON(w, http.StatusBadRequest, &responseCreateGameHandlerError{
 			Code: http.StatusBadRequest,
 			Text: "invalid limit",
 		})
 		return
 	}
-	if connectionLimit <= 0 {
-		h.logger.Warnln(ErrCreateGameHandler("invalid connection limit"), connectionLimit)
-		h.writeResponseJSON(w, http.StatusBadRequest, &responseCreateGameHandlerError{
-			Code: http.StatusBadRequest,
-			Text: "invalid limit",
-		})
-		return
-	}
-
 	mapWidth, err := strconv.ParseUint(r.PostFormValue(postFieldMapWidth), 10, 8)
 	if err != nil {
-		h.logger.Error(ErrCreateGameHandler(err.Error()))
+		logger.Error(ErrCreateGameHandler(err.Error()))
 		h.writeResponseJSON(w, http.StatusBadRequest, &responseCreateGameHandlerError{
 			Code: http.StatusBadRequest,
 			Text: "invalid width",
 		})
 		return
 	}
-	if mapWidth < minMapWidth {
-		h.logger.Warnln(ErrCreateGameHandler("invalid map width less than min"), mapWidth)
-		h.writeResponseJSON(w, http.StatusBadRequest, &responseCreateGameHandlerError{
-			Code: http.StatusBadRequest,
-			Text: strErrLessThanMinMapWidth,
-		})
-		return
-	}
 
 	mapHeight, err := strconv.ParseUint(r.PostFormValue(postFieldMapHeight), 10, 8)
 	if err != nil {
-		h.logger.Error(ErrCreateGameHandler(err.Error()))
+		logger.Error(ErrCreateGameHandler(err.Error()))
 		h.writeResponseJSON(w, http.StatusBadRequest, &responseCreateGameHandlerError{
 			Code: http.StatusBadRequest,
 			Text: "invalid height",
 		})
 		return
 	}
-	if mapHeight < minMapHeight {
-		h.logger.Warnln(ErrCreateGameHandler("invalid map height less than min"), mapHeight)
-		h.writeResponseJSON(w, http.StatusBadRequest, &responseCreateGameHandlerError{
-			Code: http.StatusBadRequest,
-			Text: strErrLessThanMinMapHeight,
-		})
-		return
-	}
 
 	enableWalls, err := strconv.ParseBool(r.PostFormValue(postFieldEnableWalls))
 	if err != nil {
 		enableWalls = defaultParamValueEnableWalls
 	}
 
-	h.logger.WithFields(logrus.Fields{
-		"width":            mapWidth,
-		"height":           mapHeight,
-		"connection_limit": connectionLimit,
-		"enable_walls":     enableWalls,
+	enablePoison, err := strconv.ParseBool(r.PostFormValue(postFieldEnablePoison))
+	if err != nil {
+		enablePoison = defaultParamValueEnablePoison
+	}
+
+	enableGolden, err := strconv.ParseBool(r.PostFormValue(postFieldEnableGolden))
+	if err != nil {
+		enableGolden = defaultParamValueEnableGolden
+	}
+
+	enableFruits, err := strconv.ParseBool(r.PostFormValue(postFieldEnableFruits))
+	if err != nil {
+		enableFruits = defaultParamValueEnableFruits
+	}
+
+	battleRoyale, err := strconv.ParseBool(r.PostFormValue(postFieldBattleRoyale))
+	if err != nil {
+		battleRoyale = defaultParamValueBattleRoyale
+	}
+
+	var batchWindow uint64
+	if value := r.PostFormValue(postFieldBatchWindow); value != "" {
+		batchWindow, err = strconv.ParseUint(value, 10, 16)
+		if err != nil {
+			logger.Warnln(ErrCreateGameHandler("invalid batch window"), value)
+			h.writeResponseJSON(w, http.StatusBadRequest, &responseCreateGameHandlerError{
+				Code: http.StatusBadRequest,
+				Text: connections.ErrInvalidBatchWindow.Error(),
+			})
+			return
+		}
+	}
+
+	enableBoost, err := strconv.ParseBool(r.PostFormValue(postFieldEnableBoost))
+	if err != nil {
+		enableBoost = defaultParamValueEnableBoost
+	}
+
+	var corpseLifetime uint64
+	if value := r.PostFormValue(postFieldCorpseLifetime); value != "" {
+		// The zero lifetime selects the default one, it cannot be passed
+		corpseLifetime, err = strconv.ParseUint(value, 10, 8)
+		if err != nil || corpseLifetime == 0 {
+			logger.Warnln(ErrCreateGameHandler("invalid corpse lifetime"), value)
+			h.writeResponseJSON(w, http.StatusBadRequest, &responseCreateGameHandlerError{
+				Code: http.StatusBadRequest,
+				Text: connections.ErrInvalidCorpseLifetime.Error(),
+			})
+			return
+		}
+	}
+
+	corpseDecay, err := strconv.ParseBool(r.PostFormValue(postFieldCorpseDecay))
+	if err != nil {
+		corpseDecay = defaultParamValueCorpseDecay
+	}
+
+	params := connections.GroupParams{
+		Limit:              connectionLimit,
+		Width:              int(mapWidth),
+		Height:             int(mapHeight),
+		EnableWalls:        enableWalls,
+		EnablePoison:       enablePoison,
+		EnableGoldenApples: enableGolden,
+		EnableFruits:       enableFruits,
+		BattleRoyale:       battleRoyale,
+		BatchWindow:        time.Duration(batchWindow) * batchWindowUnit,
+		SpeedCurve:         r.PostFormValue(postFieldSpeedCurve),
+		EnableBoost:        enableBoost,
+		HeadToHead:         r.PostFormValue(postFieldHeadToHead),
+		HeadToBody:         r.PostFormValue(postFieldHeadToBody),
+		SelfBite:           r.PostFormValue(postFieldSelfBite),
+		CorpseLifetime:     time.Duration(corpseLifetime) * corpseLifetimeUnit,
+		CorpseDecay:        corpseDecay,
+	}
+
+	logger.WithFields(logrus.Fields{
+		"width":            params.Width,
+		"height":           params.Height,
+		"connection_limit": params.Limit,
+		"enable_walls":     params.EnableWalls,
+		"enable_poison":    params.EnablePoison,
+		"enable_golden":    params.EnableGoldenApples,
+		"enable_fruits":    params.EnableFruits,
+		"battle_royale":    params.BattleRoyale,
+		"batch_window":     params.BatchWindow,
+		"speed_curve":      params.SpeedCurve,
+		"enable_boost":     params.EnableBoost,
+		"head_to_head":     params.HeadToHead,
+		"head_to_body":     params.HeadToBody,
+		"self_bite":        params.SelfBite,
+		"corpse_lifetime":  params.CorpseLifetime,
+		"corpse_decay":     params.CorpseDecay,
 	}).Debug("create game group")
 
-	group, err := connections.NewConnectionGroup(h.logger, connectionLimit, uint8(mapWidth), uint8(mapHeight), enableWalls)
+	// The group outlives the request and its span, so the group logs only the
+	// identifier of the request which has created it
+	groupLogger := h.logger
+	if id, ok := middlewares.RequestID(r.Context()); ok {
+		groupLogger = groupLogger.WithField(logfields.RequestID, id)
+	}
+
+	group, err := connections.NewConnectionGroupParams(groupLogger, params)
 	if err != nil {
-		h.logger.Error(ErrCreateGameHandler(err.Error()))
+		if errParams, ok := err.(connections.ErrGroupParams); ok {
+			logger.Warn(ErrCreateGameHandler(err.Error()))
+
+			text, ok := errGroupParamsTexts[errParams]
+			if !ok {
+				text = errParams.Error()
+			}
+
+			h.writeResponseJSON(w, http.StatusBadRequest, &responseCreateGameHandlerError{
+				Code: http.StatusBadRequest,
+				Text: text,
+			})
+			return
+		}
+
+		logger.Error(ErrCreateGameHandler(err.Error()))
 		h.writeResponseJSON(w, http.StatusInternalServerError, &responseCreateGameHandlerError{
 			Code: http.StatusInternalServerError,
 			Text: "cannot create game",
@@ -145,7 +262,7 @@ func (h *createGameHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	id, err := h.groupManager.Add(group)
 	if err != nil {
-		h.logger.Error(ErrCreateGameHandler(err.Error()))
+		logger.Error(ErrCreateGameHandler(err.Error()))
 
 		switch err {
 		case connections.ErrGroupLimitReached:
@@ -158,6 +275,11 @@ func (h *createGameHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 				Code: http.StatusServiceUnavailable,
 				Text: "connections limit reached",
 			})
+		case connections.ErrServerDraining:
+			h.writeResponseJSON(w, http.StatusServiceUnavailable, &responseCreateGameHandlerError{
+				Code: http.StatusServiceUnavailable,
+				Text: "server is shutting down",
+			})
 		default:
 			h.writeResponseJSON(w, http.StatusInternalServerError, &responseCreateGameHandlerError{
 				Code: http.StatusInternalServerError,
@@ -167,17 +289,17 @@ func (h *createGameHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.logger.Info("start group")
+	logger.Info("start group")
 	group.Start()
 
-	h.logger.WithField("group_id", id).Infoln("created group")
+	logger.WithField(logfields.GameID, id).Infoln("created group")
 
 	h.writeResponseJSON(w, http.StatusCreated, &responseCreateGameHandler{
 		ID:     id,
 		Limit:  group.GetLimit(),
 		Count:  0,
-		Width:  uint8(mapWidth),
-		Height: uint8(mapHeight),
+		Width:  group.GetWorldWidth(),
+		Height: group.GetWorldHeight(),
 		Rate:   0,
 	})
 }
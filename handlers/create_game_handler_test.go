@@ -1,11 +1,13 @@
 package handlers
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus/hooks/test"
@@ -55,3 +57,225 @@ func Test_CreateGameHandler_ServeHTTP_CreatesGroup(t *testing.T) {
 
 	hook.Reset()
 }
+
+func Test_CreateGameHandler_ServeHTTP_BatchWindow(t *testing.T) {
+	const groupsLimit = 5
+	const connsLimit = 10
+
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	groupManager, err := connections.NewConnectionGroupManager(logger, groupsLimit, connsLimit)
+	require.Nil(t, err)
+
+	handler := NewCreateGameHandler(logger, groupManager)
+
+	r := mux.NewRouter()
+	r.Path(URLRouteCreateGame).Methods(MethodCreateGame).Handler(handler)
+
+	for _, value := range []string{"-1", "1001", "fast"} {
+		data := &url.Values{}
+		data.Add(postFieldConnectionLimit, "2")
+		data.Add(postFieldMapWidth, "20")
+		data.Add(postFieldMapHeight, "20")
+		data.Add(postFieldBatchWindow, value)
+
+		request := httptest.NewRequest(MethodCreateGame, URLRouteCreateGame, strings.NewReader(data.Encode()))
+		request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusBadRequest, recorder.Code, value)
+	}
+
+	require.Empty(t, groupManager.Groups())
+
+	data := &url.Values{}
+	data.Add(postFieldConnectionLimit, "2")
+	data.Add(postFieldMapWidth, "20")
+	data.Add(postFieldMapHeight, "20")
+	data.Add(postFieldBatchWindow, "25")
+
+	request := httptest.NewRequest(MethodCreateGame, URLRouteCreateGame, strings.NewReader(data.Encode()))
+	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusCreated, recorder.Code)
+
+	group, err := groupManager.Get(1)
+	require.Nil(t, err)
+	require.Equal(t, time.Millisecond*25, group.GetBatchWindow())
+	require.Nil(t, groupManager.Delete(group))
+}
+
+func Test_CreateGameHandler_ServeHTTP_SpeedCurve(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	groupManager, err := connections.NewConnectionGroupManager(logger, 5, 10)
+	require.Nil(t, err)
+
+	handler := NewCreateGameHandler(logger, groupManager)
+
+	r := mux.NewRouter()
+	r.Path(URLRouteCreateGame).Methods(MethodCreateGame).Handler(handler)
+
+	create := func(speedCurve string) int {
+		data := &url.Values{}
+		data.Add(postFieldConnectionLimit, "2")
+		data.Add(postFieldMapWidth, "20")
+		data.Add(postFieldMapHeight, "20")
+		data.Add(postFieldSpeedCurve, speedCurve)
+
+		request := httptest.NewRequest(MethodCreateGame, URLRouteCreateGame, strings.NewReader(data.Encode()))
+		request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	require.Equal(t, http.StatusBadRequest, create("warp"))
+	require.Empty(t, groupManager.Groups())
+
+	require.Equal(t, http.StatusCreated, create("slowing"))
+	group, err := groupManager.Get(1)
+	require.Nil(t, err)
+	require.Nil(t, groupManager.Delete(group))
+}
+
+func Test_CreateGameHandler_ServeHTTP_CollisionRules(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	groupManager, err := connections.NewConnectionGroupManager(logger, 5, 10)
+	require.Nil(t, err)
+
+	handler := NewCreateGameHandler(logger, groupManager)
+
+	r := mux.NewRouter()
+	r.Path(URLRouteCreateGame).Methods(MethodCreateGame).Handler(handler)
+
+	create := func(headToHead, headToBody, selfBite string) int {
+		data := &url.Values{}
+		data.Add(postFieldConnectionLimit, "2")
+		data.Add(postFieldMapWidth, "20")
+		data.Add(postFieldMapHeight, "20")
+		data.Add(postFieldHeadToHead, headToHead)
+		data.Add(postFieldHeadToBody, headToBody)
+		data.Add(postFieldSelfBite, selfBite)
+
+		request := httptest.NewRequest(MethodCreateGame, URLRouteCreateGame, strings.NewReader(data.Encode()))
+		request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	require.Equal(t, http.StatusBadRequest, create("defender_cut", "", ""))
+	require.Equal(t, http.StatusBadRequest, create("", "both_die", ""))
+	require.Equal(t, http.StatusBadRequest, create("", "", "sever"))
+	require.Empty(t, groupManager.Groups())
+
+	require.Equal(t, http.StatusCreated, create("longer_wins", "defender_cut", "trim"))
+	group, err := groupManager.Get(1)
+	require.Nil(t, err)
+	require.Nil(t, groupManager.Delete(group))
+}
+
+func Test_CreateGameHandler_ServeHTTP_CorpseDecay(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	groupManager, err := connections.NewConnectionGroupManager(logger, 5, 10)
+	require.Nil(t, err)
+
+	handler := NewCreateGameHandler(logger, groupManager)
+
+	r := mux.NewRouter()
+	r.Path(URLRouteCreateGame).Methods(MethodCreateGame).Handler(handler)
+
+	create := func(corpseLifetime string) int {
+		data := &url.Values{}
+		data.Add(postFieldConnectionLimit, "2")
+		data.Add(postFieldMapWidth, "20")
+		data.Add(postFieldMapHeight, "20")
+		data.Add(postFieldCorpseLifetime, corpseLifetime)
+		data.Add(postFieldCorpseDecay, "true")
+
+		request := httptest.NewRequest(MethodCreateGame, URLRouteCreateGame, strings.NewReader(data.Encode()))
+		request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	require.Equal(t, http.StatusBadRequest, create("0"))
+	require.Equal(t, http.StatusBadRequest, create("61"))
+	require.Equal(t, http.StatusBadRequest, create("ten"))
+	require.Empty(t, groupManager.Groups())
+
+	require.Equal(t, http.StatusCreated, create("30"))
+	group, err := groupManager.Get(1)
+	require.Nil(t, err)
+	require.Nil(t, groupManager.Delete(group))
+}
+
+func Test_CreateGameHandler_ServeHTTP_MapSizeErrors(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	groupManager, err := connections.NewConnectionGroupManager(logger, 5, 10)
+	require.Nil(t, err)
+
+	handler := NewCreateGameHandler(logger, groupManager)
+
+	r := mux.NewRouter()
+	r.Path(URLRouteCreateGame).Methods(MethodCreateGame).Handler(handler)
+
+	tests := []struct {
+		width  string
+		height string
+		text   string
+	}{
+		// Test case 1
+		{
+			width:  "4",
+			height: "20",
+			text:   "map width less than 8",
+		},
+		// Test case 2
+		{
+			width:  "20",
+			height: "4",
+			text:   "map height less than 8",
+		},
+		// Test case 3
+		{
+			width:  "300",
+			height: "20",
+			text:   "invalid width",
+		},
+	}
+
+	for i, test := range tests {
+		data := &url.Values{}
+		data.Add(postFieldConnectionLimit, "2")
+		data.Add(postFieldMapWidth, test.width)
+		data.Add(postFieldMapHeight, test.height)
+
+		request := httptest.NewRequest(MethodCreateGame, URLRouteCreateGame, strings.NewReader(data.Encode()))
+		request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusBadRequest, recorder.Code, "test case %d", i+1)
+
+		response := &responseCreateGameHandlerError{}
+		require.Nil(t, json.NewDecoder(recorder.Body).Decode(response), "test case %d", i+1)
+		require.Equal(t, test.text, response.Text, "test case %d", i+1)
+	}
+}
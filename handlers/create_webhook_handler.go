@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ivan1993spb/snake-server/connections"
+	"github.com/ivan1993spb/snake-server/logfields"
+	"github.com/ivan1993spb/snake-server/middlewares"
+	"github.com/ivan1993spb/snake-server/webhooks"
+)
+
+const URLRouteCreateWebhook = "/games/{id}/webhooks"
+
+const MethodCreateWebhook = http.MethodPost
+
+const postFieldWebhookURL = "url"
+
+type responseCreateWebhookHandler struct {
+	ID     int      `json:"id"`
+	URLs   []string `json:"urls"`
+	Secret string   `json:"secret"`
+}
+
+type responseCreateWebhookHandlerError struct {
+	Code int    `json:"code"`
+	Text string `json:"text"`
+	ID   int    `json:"id"`
+}
+
+type createWebhookHandler struct {
+	logger       logrus.FieldLogger
+	groupManager *connections.ConnectionGroupManager
+	dispatcher   *webhooks.Dispatcher
+}
+
+type ErrCreateWebhookHandler string
+
+func (e ErrCreateWebhookHandler) Error() string {
+	return "create webhook handler error: " + string(e)
+}
+
+func NewCreateWebhookHandler(logger logrus.FieldLogger, groupManager *connections.ConnectionGroupManager,
+	dispatcher *webhooks.Dispatcher) http.Handler {
+	return &createWebhookHandler{
+		logger:       logger,
+		groupManager: groupManager,
+		dispatcher:   dispatcher,
+	}
+}
+
+func (h *createWebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := middlewares.RequestLogger(h.logger, r)
+
+	vars := mux.Vars(r)
+
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		logger.Error(ErrCreateWebhookHandler(err.Error()))
+		h.writeResponseJSON(w, http.StatusBadRequest, &responseCreateWebhookHandlerError{
+			Code: http.StatusBadRequest,
+			Text: "invalid game id",
+			ID:   id,
+		})
+		return
+	}
+
+	if _, err := h.groupManager.Get(id); err != nil {
+		logger.Error(ErrCreateWebhookHandler(err.Error()))
+
+		switch err {
+		case connections.ErrNotFoundGroup:
+			h.writeResponseJSON(w, http.StatusNotFound, &responseCreateWebhookHandlerError{
+				Code: http.StatusNotFound,
+				Text: "game not found",
+				ID:   id,
+			})
+		default:
+			h.writeResponseJSON(w, http.StatusInternalServerError, &responseCreateWebhookHandlerError{
+				Code: http.StatusInternalServerError,
+				Text: "unknown error",
+				ID:   id,
+			})
+		}
+		return
+	}
+
+	secret, err := h.dispatcher.Register(id, r.PostFormValue(postFieldWebhookURL))
+	if err != nil {
+		logger.Warn(ErrCreateWebhookHandler(err.Error()))
+
+		switch err {
+		case webhooks.ErrInvalidURL:
+			h.writeResponseJSON(w, http.StatusBadRequest, &responseCreateWebhookHandlerError{
+				Code: http.StatusBadRequest,
+				Text: "invalid url",
+				ID:   id,
+			})
+		case webhooks.ErrHostNotAllowed:
+			h.writeResponseJSON(w, http.StatusForbidden, &responseCreateWebhookHandlerError{
+				Code: http.StatusForbidden,
+				Text: "host is not allowed",
+				ID:   id,
+			})
+		case webhooks.ErrForbiddenAddress:
+			h.writeResponseJSON(w, http.StatusForbidden, &responseCreateWebhookHandlerError{
+				Code: http.StatusForbidden,
+				Text: "forbidden address",
+				ID:   id,
+			})
+		case webhooks.ErrURLRegistered:
+			h.writeResponseJSON(w, http.StatusConflict, &responseCreateWebhookHandlerError{
+				Code: http.StatusConflict,
+				Text: "url is already registered",
+				ID:   id,
+			})
+		case webhooks.ErrGameURLsLimit:
+			h.writeResponseJSON(w, http.StatusServiceUnavailable, &responseCreateWebhookHandlerError{
+				Code: http.StatusServiceUnavailable,
+				Text: "webhooks limit reached",
+				ID:   id,
+			})
+		default:
+			h.writeResponseJSON(w, http.StatusInternalServerError, &responseCreateWebhookHandlerError{
+				Code: http.StatusInternalServerError,
+				Text: "unknown error",
+				ID:   id,
+			})
+		}
+		return
+	}
+
+	logger.WithField(logfields.GameID, id).Info("webhook registered")
+
+	h.writeResponseJSON(w, http.StatusCreated, &responseCreateWebhookHandler{
+		ID:     id,
+		URLs:   h.dispatcher.GameURLs(id),
+		Secret: secret,
+	})
+}
+
+func (h *createWebhookHandler) writeResponseJSON(w http.ResponseWriter, statusCode int, response interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error(ErrCreateWebhookHandler(err.Error()))
+	}
+}
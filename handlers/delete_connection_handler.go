@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ivan1993spb/snake-server/connections"
+	"github.com/ivan1993spb/snake-server/logfields"
+	"github.com/ivan1993spb/snake-server/middlewares"
+)
+
+const URLRouteDeleteConnection = "/games/{id}/connections/{cid}"
+
+const MethodDeleteConnection = http.MethodDelete
+
+const (
+	fieldKickReason   = "reason"
+	defaultKickReason = "you have been kicked from the game"
+	kickReasonMaxLen  = 128
+)
+
+type responseDeleteConnectionHandler struct {
+	ID           int `json:"id"`
+	ConnectionID int `json:"connection_id"`
+}
+
+type responseDeleteConnectionHandlerError struct {
+	Code         int    `json:"code"`
+	Text         string `json:"text"`
+	ID           int    `json:"id"`
+	ConnectionID int    `json:"connection_id"`
+}
+
+type deleteConnectionHandler struct {
+	logger       logrus.FieldLogger
+	groupManager *connections.ConnectionGroupManager
+}
+
+type ErrDeleteConnectionHandler string
+
+func (e ErrDeleteConnectionHandler) Error() string {
+	return "delete connection handler error: " + string(e)
+}
+
+func NewDeleteConnectionHandler(logger logrus.FieldLogger, groupManager *connections.ConnectionGroupManager) http.Handler {
+	return &deleteConnectionHandler{
+		logger:       logger,
+		groupManager: groupManager,
+	}
+}
+
+func (h *deleteConnectionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := middlewares.RequestLogger(h.logger, r)
+
+	vars := mux.Vars(r)
+
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		logger.Error(ErrDeleteConnectionHandler(err.Error()))
+		h.writeResponseJSON(w, http.StatusBadRequest, &responseDeleteConnectionHandlerError{
+			Code: http.StatusBadRequest,
+			Text: "invalid game id",
+			ID:   id,
+		})
+		return
+	}
+
+	cid, err := strconv.Atoi(vars["cid"])
+	if err != nil {
+		logger.Error(ErrDeleteConnectionHandler(err.Error()))
+		h.writeResponseJSON(w, http.StatusBadRequest, &responseDeleteConnectionHandlerError{
+			Code:         http.StatusBadRequest,
+			Text:         "invalid connection id",
+			ID:           id,
+			ConnectionID: cid,
+		})
+		return
+	}
+
+	reason := r.FormValue(fieldKickReason)
+	if len(reason) > kickReasonMaxLen {
+		logger.Warn(ErrDeleteConnectionHandler("reason is too long"))
+		h.writeResponseJSON(w, http.StatusBadRequest, &responseDeleteConnectionHandlerError{
+			Code:         http.StatusBadRequest,
+			Text:         "reason is too long",
+			ID:           id,
+			ConnectionID: cid,
+		})
+		return
+	}
+	if len(reason) == 0 {
+		reason = defaultKickReason
+	}
+
+	group, err := h.groupManager.Get(id)
+	if err != nil {
+		logger.Error(ErrDeleteConnectionHandler(err.Error()))
+
+		switch err {
+		case connections.ErrNotFoundGroup:
+			h.writeResponseJSON(w, http.StatusNotFound, &responseDeleteConnectionHandlerError{
+				Code:         http.StatusNotFound,
+				Text:         "game not found",
+				ID:           id,
+				ConnectionID: cid,
+			})
+		default:
+			h.writeResponseJSON(w, http.StatusInternalServerError, &responseDeleteConnectionHandlerError{
+				Code:         http.StatusInternalServerError,
+				Text:         "unknown error",
+				ID:           id,
+				ConnectionID: cid,
+			})
+		}
+		return
+	}
+
+	if err := group.KickConnection(cid, reason); err != nil {
+		logger.Error(ErrDeleteConnectionHandler(err.Error()))
+
+		switch err {
+		case connections.ErrNotFoundConnection:
+			h.writeResponseJSON(w, http.StatusNotFound, &responseDeleteConnectionHandlerError{
+				Code:         http.StatusNotFound,
+				Text:         "connection not found",
+				ID:           id,
+				ConnectionID: cid,
+			})
+		default:
+			h.writeResponseJSON(w, http.StatusInternalServerError, &responseDeleteConnectionHandlerError{
+				Code:         http.StatusInternalServerError,
+				Text:         "unknown error",
+				ID:           id,
+				ConnectionID: cid,
+			})
+		}
+		return
+	}
+
+	logger.WithFields(logrus.Fields{
+		logfields.GameID:       id,
+		logfields.ConnectionID: cid,
+		"reason":               reason,
+	}).Info("connection kicked")
+
+	h.writeResponseJSON(w, http.StatusOK, &responseDeleteConnectionHandler{
+		ID:           id,
+		ConnectionID: cid,
+	})
+}
+
+func (h *deleteConnectionHandler) writeResponseJSON(w http.ResponseWriter, statusCode int, response interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error(ErrDeleteConnectionHandler(err.Error()))
+	}
+}
@@ -9,12 +9,19 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/ivan1993spb/snake-server/connections"
+	"github.com/ivan1993spb/snake-server/logfields"
+	"github.com/ivan1993spb/snake-server/middlewares"
 )
 
 const URLRouteDeleteGameByID = "/games/{id}"
 
 const MethodDeleteGame = http.MethodDelete
 
+const (
+	fieldForceDelete       = "force"
+	fieldForceDeleteReason = "reason"
+)
+
 type responseDeleteGameHandler struct {
 	ID int `json:"id"`
 }
@@ -44,11 +51,13 @@ func NewDeleteGameHandler(logger logrus.FieldLogger, groupManager *connections.C
 }
 
 func (h *deleteGameHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := middlewares.RequestLogger(h.logger, r)
+
 	vars := mux.Vars(r)
 
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		h.logger.Error(ErrDeleteGameHandler(err.Error()))
+		logger.Error(ErrDeleteGameHandler(err.Error()))
 		h.writeResponseJSON(w, http.StatusBadRequest, &responseDeleteGameHandlerError{
 			Code: http.StatusBadRequest,
 			Text: "invalid game id",
@@ -57,11 +66,30 @@ func (h *deleteGameHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.logger.Infoln("group id to delete:", id)
+	force, err := strconv.ParseBool(r.FormValue(fieldForceDelete))
+	if err != nil {
+		force = false
+	}
+
+	reason := r.FormValue(fieldForceDeleteReason)
+	if len(reason) > connections.MaxCloseReasonLength {
+		logger.Warn(ErrDeleteGameHandler("reason is too long"))
+		h.writeResponseJSON(w, http.StatusBadRequest, &responseDeleteGameHandlerError{
+			Code: http.StatusBadRequest,
+			Text: "reason is too long",
+			ID:   id,
+		})
+		return
+	}
+	if len(reason) == 0 {
+		reason = connections.DefaultCloseReason
+	}
+
+	logger.Infoln("group id to delete:", id)
 
 	group, err := h.groupManager.Get(id)
 	if err != nil {
-		h.logger.Error(ErrDeleteGameHandler(err.Error()))
+		logger.Error(ErrDeleteGameHandler(err.Error()))
 
 		switch err {
 		case connections.ErrNotFoundGroup:
@@ -80,9 +108,20 @@ func (h *deleteGameHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !group.IsEmpty() {
-		h.logger.Warn(ErrDeleteGameHandler("try to delete not empty group"))
-		h.logger.Warnf("there is %d opened connections in group %d", group.GetCount(), id)
+	if !group.IsEmpty() && force {
+		logger.WithFields(logrus.Fields{
+			logfields.GameID: id,
+			"count":          group.GetCount(),
+			"reason":         reason,
+		}).Warn("force delete not empty group")
+
+		// The group is stopped if it has not drained, so it is deleted anyway
+		if !group.Close(reason, connections.ForceDeleteDrainTimeout) {
+			logger.Warn(ErrDeleteGameHandler("group has not drained"))
+		}
+	} else if !group.IsEmpty() {
+		logger.Warn(ErrDeleteGameHandler("try to delete not empty group"))
+		logger.Warnf("there is %d opened connections in group %d", group.GetCount(), id)
 		h.writeResponseJSON(w, http.StatusServiceUnavailable, &responseDeleteGameHandlerError{
 			Code: http.StatusServiceUnavailable,
 			Text: "cannot delete not empty game",
@@ -92,7 +131,7 @@ func (h *deleteGameHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.groupManager.Delete(group); err != nil {
-		h.logger.Error(ErrDeleteGameHandler(err.Error()))
+		logger.Error(ErrDeleteGameHandler(err.Error()))
 
 		switch err {
 		case connections.ErrDeleteNotFoundGroup:
@@ -117,10 +156,10 @@ func (h *deleteGameHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.logger.Info("stop group")
+	logger.Info("stop group")
 	group.Stop()
 
-	h.logger.Infoln("group deleted:", id)
+	logger.Infoln("group deleted:", id)
 
 	h.writeResponseJSON(w, http.StatusOK, responseDeleteGameHandler{
 		ID: id,
@@ -8,14 +8,23 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/gorilla/websocket"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/ivan1993spb/snake-server/connections"
+	"github.com/ivan1993spb/snake-server/logfields"
+	"github.com/ivan1993spb/snake-server/middlewares"
+	"github.com/ivan1993spb/snake-server/tracing"
 )
 
 const URLRouteGameWebSocketByID = "/games/{id}"
 
 const MethodGame = http.MethodGet
 
+// querySpectate is the query parameter which makes a client a spectator. A
+// spectator receives the game stream but takes no place and has no snake
+const querySpectate = "spectate"
+
 const wsReadMessageLimit = 128
 
 const wsReadBufferSize = 2048
@@ -63,14 +72,16 @@ func NewGameWebSocketHandler(logger logrus.FieldLogger, groupManager *connection
 }
 
 func (h *gameWebSocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	h.logger.Info("game handler start")
-	defer h.logger.Info("game handler end")
+	logger := middlewares.RequestLogger(h.logger, r)
+
+	logger.Info("game handler start")
+	defer logger.Info("game handler end")
 
 	vars := mux.Vars(r)
 
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		h.logger.Error(ErrGameWebSocketHandler(err.Error()))
+		logger.Error(ErrGameWebSocketHandler(err.Error()))
 		h.writeResponseJSON(w, http.StatusBadRequest, &responseGameWebSocketHandlerError{
 			Code: http.StatusBadRequest,
 			Text: "invalid game id",
@@ -78,11 +89,26 @@ func (h *gameWebSocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	h.logger.WithField("game", id).Info("try to connect to game group")
+	spectate := false
+	if value := r.URL.Query().Get(querySpectate); value != "" {
+		spectate, err = strconv.ParseBool(value)
+		if err != nil {
+			logger.Error(ErrGameWebSocketHandler(err.Error()))
+			h.writeResponseJSON(w, http.StatusBadRequest, &responseGameWebSocketHandlerError{
+				Code: http.StatusBadRequest,
+				Text: "invalid spectate flag",
+			})
+			return
+		}
+	}
+
+	logger = logger.WithField(logfields.GameID, id)
+
+	logger.Info("try to connect to game group")
 
 	group, err := h.groupManager.Get(id)
 	if err != nil {
-		h.logger.Error(ErrGameWebSocketHandler(err.Error()))
+		logger.Error(ErrGameWebSocketHandler(err.Error()))
 
 		switch err {
 		case connections.ErrNotFoundGroup:
@@ -99,8 +125,17 @@ func (h *gameWebSocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if group.IsFull() {
-		h.logger.Warn(ErrGameWebSocketHandler("group is full"))
+	if group.IsClosed() {
+		logger.Warn(ErrGameWebSocketHandler("group is closed"))
+		h.writeResponseJSON(w, http.StatusServiceUnavailable, &responseGameWebSocketHandlerError{
+			Code: http.StatusServiceUnavailable,
+			Text: "game is closing",
+		})
+		return
+	}
+
+	if !spectate && group.IsFull() {
+		logger.Warn(ErrGameWebSocketHandler("group is full"))
 		h.writeResponseJSON(w, http.StatusServiceUnavailable, &responseGameWebSocketHandlerError{
 			Code: http.StatusServiceUnavailable,
 			Text: "group is full",
@@ -108,25 +143,68 @@ func (h *gameWebSocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	h.logger.Info("upgrade connection")
+	logger.Info("upgrade connection")
+
+	_, upgradeSpan := tracing.Tracer().Start(r.Context(), "websocket.upgrade",
+		trace.WithAttributes(tracing.AttributeGameID.Int(id)))
 
 	conn, err := h.upgrader.Upgrade(w, r, nil)
 	if err != nil {
-		h.logger.Error(ErrGameWebSocketHandler(err.Error()))
+		upgradeSpan.RecordError(err)
+		upgradeSpan.SetStatus(codes.Error, messageUpgradeConnectionError)
+		upgradeSpan.End()
+		logger.Error(ErrGameWebSocketHandler(err.Error()))
 		// Response is written by failed upgrader
 		return
 	}
 
+	upgradeSpan.End()
+
+	// The connection has its own span, the request span ends at the upgrade
+	middlewares.EndRequestSpan(r, http.StatusSwitchingProtocols)
+
 	conn.SetReadLimit(wsReadMessageLimit)
 
-	h.logger.Info("start connection worker")
+	if spectate {
+		h.spectate(r, id, group, conn)
+		return
+	}
+
+	logger.Info("start connection worker")
 
-	if err := group.Handle(connections.NewConnectionWorker(conn, h.logger)); err != nil {
-		h.logger.Error(ErrGameWebSocketHandler(err.Error()))
+	ctx, span := tracing.Tracer().Start(r.Context(), "connection_worker",
+		trace.WithAttributes(tracing.AttributeGameID.Int(id)))
+	defer span.End()
+
+	// The worker's log lines are tied to its span
+	workerLogger := middlewares.RequestLogger(h.logger, r.WithContext(ctx))
+
+	if err := group.Handle(connections.NewConnectionWorker(conn, workerLogger)); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		logger.Error(ErrGameWebSocketHandler(err.Error()))
 		return
 	}
 }
 
+func (h *gameWebSocketHandler) spectate(r *http.Request, id int, group *connections.ConnectionGroup, conn *websocket.Conn) {
+	logger := middlewares.RequestLogger(h.logger, r).WithField(logfields.GameID, id)
+
+	logger.Info("start spectator")
+
+	ctx, span := tracing.Tracer().Start(r.Context(), "spectator",
+		trace.WithAttributes(tracing.AttributeGameID.Int(id)))
+	defer span.End()
+
+	spectatorLogger := middlewares.RequestLogger(h.logger, r.WithContext(ctx))
+
+	if err := group.Spectate(conn, spectatorLogger); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		logger.Error(ErrGameWebSocketHandler(err.Error()))
+	}
+}
+
 func (h *gameWebSocketHandler) errorUpgradeConnection(w http.ResponseWriter, _ *http.Request, status int, _ error) {
 	// Composing error message for upgrade failure case
 	w.Header().Set("Sec-Websocket-Version", "13")
@@ -7,6 +7,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/ivan1993spb/snake-server/connections"
+	"github.com/ivan1993spb/snake-server/middlewares"
 )
 
 const URLRouteGetCapacity = "/capacity"
@@ -36,6 +37,8 @@ func NewGetCapacityHandler(logger logrus.FieldLogger, groupManager *connections.
 }
 
 func (h *getCapacityHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := middlewares.RequestLogger(h.logger, r)
+
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 
@@ -43,6 +46,6 @@ func (h *getCapacityHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		Capacity: h.groupManager.Capacity(),
 	})
 	if err != nil {
-		h.logger.Error(ErrGetGameHandler(err.Error()))
+		logger.Error(ErrGetGameHandler(err.Error()))
 	}
 }
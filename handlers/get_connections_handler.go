@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ivan1993spb/snake-server/connections"
+	"github.com/ivan1993spb/snake-server/middlewares"
+)
+
+const URLRouteGetConnections = "/games/{id}/connections"
+
+const MethodGetConnections = http.MethodGet
+
+// Remote addresses of players are returned only on demand
+const fieldRemoteAddr = "remote_addr"
+
+type responseGetConnectionsHandler struct {
+	ID          int                          `json:"id"`
+	Connections []connections.ConnectionInfo `json:"connections"`
+}
+
+type responseGetConnectionsHandlerError struct {
+	Code int    `json:"code"`
+	Text string `json:"text"`
+	ID   int    `json:"id"`
+}
+
+type getConnectionsHandler struct {
+	logger       logrus.FieldLogger
+	groupManager *connections.ConnectionGroupManager
+}
+
+type ErrGetConnectionsHandler string
+
+func (e ErrGetConnectionsHandler) Error() string {
+	return "get connections handler error: " + string(e)
+}
+
+func NewGetConnectionsHandler(logger logrus.FieldLogger, groupManager *connections.ConnectionGroupManager) http.Handler {
+	return &getConnectionsHandler{
+		logger:       logger,
+		groupManager: groupManager,
+	}
+}
+
+func (h *getConnectionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := middlewares.RequestLogger(h.logger, r)
+
+	vars := mux.Vars(r)
+
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		logger.Error(ErrGetConnectionsHandler(err.Error()))
+		h.writeResponseJSON(w, http.StatusBadRequest, &responseGetConnectionsHandlerError{
+			Code: http.StatusBadRequest,
+			Text: "invalid game id",
+			ID:   id,
+		})
+		return
+	}
+
+	group, err := h.groupManager.Get(id)
+	if err != nil {
+		logger.Error(ErrGetConnectionsHandler(err.Error()))
+
+		switch err {
+		case connections.ErrNotFoundGroup:
+			h.writeResponseJSON(w, http.StatusNotFound, &responseGetConnectionsHandlerError{
+				Code: http.StatusNotFound,
+				Text: "game not found",
+				ID:   id,
+			})
+		default:
+			h.writeResponseJSON(w, http.StatusInternalServerError, &responseGetConnectionsHandlerError{
+				Code: http.StatusInternalServerError,
+				Text: "unknown error",
+				ID:   id,
+			})
+		}
+		return
+	}
+
+	withRemoteAddr, err := strconv.ParseBool(r.FormValue(fieldRemoteAddr))
+	if err != nil {
+		withRemoteAddr = false
+	}
+
+	infos := group.GetConnections()
+	if !withRemoteAddr {
+		for i := range infos {
+			infos[i].RemoteAddr = ""
+		}
+	}
+
+	h.writeResponseJSON(w, http.StatusOK, &responseGetConnectionsHandler{
+		ID:          id,
+		Connections: infos,
+	})
+}
+
+func (h *getConnectionsHandler) writeResponseJSON(w http.ResponseWriter, statusCode int, response interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error(ErrGetConnectionsHandler(err.Error()))
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/ivan1993spb/snake-server/connections"
+	"github.com/ivan1993spb/snake-server/middlewares"
 )
 
 const URLRouteGetGameByID = "/games/{id}"
@@ -49,11 +50,13 @@ func NewGetGameHandler(logger logrus.FieldLogger, groupManager *connections.Conn
 }
 
 func (h *getGameHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := middlewares.RequestLogger(h.logger, r)
+
 	vars := mux.Vars(r)
 
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		h.logger.Error(ErrGetGameHandler(err.Error()))
+		logger.Error(ErrGetGameHandler(err.Error()))
 		h.writeResponseJSON(w, http.StatusBadRequest, &responseGetGameHandlerError{
 			Code: http.StatusBadRequest,
 			Text: "invalid game id",
@@ -62,11 +65,11 @@ func (h *getGameHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.logger.Infoln("group id to get:", id)
+	logger.Infoln("group id to get:", id)
 
 	group, err := h.groupManager.Get(id)
 	if err != nil {
-		h.logger.Errorln("cannot get group:", err.Error())
+		logger.Errorln("cannot get group:", err.Error())
 
 		switch err {
 		case connections.ErrNotFoundGroup:
@@ -2,9 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
-	"errors"
 	"net/http"
-	"sort"
 	"strconv"
 
 	"github.com/sirupsen/logrus"
@@ -21,39 +19,6 @@ const (
 	getFieldGamesSorting = "sorting"
 )
 
-const (
-	gamesSortingLabelSmart  = "smart"
-	gamesSortingLabelRandom = "random"
-)
-
-type gamesSorting uint8
-
-const (
-	gamesSortingUndefined gamesSorting = iota
-	gamesSortingSmart
-	gamesSortingRandom
-)
-
-const gamesSortingDefault = gamesSortingRandom
-
-var errInvalidGamesSorting = errors.New("invalid sorting")
-
-func parseGamesSorting(sorting string) (gamesSorting, error) {
-	if len(sorting) == 0 {
-		return gamesSortingDefault, nil
-	}
-
-	if sorting == gamesSortingLabelSmart {
-		return gamesSortingSmart, nil
-	}
-
-	if sorting == gamesSortingLabelRandom {
-		return gamesSortingRandom, nil
-	}
-
-	return gamesSortingUndefined, errInvalidGamesSorting
-}
-
 type responseGetGamesEntity struct {
 	ID     int    `json:"id"`
 	Limit  int    `json:"limit"`
@@ -93,15 +58,6 @@ func NewGetGamesHandler(logger logrus.FieldLogger, groupManager *connections.Con
 }
 
 func (h *getGamesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	sorting, err := parseGamesSorting(r.URL.Query().Get(getFieldGamesSorting))
-	if err != nil {
-		h.writeResponseJSON(w, http.StatusBadRequest, &responseGetGamesHandlerError{
-			Code: http.StatusBadRequest,
-			Text: "invalid sorting",
-		})
-		return
-	}
-
 	limitLabel := r.URL.Query().Get(getFieldGamesLimit)
 	flagUseLimit := len(limitLabel) > 0
 	limit, err := strconv.Atoi(limitLabel)
@@ -112,50 +68,36 @@ func (h *getGamesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
-
-	groupCount := h.groupManager.GroupCount()
-
-	if groupCount == 0 {
-		h.writeResponseJSON(w, http.StatusOK, &responseGetGamesHandler{
-			Games: []*responseGetGamesEntity{},
-			Limit: h.groupManager.GroupLimit(),
-			Count: groupCount,
-		})
-		return
+	if !flagUseLimit {
+		limit = -1
 	}
 
-	if flagUseLimit && limit == 0 {
-		h.writeResponseJSON(w, http.StatusOK, &responseGetGamesHandler{
-			Games: []*responseGetGamesEntity{},
-			Limit: h.groupManager.GroupLimit(),
-			Count: groupCount,
+	infos, err := h.groupManager.ListGroups(r.URL.Query().Get(getFieldGamesSorting), limit)
+	if err != nil {
+		h.writeResponseJSON(w, http.StatusBadRequest, &responseGetGamesHandlerError{
+			Code: http.StatusBadRequest,
+			Text: "invalid sorting",
 		})
 		return
 	}
 
-	entities := make([]*responseGetGamesEntity, 0, groupCount)
+	entities := make([]*responseGetGamesEntity, 0, len(infos))
 
-	for id, group := range h.groupManager.Groups() {
+	for _, info := range infos {
 		entities = append(entities, &responseGetGamesEntity{
-			ID:     id,
-			Limit:  group.GetLimit(),
-			Count:  group.GetCount(),
-			Width:  int(group.GetWorldWidth()),
-			Height: int(group.GetWorldHeight()),
-			Rate:   group.GetRate(),
+			ID:     info.ID,
+			Limit:  info.Limit,
+			Count:  info.Count,
+			Width:  int(info.Width),
+			Height: int(info.Height),
+			Rate:   info.Rate,
 		})
 	}
 
-	entities = sortGameEntities(sorting, entities)
-
-	if flagUseLimit && limit < len(entities) {
-		entities = entities[:limit]
-	}
-
 	h.writeResponseJSON(w, http.StatusOK, &responseGetGamesHandler{
 		Games: entities,
 		Limit: h.groupManager.GroupLimit(),
-		Count: groupCount,
+		Count: h.groupManager.GroupCount(),
 	})
 }
 
@@ -167,51 +109,3 @@ func (h *getGamesHandler) writeResponseJSON(w http.ResponseWriter, statusCode in
 		h.logger.Error(ErrGetGamesHandler(err.Error()))
 	}
 }
-
-func sortGameEntities(sorting gamesSorting, entities []*responseGetGamesEntity) []*responseGetGamesEntity {
-	if sorting != gamesSortingSmart {
-		return entities
-	}
-
-	emptyGameEntities := filterGameEntities(entities, func(entity *responseGetGamesEntity) bool {
-		return entity.Count == 0
-	})
-
-	fullGameEntities := filterGameEntities(entities, func(entity *responseGetGamesEntity) bool {
-		return entity.Count == entity.Limit
-	})
-
-	relevantGameEntities := filterGameEntities(entities, func(entity *responseGetGamesEntity) bool {
-		return entity.Count > 0 && entity.Count < entity.Limit
-	})
-
-	sort.Slice(emptyGameEntities, func(i, j int) bool {
-		return emptyGameEntities[i].Rate < emptyGameEntities[j].Rate
-	})
-
-	sort.Slice(fullGameEntities, func(i, j int) bool {
-		return fullGameEntities[i].Limit < emptyGameEntities[j].Limit
-	})
-
-	sort.Slice(relevantGameEntities, func(i, j int) bool {
-		return relevantGameEntities[i].Count < relevantGameEntities[j].Count
-	})
-
-	copy(entities, relevantGameEntities)
-	copy(entities[len(relevantGameEntities):], emptyGameEntities)
-	copy(entities[len(relevantGameEntities)+len(emptyGameEntities):], fullGameEntities)
-
-	return entities
-}
-
-type gamesEntityFilter func(entity *responseGetGamesEntity) bool
-
-func filterGameEntities(entities []*responseGetGamesEntity, filter gamesEntityFilter) []*responseGetGamesEntity {
-	resultGameEntities := make([]*responseGetGamesEntity, 0)
-	for _, entity := range entities {
-		if filter(entity) {
-			resultGameEntities = append(resultGameEntities, entity)
-		}
-	}
-	return resultGameEntities
-}
@@ -5,6 +5,8 @@ import (
 
 	"github.com/pquerna/ffjson/ffjson"
 	"github.com/sirupsen/logrus"
+
+	"github.com/ivan1993spb/snake-server/middlewares"
 )
 
 const URLRouteGetInfo = "/info"
@@ -47,10 +49,12 @@ func NewGetInfoHandler(logger logrus.FieldLogger, author, license, version, buil
 }
 
 func (h *getInfoHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := middlewares.RequestLogger(h.logger, r)
+
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 
 	if _, err := w.Write(h.info); err != nil {
-		h.logger.Error(ErrGetInfoHandler(err.Error()))
+		logger.Error(ErrGetInfoHandler(err.Error()))
 	}
 }
@@ -12,6 +12,8 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/ivan1993spb/snake-server/connections"
+	"github.com/ivan1993spb/snake-server/logfields"
+	"github.com/ivan1993spb/snake-server/middlewares"
 )
 
 const URLRouteGetObjects = "/games/{id}/objects"
@@ -90,11 +92,13 @@ func (h *getObjectsHandler) getRetryAfterHeaderSeconds(groupId int) int {
 }
 
 func (h *getObjectsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := middlewares.RequestLogger(h.logger, r)
+
 	vars := mux.Vars(r)
 
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		h.logger.WithError(ErrGetObjectsHandler(err.Error())).Error("parse game id error")
+		logger.WithError(ErrGetObjectsHandler(err.Error())).Error("parse game id error")
 		h.writeResponseJSON(w, http.StatusBadRequest, &responseGetObjectsHandlerError{
 			Code: http.StatusBadRequest,
 			Text: "invalid game id",
@@ -102,11 +106,11 @@ func (h *getObjectsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.logger.WithField("game", id).Infoln("game id received")
+	logger.WithField(logfields.GameID, id).Infoln("game id received")
 
 	group, err := h.groupManager.Get(id)
 	if err != nil {
-		h.logger.WithError(ErrGetObjectsHandler(err.Error())).Error("cannot get game group")
+		logger.WithError(ErrGetObjectsHandler(err.Error())).Error("cannot get game group")
 
 		switch err {
 		case connections.ErrNotFoundGroup:
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ivan1993spb/snake-server/middlewares"
+	"github.com/ivan1993spb/snake-server/webhooks"
+)
+
+const URLRouteGetWebhookDeliveries = "/webhooks/deliveries"
+
+const MethodGetWebhookDeliveries = http.MethodGet
+
+type responseGetWebhookDeliveriesHandler struct {
+	Deliveries []webhooks.Delivery `json:"deliveries"`
+}
+
+type getWebhookDeliveriesHandler struct {
+	logger     logrus.FieldLogger
+	dispatcher *webhooks.Dispatcher
+}
+
+type ErrGetWebhookDeliveriesHandler string
+
+func (e ErrGetWebhookDeliveriesHandler) Error() string {
+	return "get webhook deliveries handler error: " + string(e)
+}
+
+func NewGetWebhookDeliveriesHandler(logger logrus.FieldLogger, dispatcher *webhooks.Dispatcher) http.Handler {
+	return &getWebhookDeliveriesHandler{
+		logger:     logger,
+		dispatcher: dispatcher,
+	}
+}
+
+func (h *getWebhookDeliveriesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := middlewares.RequestLogger(h.logger, r)
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+
+	err := json.NewEncoder(w).Encode(responseGetWebhookDeliveriesHandler{
+		Deliveries: h.dispatcher.Deliveries(),
+	})
+	if err != nil {
+		logger.Error(ErrGetWebhookDeliveriesHandler(err.Error()))
+	}
+}
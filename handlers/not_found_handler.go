@@ -4,6 +4,8 @@ import (
 	"net/http"
 
 	"github.com/sirupsen/logrus"
+
+	"github.com/ivan1993spb/snake-server/middlewares"
 )
 
 var notFoundJSONResponse = []byte(`{"code":404,"text":"not found"}`)
@@ -25,10 +27,12 @@ func NewNotFoundHandler(logger logrus.FieldLogger) http.Handler {
 }
 
 func (h *notFoundHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := middlewares.RequestLogger(h.logger, r)
+
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusNotFound)
 
 	if _, err := w.Write(notFoundJSONResponse); err != nil {
-		h.logger.Error(ErrNotFoundHandler(err.Error()))
+		logger.Error(ErrNotFoundHandler(err.Error()))
 	}
 }
@@ -4,6 +4,8 @@ import (
 	"net/http"
 
 	"github.com/sirupsen/logrus"
+
+	"github.com/ivan1993spb/snake-server/middlewares"
 )
 
 const URLRouteOpenAPI = "/openapi.yaml"
@@ -27,10 +29,12 @@ func NewOpenAPIHandler(logger logrus.FieldLogger, spec []byte) http.Handler {
 }
 
 func (h *openAPIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := middlewares.RequestLogger(h.logger, r)
+
 	w.Header().Set("Content-Type", "text/yaml; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 
 	if _, err := w.Write(h.spec); err != nil {
-		h.logger.Error(ErrOpenAPIHandler(err.Error()))
+		logger.Error(ErrOpenAPIHandler(err.Error()))
 	}
 }
@@ -4,6 +4,8 @@ import (
 	"net/http"
 
 	"github.com/sirupsen/logrus"
+
+	"github.com/ivan1993spb/snake-server/middlewares"
 )
 
 const URLRoutePing = "/ping"
@@ -29,10 +31,12 @@ func NewPingHandler(logger logrus.FieldLogger) http.Handler {
 }
 
 func (h *pingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := middlewares.RequestLogger(h.logger, r)
+
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 
 	if _, err := w.Write(pingResponseBody); err != nil {
-		h.logger.Error(ErrPingHandler(err.Error()))
+		logger.Error(ErrPingHandler(err.Error()))
 	}
 }
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ivan1993spb/snake-server/connections"
+	"github.com/ivan1993spb/snake-server/middlewares"
+)
+
+const URLRouteReady = "/ready"
+
+const MethodReady = http.MethodGet
+
+var (
+	readyResponseBody    = []byte(`{"ready":true}`)
+	notReadyResponseBody = []byte(`{"ready":false}`)
+)
+
+type readyHandler struct {
+	logger       logrus.FieldLogger
+	groupManager *connections.ConnectionGroupManager
+}
+
+type ErrReadyHandler string
+
+func (e ErrReadyHandler) Error() string {
+	return "ready handler error: " + string(e)
+}
+
+// NewReadyHandler returns a handler which reports whether the server accepts
+// new games and connections
+func NewReadyHandler(logger logrus.FieldLogger, groupManager *connections.ConnectionGroupManager) http.Handler {
+	return &readyHandler{
+		logger:       logger,
+		groupManager: groupManager,
+	}
+}
+
+func (h *readyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := middlewares.RequestLogger(h.logger, r)
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	body := readyResponseBody
+	if h.groupManager.IsDraining() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		body = notReadyResponseBody
+	} else {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if _, err := w.Write(body); err != nil {
+		logger.Error(ErrReadyHandler(err.Error()))
+	}
+}
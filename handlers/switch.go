@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// Switch turns handlers on and off while the server is running. A turned off
+// handler responds as if its route did not exist
+type Switch struct {
+	enabled  int32
+	notFound http.Handler
+}
+
+func NewSwitch(notFound http.Handler, enabled bool) *Switch {
+	s := &Switch{
+		notFound: notFound,
+	}
+	s.SetEnabled(enabled)
+	return s
+}
+
+func (s *Switch) SetEnabled(enabled bool) {
+	if enabled {
+		atomic.StoreInt32(&s.enabled, 1)
+	} else {
+		atomic.StoreInt32(&s.enabled, 0)
+	}
+}
+
+func (s *Switch) Enabled() bool {
+	return atomic.LoadInt32(&s.enabled) == 1
+}
+
+// Handler returns the handler which serves requests while the switch is on
+func (s *Switch) Handler(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.Enabled() {
+			handler.ServeHTTP(w, r)
+		} else {
+			s.notFound.ServeHTTP(w, r)
+		}
+	})
+}
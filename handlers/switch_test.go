@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Switch_Handler(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+
+	s := NewSwitch(NewNotFoundHandler(logger), false)
+	handler := s.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	serve := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		return rec
+	}
+
+	rec := serve()
+	require.Equal(t, http.StatusNotFound, rec.Code)
+	require.Equal(t, `{"code":404,"text":"not found"}`, rec.Body.String())
+
+	s.SetEnabled(true)
+	require.True(t, s.Enabled())
+	require.Equal(t, http.StatusNoContent, serve().Code)
+
+	s.SetEnabled(false)
+	require.Equal(t, http.StatusNotFound, serve().Code)
+}
@@ -4,6 +4,8 @@ import (
 	"net/http"
 
 	"github.com/sirupsen/logrus"
+
+	"github.com/ivan1993spb/snake-server/middlewares"
 )
 
 const URLRouteWelcome = "/"
@@ -29,10 +31,12 @@ func NewWelcomeHandler(logger logrus.FieldLogger) http.Handler {
 }
 
 func (h *welcomeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := middlewares.RequestLogger(h.logger, r)
+
 	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 
 	if _, err := w.Write(welcomeMessage); err != nil {
-		h.logger.Error(ErrWelcomeHandler(err.Error()))
+		logger.Error(ErrWelcomeHandler(err.Error()))
 	}
 }
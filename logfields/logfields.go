@@ -0,0 +1,17 @@
+// Package logfields defines names of log fields which tie log lines to
+// requests, games, connections and snakes
+package logfields
+
+const (
+	// RequestID is an identifier of an HTTP request
+	RequestID = "request_id"
+
+	// GameID is an identifier of a game
+	GameID = "game_id"
+
+	// ConnectionID is an identifier of a web-socket connection in a game
+	ConnectionID = "connection_id"
+
+	// SnakeID is an identifier of a snake
+	SnakeID = "snake_id"
+)
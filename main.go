@@ -10,6 +10,8 @@ import (
 	"os"
 	"os/signal"
 	"runtime"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/evalphobia/logrus_sentry"
@@ -20,12 +22,18 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
 	"github.com/urfave/negroni"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
+	"github.com/ivan1993spb/snake-server/cli"
 	"github.com/ivan1993spb/snake-server/client"
 	"github.com/ivan1993spb/snake-server/config"
 	"github.com/ivan1993spb/snake-server/connections"
 	"github.com/ivan1993spb/snake-server/handlers"
 	"github.com/ivan1993spb/snake-server/middlewares"
+	"github.com/ivan1993spb/snake-server/rpc"
+	"github.com/ivan1993spb/snake-server/tracing"
+	"github.com/ivan1993spb/snake-server/webhooks"
 )
 
 const ServerName = "Snake-Server"
@@ -43,7 +51,10 @@ func usage(f *flag.FlagSet) func() {
 	return func() {
 		fmt.Fprint(os.Stderr, "Welcome to snake-server!\n\n")
 		fmt.Fprintf(os.Stderr, "Server version %s, build %s\n\n", Version, Build)
-		fmt.Fprintf(os.Stderr, "Usage: %s [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Usage: %s [options]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s <command> [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Commands: %s\n\n", strings.Join(cli.Names(), ", "))
+		fmt.Fprint(os.Stderr, "Options:\n")
 		f.PrintDefaults()
 	}
 }
@@ -75,8 +86,30 @@ func logger(configLog config.Log) *logrus.Logger {
 	return logger
 }
 
+func sentryHooks(logger logrus.FieldLogger, configSentry config.Sentry) logrus.LevelHooks {
+	hooks := make(logrus.LevelHooks)
+
+	if configSentry.Enable {
+		hook, err := logrus_sentry.NewAsyncSentryHook(configSentry.DSN, []logrus.Level{
+			logrus.PanicLevel,
+			logrus.FatalLevel,
+			logrus.ErrorLevel,
+		})
+
+		if err == nil {
+			hooks.Add(hook)
+		} else {
+			logger.Errorln("cannot create sentry hook:", err)
+		}
+	}
+
+	return hooks
+}
+
 const serverShutdownTimeout = time.Second
 
+const tracingShutdownTimeout = time.Second * 5
+
 func serve(ctx context.Context, logger logrus.FieldLogger,
 	handler http.Handler, address string, configTLS config.TLS) error {
 	server := &http.Server{
@@ -109,8 +142,71 @@ func serve(ctx context.Context, logger logrus.FieldLogger,
 	return err
 }
 
+func serveGRPC(ctx context.Context, logger logrus.FieldLogger,
+	groupManager *connections.ConnectionGroupManager, address string, configTLS config.TLS) error {
+	var options []grpc.ServerOption
+
+	if configTLS.Enable {
+		creds, err := credentials.NewServerTLSFromFile(configTLS.Cert, configTLS.Key)
+		if err != nil {
+			return err
+		}
+		options = append(options, grpc.Creds(creds))
+	}
+
+	server := grpc.NewServer(options...)
+	rpc.RegisterGamesServer(server, rpc.NewServer(logger, groupManager))
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		<-ctx.Done()
+		logger.Info("shutting down gRPC server")
+
+		// Streams which have not finished within the timeout are cancelled
+		stopped := make(chan struct{})
+		go func() {
+			server.GracefulStop()
+			close(stopped)
+		}()
+
+		timer := time.NewTimer(serverShutdownTimeout)
+		defer timer.Stop()
+
+		select {
+		case <-stopped:
+		case <-timer.C:
+			logger.Warn("gRPC server has not stopped gracefully")
+			server.Stop()
+		}
+	}()
+
+	if err := server.Serve(listener); err != nil {
+		return err
+	}
+
+	// Serve returns nil when the server is being stopped
+	<-done
+
+	return nil
+}
+
 func main() {
-	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	if len(os.Args) > 1 && cli.IsCommand(os.Args[1]) {
+		os.Exit(cli.Run(os.Args[0], os.Args[1:], os.Stdout, os.Stderr))
+	}
+
+	signals, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+
+	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	cfg, err := configurate()
@@ -119,17 +215,7 @@ func main() {
 		logger.Fatalln("cannot load config:", err)
 	}
 
-	if cfg.Server.Sentry.Enable {
-		hook, err := logrus_sentry.NewAsyncSentryHook(cfg.Server.Sentry.DSN, []logrus.Level{
-			logrus.PanicLevel,
-			logrus.FatalLevel,
-			logrus.ErrorLevel,
-		})
-
-		if err == nil {
-			logger.Hooks.Add(hook)
-		}
-	}
+	logger.ReplaceHooks(sentryHooks(logger, cfg.Server.Sentry))
 
 	logger.WithFields(logrus.Fields{
 		"author":  Author,
@@ -152,6 +238,10 @@ func main() {
 		"broadcast":    cfg.Server.Flags.EnableBroadcast,
 		"web":          cfg.Server.Flags.EnableWeb,
 		"cors":         !cfg.Server.Flags.ForbidCORS,
+		"grpc":         cfg.Server.GRPC.Enable,
+		"webhooks":     cfg.Server.Webhooks.Enable,
+		"drain_period": cfg.Server.Drain.Period,
+		"tracing":      cfg.Server.Tracing.Enable,
 	}).Info("preparing to start server")
 
 	if cfg.Server.Flags.EnableBroadcast {
@@ -160,6 +250,27 @@ func main() {
 
 	rand.Seed(cfg.Server.Seed)
 
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Server.Tracing, Version)
+	if err != nil {
+		logger.Fatalln("cannot setup tracing:", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+		defer cancel()
+
+		if err := shutdownTracing(ctx); err != nil {
+			logger.Errorln("cannot shutdown tracing:", err)
+		}
+	}()
+
+	if cfg.Server.Tracing.Enable {
+		logger.WithFields(logrus.Fields{
+			"endpoint":     cfg.Server.Tracing.Endpoint,
+			"insecure":     cfg.Server.Tracing.Insecure,
+			"sample_ratio": cfg.Server.Tracing.SampleRatio,
+		}).Info("exporting traces")
+	}
+
 	groupManager, err := connections.NewConnectionGroupManager(logger, cfg.Server.Limits.Groups, cfg.Server.Limits.Conns)
 	if err != nil {
 		logger.Fatalln("cannot create connections group manager:", err)
@@ -168,6 +279,36 @@ func main() {
 		logger.Fatalln("cannot register connection group manager as a metric collector:", err)
 	}
 
+	cors := middlewares.NewSwitchableCORS(!cfg.Server.Flags.ForbidCORS)
+
+	notFoundHandler := handlers.NewNotFoundHandler(logger)
+	broadcastSwitch := handlers.NewSwitch(notFoundHandler, cfg.Server.Flags.EnableBroadcast)
+	adminSwitch := handlers.NewSwitch(notFoundHandler, cfg.Server.Flags.EnableAdmin)
+
+	reloader := newReloader(logger, groupManager, cors, broadcastSwitch, adminSwitch, cfg)
+	reloader.Start(ctx)
+
+	go func() {
+		<-signals.Done()
+		// Restore the default behavior: the next signal kills the server
+		stopSignals()
+
+		period := reloader.Config().Server.Drain.Period
+		logger.WithField("period", period).Info("draining server")
+		groupManager.Drain(period)
+		cancel()
+	}()
+
+	var dispatcher *webhooks.Dispatcher
+	if cfg.Server.Webhooks.Enable {
+		dispatcher, err = webhooks.NewDispatcher(logger, cfg.Server.Webhooks.URLs, cfg.Server.Webhooks.Secret,
+			cfg.Server.Webhooks.AllowedHosts)
+		if err != nil {
+			logger.Fatalln("cannot create webhooks dispatcher:", err)
+		}
+		dispatcher.Start(ctx.Done(), groupManager)
+	}
+
 	rootRouter := mux.NewRouter().StrictSlash(true)
 	rootRouter.Path("/metrics").Handler(promhttp.Handler())
 	if cfg.Server.Flags.Debug {
@@ -180,7 +321,7 @@ func main() {
 	} else {
 		rootRouter.Path(handlers.URLRouteWelcome).Methods(handlers.MethodWelcome).Handler(handlers.NewWelcomeHandler(logger))
 	}
-	rootRouter.NotFoundHandler = handlers.NewNotFoundHandler(logger)
+	rootRouter.NotFoundHandler = notFoundHandler
 
 	// Web-Socket routes
 	wsRouter := rootRouter.PathPrefix("/ws").Subrouter()
@@ -194,24 +335,52 @@ func main() {
 	apiRouter.Path(handlers.URLRouteGetGameByID).Methods(handlers.MethodGetGame).Handler(handlers.NewGetGameHandler(logger, groupManager))
 	apiRouter.Path(handlers.URLRouteDeleteGameByID).Methods(handlers.MethodDeleteGame).Handler(handlers.NewDeleteGameHandler(logger, groupManager))
 	apiRouter.Path(handlers.URLRouteGetGames).Methods(handlers.MethodGetGames).Handler(handlers.NewGetGamesHandler(logger, groupManager))
-	if cfg.Server.Flags.EnableBroadcast {
-		apiRouter.Path(handlers.URLRouteBroadcast).Methods(handlers.MethodBroadcast).Handler(handlers.NewBroadcastHandler(logger, groupManager))
+	// The broadcast and admin routes are switched on and off on config reload
+	apiRouter.Path(handlers.URLRouteBroadcast).Methods(handlers.MethodBroadcast).Handler(broadcastSwitch.Handler(handlers.NewBroadcastHandler(logger, groupManager)))
+	if cfg.Server.Webhooks.Enable && dispatcher.GameWebhooksEnabled() {
+		apiRouter.Path(handlers.URLRouteCreateWebhook).Methods(handlers.MethodCreateWebhook).Handler(handlers.NewCreateWebhookHandler(logger, groupManager, dispatcher))
+	}
+	if cfg.Server.Webhooks.Enable {
+		apiRouter.Path(handlers.URLRouteGetWebhookDeliveries).Methods(handlers.MethodGetWebhookDeliveries).Handler(adminSwitch.Handler(handlers.NewGetWebhookDeliveriesHandler(logger, dispatcher)))
 	}
+	apiRouter.Path(handlers.URLRouteGetConnections).Methods(handlers.MethodGetConnections).Handler(adminSwitch.Handler(handlers.NewGetConnectionsHandler(logger, groupManager)))
+	apiRouter.Path(handlers.URLRouteDeleteConnection).Methods(handlers.MethodDeleteConnection).Handler(adminSwitch.Handler(handlers.NewDeleteConnectionHandler(logger, groupManager)))
 	apiRouter.Path(handlers.URLRouteGetObjects).Methods(handlers.MethodGetObjects).Handler(handlers.NewGetObjectsHandler(logger, groupManager))
 	apiRouter.Path(handlers.URLRoutePing).Methods(handlers.MethodPing).Handler(handlers.NewPingHandler(logger))
+	apiRouter.Path(handlers.URLRouteReady).Methods(handlers.MethodReady).Handler(handlers.NewReadyHandler(logger, groupManager))
 
 	n := negroni.New(
 		middlewares.NewRecovery(logger),
 		middlewares.NewServerInfo(ServerName, Version, Build),
+		middlewares.NewRequestID(),
+		middlewares.NewTracing(rootRouter),
 		middlewares.NewLogger(logger, logName),
 	)
 
-	if !cfg.Server.Flags.ForbidCORS {
-		n.Use(middlewares.NewCORS())
-	}
+	n.Use(cors)
 
 	n.UseHandler(rootRouter)
 
+	grpcErr := make(chan error, 1)
+
+	if cfg.Server.GRPC.Enable {
+		logger.WithFields(logrus.Fields{
+			"address": cfg.Server.GRPC.Address,
+			"tls":     cfg.Server.TLS.Enable,
+		}).Info("starting gRPC server")
+
+		go func() {
+			err := serveGRPC(ctx, logger, groupManager, cfg.Server.GRPC.Address, cfg.Server.TLS)
+			if err != nil {
+				// The HTTP server is stopped, the error is reported by main
+				cancel()
+			}
+			grpcErr <- err
+		}()
+	} else {
+		grpcErr <- nil
+	}
+
 	logger.WithFields(logrus.Fields{
 		"address": cfg.Server.Address,
 		"tls":     cfg.Server.TLS.Enable,
@@ -221,5 +390,9 @@ func main() {
 		logger.Fatalf("server error: %s", err)
 	}
 
+	if err := <-grpcErr; err != nil {
+		logger.Fatalf("gRPC server error: %s", err)
+	}
+
 	logger.Info("buh bye!")
 }
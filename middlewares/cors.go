@@ -1,6 +1,9 @@
 package middlewares
 
 import (
+	"net/http"
+	"sync/atomic"
+
 	"github.com/rs/cors"
 	"github.com/urfave/negroni"
 )
@@ -8,3 +11,34 @@ import (
 func NewCORS() negroni.Handler {
 	return cors.AllowAll()
 }
+
+// SwitchableCORS is a CORS middleware which can be turned on and off while
+// the server is running
+type SwitchableCORS struct {
+	enabled int32
+	cors    negroni.Handler
+}
+
+func NewSwitchableCORS(enabled bool) *SwitchableCORS {
+	c := &SwitchableCORS{
+		cors: NewCORS(),
+	}
+	c.SetEnabled(enabled)
+	return c
+}
+
+func (c *SwitchableCORS) SetEnabled(enabled bool) {
+	if enabled {
+		atomic.StoreInt32(&c.enabled, 1)
+	} else {
+		atomic.StoreInt32(&c.enabled, 0)
+	}
+}
+
+func (c *SwitchableCORS) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	if atomic.LoadInt32(&c.enabled) == 1 {
+		c.cors.ServeHTTP(rw, r, next)
+	} else {
+		next(rw, r)
+	}
+}
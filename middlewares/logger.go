@@ -6,6 +6,9 @@ import (
 	"github.com/meatballhat/negroni-logrus"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/negroni"
+
+	"github.com/ivan1993spb/snake-server/logfields"
+	"github.com/ivan1993spb/snake-server/tracing"
 )
 
 const headerSnakeClient = "X-Snake-Client"
@@ -23,10 +26,15 @@ func before(entry *logrus.Entry, req *http.Request, remoteAddr string) *logrus.E
 		client = req.UserAgent()
 	}
 
-	return entry.WithFields(logrus.Fields{
+	fields := logrus.Fields{
 		"client":  client,
 		"request": req.RequestURI,
 		"method":  req.Method,
 		"remote":  remoteAddr,
-	})
+	}
+	if id, ok := RequestID(req.Context()); ok {
+		fields[logfields.RequestID] = id
+	}
+
+	return entry.WithFields(fields).WithFields(tracing.LogFields(req.Context()))
 }
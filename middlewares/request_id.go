@@ -0,0 +1,64 @@
+package middlewares
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"regexp"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/negroni"
+
+	"github.com/ivan1993spb/snake-server/logfields"
+	"github.com/ivan1993spb/snake-server/tracing"
+)
+
+const headerRequestID = "X-Request-ID"
+
+const requestIDSize = 16
+
+// Incoming request identifiers are accepted if they are reasonably short and
+// safe to be logged
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+type requestIDContextKey struct{}
+
+// NewRequestID returns a middleware which assigns an identifier to every
+// request. An identifier passed by the client in X-Request-ID is kept. The
+// identifier is returned in the same response header
+func NewRequestID() negroni.Handler {
+	return negroni.HandlerFunc(func(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+		id := r.Header.Get(headerRequestID)
+		if !requestIDPattern.MatchString(id) {
+			id = generateRequestID()
+		}
+
+		rw.Header().Set(headerRequestID, id)
+		next(rw, r.WithContext(context.WithValue(r.Context(), requestIDContextKey{}, id)))
+	})
+}
+
+func generateRequestID() string {
+	buf := make([]byte, requestIDSize)
+	if _, err := rand.Read(buf); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(buf)
+}
+
+// RequestID returns the identifier of the request stored in the context
+func RequestID(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDContextKey{}).(string)
+	return id, ok && len(id) > 0
+}
+
+// RequestLogger returns the logger with fields which tie log lines to the
+// request: the request identifier and the identifiers of the current span
+func RequestLogger(logger logrus.FieldLogger, r *http.Request) logrus.FieldLogger {
+	fields := tracing.LogFields(r.Context())
+	if id, ok := RequestID(r.Context()); ok {
+		fields[logfields.RequestID] = id
+	}
+	return logger.WithFields(fields)
+}
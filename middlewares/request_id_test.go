@@ -0,0 +1,54 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/negroni"
+
+	"github.com/ivan1993spb/snake-server/logfields"
+)
+
+func Test_RequestID_AssignsIdentifiers(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+
+	var requestID string
+
+	n := negroni.New(NewRequestID())
+	n.UseHandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID, _ = RequestID(r.Context())
+		RequestLogger(logger, r).Info("handled")
+	})
+
+	// Test case 1
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(headerRequestID, "client-id.1")
+	n.ServeHTTP(rec, req)
+
+	require.Equal(t, "client-id.1", requestID)
+	require.Equal(t, "client-id.1", rec.Header().Get(headerRequestID))
+	require.Equal(t, "client-id.1", hook.LastEntry().Data[logfields.RequestID])
+
+	// Test case 2
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(headerRequestID, "invalid id\n")
+	n.ServeHTTP(rec, req)
+
+	require.Len(t, requestID, requestIDSize*2)
+	require.NotEqual(t, "invalid id\n", requestID)
+	require.Equal(t, requestID, rec.Header().Get(headerRequestID))
+	require.Equal(t, requestID, hook.LastEntry().Data[logfields.RequestID])
+
+	// Test case 3
+	previous := requestID
+	rec = httptest.NewRecorder()
+	n.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	require.Len(t, requestID, requestIDSize*2)
+	require.NotEqual(t, previous, requestID)
+}
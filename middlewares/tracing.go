@@ -0,0 +1,79 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/urfave/negroni"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ivan1993spb/snake-server/tracing"
+)
+
+const unmatchedRoute = "unmatched"
+
+type tracingMiddleware struct {
+	router *mux.Router
+}
+
+// NewTracing returns a middleware which starts a span for every request. The
+// span is named by the path template of the route of the router which
+// matches the request
+func NewTracing(router *mux.Router) negroni.Handler {
+	return &tracingMiddleware{
+		router: router,
+	}
+}
+
+func (m *tracingMiddleware) route(r *http.Request) string {
+	var match mux.RouteMatch
+
+	if m.router.Match(r, &match) && match.Route != nil {
+		if template, err := match.Route.GetPathTemplate(); err == nil {
+			return template
+		}
+	}
+
+	return unmatchedRoute
+}
+
+func (m *tracingMiddleware) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	route := m.route(r)
+
+	ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+	ctx, span := tracing.Tracer().Start(ctx, r.Method+" "+route,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			semconv.HTTPMethod(r.Method),
+			semconv.HTTPRoute(route),
+			semconv.HTTPTarget(r.URL.RequestURI()),
+			semconv.HTTPUserAgent(r.UserAgent()),
+			semconv.NetSockPeerAddr(r.RemoteAddr),
+		),
+	)
+	defer span.End()
+
+	next(rw, r.WithContext(ctx))
+
+	if res, ok := rw.(negroni.ResponseWriter); ok {
+		status := res.Status()
+		span.SetAttributes(semconv.HTTPStatusCode(status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
+
+// EndRequestSpan ends the span of the request with the status code. Handlers
+// of requests which go on after the response, such as web-socket upgrades,
+// end the span when the response is sent. An ended span is not changed by
+// the middleware
+func EndRequestSpan(r *http.Request, status int) {
+	span := trace.SpanFromContext(r.Context())
+	span.SetAttributes(semconv.HTTPStatusCode(status))
+	span.End()
+}
@@ -0,0 +1,67 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/negroni"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+func Test_Tracing_NamesSpansByRouteTemplates(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer otel.SetTracerProvider(otel.GetTracerProvider())
+	otel.SetTracerProvider(provider)
+
+	router := mux.NewRouter()
+	apiRouter := router.PathPrefix("/api").Subrouter()
+	apiRouter.Path("/games/{id}").Methods(http.MethodGet).HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	n := negroni.New(NewTracing(router))
+	n.UseHandler(router)
+
+	n.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/games/7", nil))
+	n.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/nowhere", nil))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	require.Equal(t, "GET /api/games/{id}", spans[0].Name())
+	require.Contains(t, spans[0].Attributes(), semconv.HTTPStatusCode(http.StatusTeapot))
+	require.Contains(t, spans[0].Attributes(), semconv.HTTPTarget("/api/games/7"))
+
+	require.Equal(t, "GET "+unmatchedRoute, spans[1].Name())
+	require.Contains(t, spans[1].Attributes(), semconv.HTTPStatusCode(http.StatusNotFound))
+}
+
+func Test_EndRequestSpan_EndsSpanBeforeHandlerReturns(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer otel.SetTracerProvider(otel.GetTracerProvider())
+	otel.SetTracerProvider(provider)
+
+	router := mux.NewRouter()
+	router.Path("/ws").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		EndRequestSpan(r, http.StatusSwitchingProtocols)
+		require.Len(t, recorder.Ended(), 1, "span has not been ended")
+	})
+
+	n := negroni.New(NewTracing(router))
+	n.UseHandler(router)
+
+	n.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ws", nil))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	require.Contains(t, spans[0].Attributes(), semconv.HTTPStatusCode(http.StatusSwitchingProtocols))
+	require.NotContains(t, spans[0].Attributes(), semconv.HTTPStatusCode(http.StatusOK))
+}
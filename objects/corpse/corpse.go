@@ -2,6 +2,7 @@ package corpse
 
 import (
 	"fmt"
+	"math"
 	"sync"
 	"time"
 
@@ -19,12 +20,33 @@ const corpseNutritionalValue uint16 = 2
 
 const corpseTypeLabel = "corpse"
 
+// Decay contains the rules of corpse decay. The zero Decay keeps corpses
+// whole for 15 seconds
+type Decay struct {
+	// Lifetime is the time for which a corpse lies on the map
+	Lifetime time.Duration
+
+	// Gradual enables gradual decay: a corpse loses its dots one by one
+	// evenly over its lifetime, and the nutritional value of the dots
+	// decreases as the corpse gets older
+	Gradual bool
+}
+
+func (d Decay) lifetime() time.Duration {
+	if d.Lifetime > 0 {
+		return d.Lifetime
+	}
+	return corpseMaxExperience
+}
+
 // Snakes can eat corpses
 // ffjson: skip
 type Corpse struct {
 	id       world.Identifier
 	world    world.Interface
 	location engine.Location
+	decay    Decay
+	created  time.Time
 	mux      *sync.RWMutex
 	stop     chan struct{}
 	stopper  *sync.Once
@@ -37,7 +59,7 @@ func (e errCreateCorpse) Error() string {
 }
 
 // Corpse are created when a snake dies
-func NewCorpse(world world.Interface, location engine.Location) (*Corpse, error) {
+func NewCorpse(world world.Interface, location engine.Location, decay Decay) (*Corpse, error) {
 	if location.Empty() {
 		return nil, errCreateCorpse("location is empty")
 	}
@@ -45,6 +67,8 @@ func NewCorpse(world world.Interface, location engine.Location) (*Corpse, error)
 	corpse := &Corpse{
 		id:      world.IdentifierRegistry().Obtain(),
 		world:   world,
+		decay:   decay,
+		created: time.Now(),
 		mux:     &sync.RWMutex{},
 		stop:    make(chan struct{}),
 		stopper: &sync.Once{},
@@ -98,7 +122,7 @@ func (c *Corpse) Bite(dot engine.Dot) (nv uint16, success bool, err error) {
 			}
 			if len(newLocation) > 0 {
 				c.location = newLocation
-				return corpseNutritionalValue, true, nil
+				return c.unsafeNutritionalValue(time.Now()), true, nil
 			}
 		}
 
@@ -116,43 +140,122 @@ func (c *Corpse) Bite(dot engine.Dot) (nv uint16, success bool, err error) {
 
 		c.location = c.location[:0]
 
-		return corpseNutritionalValue, true, nil
+		return c.unsafeNutritionalValue(time.Now()), true, nil
 	}
 
 	return 0, false, nil
 }
 
+// unsafeNutritionalValue returns the nutritional value of a dot of the corpse
+// at the moment. A gradually decaying corpse loses the value linearly over its
+// lifetime, but a dot is always worth at least 1
+func (c *Corpse) unsafeNutritionalValue(now time.Time) uint16 {
+	if !c.decay.Gradual {
+		return corpseNutritionalValue
+	}
+
+	lifetime := c.decay.lifetime()
+	remaining := lifetime - now.Sub(c.created)
+	if remaining <= 0 {
+		return 1
+	}
+
+	nv := math.Ceil(float64(corpseNutritionalValue) * float64(remaining) / float64(lifetime))
+	if nv < 1 {
+		return 1
+	}
+
+	return uint16(nv)
+}
+
+// unsafeDecay removes a dot of the corpse. It returns true if the corpse has
+// decayed completely
+func (c *Corpse) unsafeDecay() (bool, error) {
+	if len(c.location) > 1 {
+		newLocation := c.location[:len(c.location)-1].Copy()
+		if err := c.world.UpdateObject(c, c.location, newLocation); err != nil {
+			return false, err
+		}
+		c.location = newLocation
+		return false, nil
+	}
+
+	return true, nil
+}
+
 func (c *Corpse) Run(stop <-chan struct{}, logger logrus.FieldLogger) {
 	go func() {
-		var timer = time.NewTimer(corpseMaxExperience)
+		lifetime := c.decay.lifetime()
+
+		var timer = time.NewTimer(lifetime - time.Since(c.created))
 		defer timer.Stop()
-		select {
-		case <-stop:
-			// global stop
-		case <-timer.C:
-			c.mux.Lock()
 
-			var err error
+		var decay <-chan time.Time
 
-			c.stopper.Do(func() {
-				close(c.stop)
-				c.world.IdentifierRegistry().Release(c.id)
-				err = c.world.DeleteObject(c, c.location)
-			})
+		if c.decay.Gradual {
+			c.mux.RLock()
+			dots := len(c.location)
+			c.mux.RUnlock()
 
-			if err != nil {
-				logger.WithError(err).Error("corpse stop error")
+			// The last dot disappears at the end of the lifetime
+			if dots > 0 {
+				if interval := lifetime / time.Duration(dots); interval > 0 {
+					ticker := time.NewTicker(interval)
+					defer ticker.Stop()
+					decay = ticker.C
+				}
 			}
+		}
 
-			c.location = c.location[:0]
-
-			c.mux.Unlock()
-		case <-c.stop:
-			// Corpse was eaten.
+		for {
+			select {
+			case <-stop:
+				// global stop
+				return
+			case <-decay:
+				c.mux.Lock()
+				decayed, err := c.unsafeDecay()
+				if err != nil {
+					logger.WithError(err).Error("corpse decay error")
+				}
+				if decayed {
+					c.unsafeDelete(logger)
+				}
+				c.mux.Unlock()
+
+				if decayed {
+					return
+				}
+			case <-timer.C:
+				c.mux.Lock()
+				c.unsafeDelete(logger)
+				c.mux.Unlock()
+				return
+			case <-c.stop:
+				// Corpse was eaten.
+				return
+			}
 		}
 	}()
 }
 
+// unsafeDelete deletes the corpse from the world if it has not been deleted
+func (c *Corpse) unsafeDelete(logger logrus.FieldLogger) {
+	var err error
+
+	c.stopper.Do(func() {
+		close(c.stop)
+		c.world.IdentifierRegistry().Release(c.id)
+		err = c.world.DeleteObject(c, c.location)
+	})
+
+	if err != nil {
+		logger.WithError(err).Error("corpse stop error")
+	}
+
+	c.location = c.location[:0]
+}
+
 func (c *Corpse) MarshalJSON() ([]byte, error) {
 	c.mux.RLock()
 	defer c.mux.RUnlock()
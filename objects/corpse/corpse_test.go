@@ -3,7 +3,9 @@ package corpse
 import (
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/require"
 
 	"github.com/ivan1993spb/snake-server/engine"
@@ -20,7 +22,7 @@ func Test_NewCorpse_CreatesCorpseAndLocatesObject(t *testing.T) {
 		engine.Dot{9, 0},
 		engine.Dot{8, 0},
 		engine.Dot{7, 0},
-	})
+	}, Decay{})
 	require.Nil(t, err)
 	require.True(t, corpse.location.Equals(engine.Location{
 		engine.Dot{10, 0},
@@ -102,3 +104,106 @@ func Test_Corpse_Bite_ReturnsZeroForInvalidDot(t *testing.T) {
 		engine.Dot{7, 0},
 	}, corpse.location)
 }
+
+func Test_Corpse_unsafeNutritionalValue_DecreasesWithAge(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		decay    Decay
+		age      time.Duration
+		expected uint16
+	}{
+		// Test case 0
+		{
+			decay:    Decay{},
+			age:      time.Minute,
+			expected: corpseNutritionalValue,
+		},
+		// Test case 1
+		{
+			decay:    Decay{Lifetime: time.Second * 10, Gradual: true},
+			age:      0,
+			expected: corpseNutritionalValue,
+		},
+		// Test case 2
+		{
+			decay:    Decay{Lifetime: time.Second * 10, Gradual: true},
+			age:      time.Second * 6,
+			expected: 1,
+		},
+		// Test case 3
+		{
+			decay:    Decay{Lifetime: time.Second * 10, Gradual: true},
+			age:      time.Second * 20,
+			expected: 1,
+		},
+	}
+
+	for i, test := range tests {
+		corpse := &Corpse{
+			decay:   test.decay,
+			created: now.Add(-test.age),
+		}
+		require.Equal(t, test.expected, corpse.unsafeNutritionalValue(now), "test case %d", i)
+	}
+}
+
+func Test_Corpse_Run_DecaysGradually(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	w, err := world.NewWorld(100, 100)
+	require.Nil(t, err)
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	w.Start(stop)
+	events := w.Events(stop, 64)
+
+	location := engine.Location{
+		engine.Dot{X: 10, Y: 0},
+		engine.Dot{X: 9, Y: 0},
+		engine.Dot{X: 8, Y: 0},
+	}
+
+	corpse, err := NewCorpse(w, location, Decay{
+		Lifetime: time.Millisecond * 150,
+		Gradual:  true,
+	})
+	require.Nil(t, err)
+
+	corpse.Run(stop, logger)
+
+	var updates []int
+
+	timeout := time.After(time.Second)
+
+	for {
+		select {
+		case event := <-events:
+			if event.Payload != corpse {
+				continue
+			}
+
+			switch event.Type {
+			case world.EventTypeObjectUpdate:
+				corpse.mux.RLock()
+				updates = append(updates, len(corpse.location))
+				corpse.mux.RUnlock()
+				continue
+			case world.EventTypeObjectDelete:
+			default:
+				continue
+			}
+		case <-timeout:
+			t.Fatal("corpse has not decayed")
+		}
+		break
+	}
+
+	require.Equal(t, []int{2, 1}, updates)
+	for _, dot := range location {
+		require.Nil(t, w.GetObjectByDot(dot))
+	}
+}
@@ -0,0 +1,173 @@
+package fruit
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pquerna/ffjson/ffjson"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ivan1993spb/snake-server/engine"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+const fruitTypeLabel = "fruit"
+
+const (
+	fruitMaxNutritionalValue uint16 = 6
+	fruitMinNutritionalValue uint16 = 1
+)
+
+// Time for which a fruit rots away
+const fruitMaxExperience = time.Second * 30
+
+// Fruit rots: its nutritional value declines over time, and a rotten fruit
+// disappears
+// ffjson: skip
+type Fruit struct {
+	id      world.Identifier
+	world   world.Interface
+	dot     engine.Dot
+	created time.Time
+	mux     *sync.RWMutex
+	stop    chan struct{}
+	stopper *sync.Once
+}
+
+type errCreateFruit string
+
+func (e errCreateFruit) Error() string {
+	return "cannot create fruit: " + string(e)
+}
+
+// NewFruit creates and locates new fruit
+func NewFruit(world world.Interface) (*Fruit, error) {
+	fruit := &Fruit{
+		id:      world.IdentifierRegistry().Obtain(),
+		created: time.Now(),
+		mux:     &sync.RWMutex{},
+		stop:    make(chan struct{}),
+		stopper: &sync.Once{},
+	}
+
+	fruit.mux.Lock()
+	defer fruit.mux.Unlock()
+
+	location, err := world.CreateObjectRandomDot(fruit)
+	if err != nil {
+		world.IdentifierRegistry().Release(fruit.id)
+
+		return nil, errCreateFruit(err.Error())
+	}
+
+	if location.Empty() {
+		world.IdentifierRegistry().Release(fruit.id)
+
+		if err := world.DeleteObject(fruit, location); err != nil {
+			return nil, errCreateFruit("no location located and cannot delete fruit")
+		}
+		return nil, errCreateFruit("no location located")
+	}
+
+	fruit.dot = location.Dot(0)
+	fruit.world = world
+
+	return fruit, nil
+}
+
+func (f *Fruit) String() string {
+	f.mux.RLock()
+	defer f.mux.RUnlock()
+	return fmt.Sprintf("fruit %s", f.dot)
+}
+
+type errFruitBite string
+
+func (e errFruitBite) Error() string {
+	return "fruit bite error: " + string(e)
+}
+
+func (f *Fruit) Bite(dot engine.Dot) (nv uint16, success bool, err error) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+
+	if !f.dot.Equals(dot) {
+		return 0, false, errFruitBite("fruit does not contain dot")
+	}
+
+	if err := f.unsafeDelete(); err != nil {
+		return 0, false, errFruitBite(err.Error())
+	}
+
+	return f.nutritionalValue(time.Now()), true, nil
+}
+
+// nutritionalValue returns the nutritional value of the fruit at the moment.
+// The value declines linearly from the max value to the min value while the
+// fruit rots
+func (f *Fruit) nutritionalValue(now time.Time) uint16 {
+	remaining := fruitMaxExperience - now.Sub(f.created)
+	if remaining <= 0 {
+		return fruitMinNutritionalValue
+	}
+
+	nv := math.Ceil(float64(fruitMaxNutritionalValue) * float64(remaining) / float64(fruitMaxExperience))
+	if nv < float64(fruitMinNutritionalValue) {
+		return fruitMinNutritionalValue
+	}
+
+	return uint16(nv)
+}
+
+func (f *Fruit) unsafeDelete() error {
+	var err error
+
+	f.stopper.Do(func() {
+		close(f.stop)
+		f.world.IdentifierRegistry().Release(f.id)
+		err = f.world.DeleteObject(f, engine.Location{f.dot})
+	})
+
+	return err
+}
+
+// Run deletes the fruit when it has rotted
+func (f *Fruit) Run(stop <-chan struct{}, logger logrus.FieldLogger) {
+	go func() {
+		var timer = time.NewTimer(fruitMaxExperience - time.Since(f.created))
+		defer timer.Stop()
+		select {
+		case <-stop:
+			// global stop
+		case <-timer.C:
+			f.mux.Lock()
+			if err := f.unsafeDelete(); err != nil {
+				logger.WithError(err).Error("fruit stop error")
+			}
+			f.mux.Unlock()
+		case <-f.stop:
+			// Fruit was eaten.
+		}
+	}()
+}
+
+func (f *Fruit) MarshalJSON() ([]byte, error) {
+	f.mux.RLock()
+	defer f.mux.RUnlock()
+	return ffjson.Marshal(&fruit{
+		ID:   f.id,
+		Dot:  f.dot,
+		Type: fruitTypeLabel,
+	})
+}
+
+//go:generate ffjson -force-regenerate $GOFILE
+
+// ffjson: nodecoder
+type fruit struct {
+	ID   world.Identifier `json:"id"`
+	Dot  engine.Dot       `json:"dot"`
+	Type string           `json:"type"`
+}
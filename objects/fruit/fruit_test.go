@@ -0,0 +1,45 @@
+package fruit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Fruit_nutritionalValue_DeclinesWhileFruitRots(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		age      time.Duration
+		expected uint16
+	}{
+		// Test case 0
+		{
+			age:      0,
+			expected: fruitMaxNutritionalValue,
+		},
+		// Test case 1
+		{
+			age:      fruitMaxExperience / 2,
+			expected: fruitMaxNutritionalValue / 2,
+		},
+		// Test case 2
+		{
+			age:      fruitMaxExperience - time.Second,
+			expected: fruitMinNutritionalValue,
+		},
+		// Test case 3
+		{
+			age:      fruitMaxExperience * 2,
+			expected: fruitMinNutritionalValue,
+		},
+	}
+
+	for i, test := range tests {
+		fruit := &Fruit{
+			created: now.Add(-test.age),
+		}
+		require.Equal(t, test.expected, fruit.nutritionalValue(now), "test case %d", i)
+	}
+}
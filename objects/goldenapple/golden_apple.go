@@ -0,0 +1,150 @@
+package goldenapple
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pquerna/ffjson/ffjson"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ivan1993spb/snake-server/engine"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+const goldenAppleTypeLabel = "golden_apple"
+
+const goldenAppleNutritionalValue uint16 = 15
+
+// Time for which a golden apple lies on the map if nobody eats it
+const goldenAppleMaxExperience = time.Second * 10
+
+// GoldenApple is rare food worth many points. It disappears soon if nobody
+// eats it
+// ffjson: skip
+type GoldenApple struct {
+	id      world.Identifier
+	world   world.Interface
+	dot     engine.Dot
+	mux     *sync.RWMutex
+	stop    chan struct{}
+	stopper *sync.Once
+}
+
+type errCreateGoldenApple string
+
+func (e errCreateGoldenApple) Error() string {
+	return "cannot create golden apple: " + string(e)
+}
+
+// NewGoldenApple creates and locates new golden apple
+func NewGoldenApple(world world.Interface) (*GoldenApple, error) {
+	goldenApple := &GoldenApple{
+		id:      world.IdentifierRegistry().Obtain(),
+		mux:     &sync.RWMutex{},
+		stop:    make(chan struct{}),
+		stopper: &sync.Once{},
+	}
+
+	goldenApple.mux.Lock()
+	defer goldenApple.mux.Unlock()
+
+	location, err := world.CreateObjectRandomDot(goldenApple)
+	if err != nil {
+		world.IdentifierRegistry().Release(goldenApple.id)
+
+		return nil, errCreateGoldenApple(err.Error())
+	}
+
+	if location.Empty() {
+		world.IdentifierRegistry().Release(goldenApple.id)
+
+		if err := world.DeleteObject(goldenApple, location); err != nil {
+			return nil, errCreateGoldenApple("no location located and cannot delete golden apple")
+		}
+		return nil, errCreateGoldenApple("no location located")
+	}
+
+	goldenApple.dot = location.Dot(0)
+	goldenApple.world = world
+
+	return goldenApple, nil
+}
+
+func (a *GoldenApple) String() string {
+	a.mux.RLock()
+	defer a.mux.RUnlock()
+	return fmt.Sprintf("golden apple %s", a.dot)
+}
+
+type errGoldenAppleBite string
+
+func (e errGoldenAppleBite) Error() string {
+	return "golden apple bite error: " + string(e)
+}
+
+func (a *GoldenApple) Bite(dot engine.Dot) (nv uint16, success bool, err error) {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	if !a.dot.Equals(dot) {
+		return 0, false, errGoldenAppleBite("golden apple does not contain dot")
+	}
+
+	if err := a.unsafeDelete(); err != nil {
+		return 0, false, errGoldenAppleBite(err.Error())
+	}
+
+	return goldenAppleNutritionalValue, true, nil
+}
+
+func (a *GoldenApple) unsafeDelete() error {
+	var err error
+
+	a.stopper.Do(func() {
+		close(a.stop)
+		a.world.IdentifierRegistry().Release(a.id)
+		err = a.world.DeleteObject(a, engine.Location{a.dot})
+	})
+
+	return err
+}
+
+// Run deletes the golden apple when its time runs out
+func (a *GoldenApple) Run(stop <-chan struct{}, logger logrus.FieldLogger) {
+	go func() {
+		var timer = time.NewTimer(goldenAppleMaxExperience)
+		defer timer.Stop()
+		select {
+		case <-stop:
+			// global stop
+		case <-timer.C:
+			a.mux.Lock()
+			if err := a.unsafeDelete(); err != nil {
+				logger.WithError(err).Error("golden apple stop error")
+			}
+			a.mux.Unlock()
+		case <-a.stop:
+			// Golden apple was eaten.
+		}
+	}()
+}
+
+func (a *GoldenApple) MarshalJSON() ([]byte, error) {
+	a.mux.RLock()
+	defer a.mux.RUnlock()
+	return ffjson.Marshal(&goldenApple{
+		ID:   a.id,
+		Dot:  a.dot,
+		Type: goldenAppleTypeLabel,
+	})
+}
+
+//go:generate ffjson -force-regenerate $GOFILE
+
+// ffjson: nodecoder
+type goldenApple struct {
+	ID   world.Identifier `json:"id"`
+	Dot  engine.Dot       `json:"dot"`
+	Type string           `json:"type"`
+}
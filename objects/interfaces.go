@@ -11,6 +11,15 @@ type Food interface {
 	Bite(dot engine.Dot) (nv uint16, success bool, err error)
 }
 
+// Poisonous interface describes methods which must be implemented by all
+// objects which harm those who eat them
+type Poisonous interface {
+	// Poison bites an object at the passed dot and returns the damage which
+	// shortens the biter, success flag true if the dot has been released or an
+	// error err if one occurred
+	Poison(dot engine.Dot) (damage uint16, success bool, err error)
+}
+
 // Alive interface describes methods which must be implemented by all living
 // objects
 type Alive interface {
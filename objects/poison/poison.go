@@ -0,0 +1,126 @@
+package poison
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/pquerna/ffjson/ffjson"
+
+	"github.com/ivan1993spb/snake-server/engine"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+const poisonTypeLabel = "poison"
+
+// Poison shortens snakes which eat it
+// ffjson: skip
+type Poison struct {
+	id      world.Identifier
+	world   world.Interface
+	dot     engine.Dot
+	mux     *sync.RWMutex
+	stopper *sync.Once
+}
+
+const poisonDamage uint16 = 2
+
+type errCreatePoison string
+
+func (e errCreatePoison) Error() string {
+	return "cannot create poison: " + string(e)
+}
+
+// NewPoison creates and locates new poison
+func NewPoison(world world.Interface) (*Poison, error) {
+	poison := &Poison{
+		id:      world.IdentifierRegistry().Obtain(),
+		mux:     &sync.RWMutex{},
+		stopper: &sync.Once{},
+	}
+
+	poison.mux.Lock()
+	defer poison.mux.Unlock()
+
+	location, err := world.CreateObjectRandomDot(poison)
+	if err != nil {
+		world.IdentifierRegistry().Release(poison.id)
+
+		return nil, errCreatePoison(err.Error())
+	}
+
+	if location.Empty() {
+		world.IdentifierRegistry().Release(poison.id)
+
+		if err := world.DeleteObject(poison, location); err != nil {
+			return nil, errCreatePoison("no location located and cannot delete poison")
+		}
+		return nil, errCreatePoison("no location located")
+	}
+
+	poison.dot = location.Dot(0)
+	poison.world = world
+
+	return poison, nil
+}
+
+func (p *Poison) String() string {
+	p.mux.RLock()
+	defer p.mux.RUnlock()
+	return fmt.Sprintf("poison %s", p.dot)
+}
+
+type errPoisonBite string
+
+func (e errPoisonBite) Error() string {
+	return "poison bite error: " + string(e)
+}
+
+// Poison deletes the poison and returns the damage. Only the first snake which
+// bites the poison gets the damage
+func (p *Poison) Poison(dot engine.Dot) (damage uint16, success bool, err error) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	if !p.dot.Equals(dot) {
+		return 0, false, errPoisonBite("poison does not contain dot")
+	}
+
+	deleted, err := p.unsafeDelete()
+	if err != nil {
+		return 0, false, errPoisonBite(err.Error())
+	}
+	if !deleted {
+		return 0, false, nil
+	}
+
+	return poisonDamage, true, nil
+}
+
+func (p *Poison) unsafeDelete() (deleted bool, err error) {
+	p.stopper.Do(func() {
+		deleted = true
+		p.world.IdentifierRegistry().Release(p.id)
+		err = p.world.DeleteObject(p, engine.Location{p.dot})
+	})
+
+	return deleted, err
+}
+
+func (p *Poison) MarshalJSON() ([]byte, error) {
+	p.mux.RLock()
+	defer p.mux.RUnlock()
+	return ffjson.Marshal(&poison{
+		ID:   p.id,
+		Dot:  p.dot,
+		Type: poisonTypeLabel,
+	})
+}
+
+//go:generate ffjson -force-regenerate $GOFILE
+
+// ffjson: nodecoder
+type poison struct {
+	ID   world.Identifier `json:"id"`
+	Dot  engine.Dot       `json:"dot"`
+	Type string           `json:"type"`
+}
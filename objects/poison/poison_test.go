@@ -0,0 +1,28 @@
+package poison
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+func Test_Poison_Poison_DamagesOnce(t *testing.T) {
+	w, err := world.NewWorld(10, 10)
+	require.Nil(t, err)
+
+	p, err := NewPoison(w)
+	require.Nil(t, err)
+
+	damage, success, err := p.Poison(p.dot)
+	require.Nil(t, err)
+	require.True(t, success)
+	require.Equal(t, poisonDamage, damage)
+	require.Nil(t, w.GetObjectByDot(p.dot))
+
+	damage, success, err = p.Poison(p.dot)
+	require.Nil(t, err)
+	require.False(t, success, "poison has already been eaten")
+	require.Zero(t, damage)
+}
@@ -0,0 +1,23 @@
+package snake
+
+import (
+	"github.com/ivan1993spb/snake-server/engine"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+//go:generate ffjson -force-regenerate $GOFILE
+
+// Bite is the payload of the event which is sent when a snake bites a snake
+// or itself
+// ffjson: nodecoder
+type Bite struct {
+	SnakeID    world.Identifier `json:"snake_id"`
+	AttackerID world.Identifier `json:"attacker_id"`
+	Dot        engine.Dot       `json:"dot"`
+
+	// Self is true if the snake has bitten itself
+	Self bool `json:"self"`
+
+	// Kill is true if the bitten snake dies
+	Kill bool `json:"kill"`
+}
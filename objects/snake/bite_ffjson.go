@@ -0,0 +1,61 @@
+// Code generated by ffjson <https://github.com/pquerna/ffjson>. DO NOT EDIT.
+// source: ./objects/snake/bite.go
+
+package snake
+
+import (
+	fflib "github.com/pquerna/ffjson/fflib/v1"
+)
+
+// MarshalJSON marshal bytes to json - template
+func (j *Bite) MarshalJSON() ([]byte, error) {
+	var buf fflib.Buffer
+	if j == nil {
+		buf.WriteString("null")
+		return buf.Bytes(), nil
+	}
+	err := j.MarshalJSONBuf(&buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// MarshalJSONBuf marshal buff to json - template
+func (j *Bite) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
+	if j == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	var err error
+	var obj []byte
+	_ = obj
+	_ = err
+	buf.WriteString(`{"snake_id":`)
+	fflib.FormatBits2(buf, uint64(j.SnakeID), 10, false)
+	buf.WriteString(`,"attacker_id":`)
+	fflib.FormatBits2(buf, uint64(j.AttackerID), 10, false)
+	buf.WriteString(`,"dot":`)
+
+	{
+
+		obj, err = j.Dot.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		buf.Write(obj)
+
+	}
+	if j.Self {
+		buf.WriteString(`,"self":true`)
+	} else {
+		buf.WriteString(`,"self":false`)
+	}
+	if j.Kill {
+		buf.WriteString(`,"kill":true`)
+	} else {
+		buf.WriteString(`,"kill":false`)
+	}
+	buf.WriteByte('}')
+	return nil
+}
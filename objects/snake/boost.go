@@ -0,0 +1,142 @@
+package snake
+
+import (
+	"time"
+
+	"github.com/ivan1993spb/snake-server/engine"
+)
+
+// BoostConfig contains the rules of the boost command. A boosted snake moves
+// faster and sheds its tail into small food
+type BoostConfig struct {
+	Enable bool
+
+	// Duration is the time for which a boost increases the speed
+	Duration time.Duration
+
+	// SpeedFactor divides the delay between moves of a boosted snake
+	SpeedFactor float64
+
+	// MinLength is the length of a snake which cannot boost. A boost ends
+	// when the snake becomes as short
+	MinLength uint16
+
+	// Cooldown is the time since the end of a boost for which the snake
+	// cannot boost again
+	Cooldown time.Duration
+
+	// ShedEvery is the number of moves of a boosted snake per shed dot
+	ShedEvery uint16
+}
+
+// DefaultBoost contains the rules of the boost command in games with boost
+var DefaultBoost = BoostConfig{
+	Enable:      true,
+	Duration:    time.Second * 2,
+	SpeedFactor: 2,
+	MinLength:   snakeStartLength + 2,
+	Cooldown:    time.Second * 3,
+	ShedEvery:   2,
+}
+
+type errBoost string
+
+func (e errBoost) Error() string {
+	return "boost error: " + string(e)
+}
+
+var (
+	errBoostDisabled  = errBoost("boost is disabled")
+	errBoostCooldown  = errBoost("boost is cooling down")
+	errBoostTooShort  = errBoost("snake is too short")
+	errBoostNoEffect  = errBoost("boost has no effect")
+	errBoostIsRunning = errBoost("snake is already boosted")
+)
+
+// boost starts a boost of the snake
+func (s *Snake) boost() error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	rules := s.config.Boost
+
+	if !rules.Enable {
+		return errBoostDisabled
+	}
+	if rules.Duration <= 0 || rules.SpeedFactor <= 1 {
+		return errBoostNoEffect
+	}
+
+	now := time.Now()
+
+	if s.unsafeIsBoosted(now) {
+		return errBoostIsRunning
+	}
+	if !s.boostEnds.IsZero() && now.Sub(s.boostEnds) < rules.Cooldown {
+		return errBoostCooldown
+	}
+	if s.length <= rules.MinLength {
+		return errBoostTooShort
+	}
+
+	s.boostEnds = now.Add(rules.Duration)
+	s.boostMoves = 0
+
+	return nil
+}
+
+func (s *Snake) unsafeIsBoosted(now time.Time) bool {
+	return now.Before(s.boostEnds)
+}
+
+// unsafeStopBoost ends the boost before its time runs out. The cooldown
+// starts at the moment
+func (s *Snake) unsafeStopBoost() {
+	s.boostEnds = time.Now()
+}
+
+// shed counts a move of a boosted snake and cuts the tail dot off if it is
+// the time to shed. The dot is returned to become food
+func (s *Snake) shed() (engine.Dot, bool, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	rules := s.config.Boost
+
+	if !s.unsafeIsBoosted(time.Now()) {
+		return engine.Dot{}, false, nil
+	}
+
+	s.boostMoves++
+
+	if rules.ShedEvery > 0 && s.boostMoves%rules.ShedEvery != 0 {
+		return engine.Dot{}, false, nil
+	}
+
+	if s.length <= rules.MinLength {
+		s.unsafeStopBoost()
+		return engine.Dot{}, false, nil
+	}
+
+	s.length--
+
+	if s.length <= rules.MinLength {
+		s.unsafeStopBoost()
+	}
+
+	// The snake could be shorter than its length if it has just eaten
+	if uint16(len(s.location)) <= s.length || len(s.location) < 2 {
+		return engine.Dot{}, false, nil
+	}
+
+	tail := s.location[len(s.location)-1]
+	newLocation := s.location[:len(s.location)-1].Copy()
+
+	if err := s.world.UpdateObject(s, s.location, newLocation); err != nil {
+		return engine.Dot{}, false, errBoost(err.Error())
+	}
+
+	s.location = newLocation
+
+	return tail, true, nil
+}
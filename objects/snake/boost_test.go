@@ -0,0 +1,149 @@
+package snake
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivan1993spb/snake-server/engine"
+	"github.com/ivan1993spb/snake-server/objects/corpse"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+func newBoostTestSnake(t *testing.T, length uint16, boost BoostConfig) (*Snake, world.Interface) {
+	w, err := world.NewWorld(100, 100)
+	require.Nil(t, err)
+
+	location := make(engine.Location, length)
+	for i := range location {
+		location[i] = engine.Dot{X: uint8(int(length) - i), Y: 0}
+	}
+
+	s := &Snake{
+		world:     w,
+		length:    length,
+		location:  location,
+		direction: engine.DirectionEast,
+		config: Config{
+			Boost: boost,
+		},
+		mux: &sync.RWMutex{},
+	}
+
+	require.Nil(t, w.CreateObject(s, location.Copy()))
+
+	return s, w
+}
+
+func Test_Snake_boost_Rules(t *testing.T) {
+	s, _ := newBoostTestSnake(t, 10, BoostConfig{})
+	require.Equal(t, errBoostDisabled, s.boost())
+
+	rules := BoostConfig{
+		Enable:      true,
+		Duration:    time.Millisecond * 50,
+		SpeedFactor: 2,
+		MinLength:   10,
+		Cooldown:    time.Millisecond * 100,
+		ShedEvery:   1,
+	}
+
+	s, _ = newBoostTestSnake(t, 10, rules)
+	require.Equal(t, errBoostTooShort, s.boost())
+
+	rules.MinLength = 5
+	s, _ = newBoostTestSnake(t, 10, rules)
+
+	delay := s.calculateDelay()
+	require.Nil(t, s.Command(CommandBoost))
+	require.Equal(t, delay/2, s.calculateDelay())
+	require.Equal(t, errBoostIsRunning, s.boost())
+
+	time.Sleep(rules.Duration)
+	require.Equal(t, delay, s.calculateDelay())
+	require.Equal(t, errBoostCooldown, s.boost())
+
+	time.Sleep(rules.Cooldown)
+	require.Nil(t, s.boost())
+}
+
+func Test_Snake_boost_CooldownStartsWhenBoostStops(t *testing.T) {
+	rules := BoostConfig{
+		Enable:      true,
+		Duration:    time.Minute,
+		SpeedFactor: 2,
+		MinLength:   5,
+		Cooldown:    time.Millisecond * 100,
+	}
+
+	s, _ := newBoostTestSnake(t, 10, rules)
+
+	require.Nil(t, s.boost())
+
+	s.mux.Lock()
+	s.unsafeStopBoost()
+	s.mux.Unlock()
+
+	require.False(t, s.unsafeIsBoosted(time.Now()), "boost must stop")
+	require.Equal(t, errBoostCooldown, s.boost(), "early stop must not skip the cooldown")
+
+	time.Sleep(rules.Cooldown)
+	require.Nil(t, s.boost())
+}
+
+func Test_Snake_shed_CutsTailUntilMinLength(t *testing.T) {
+	s, w := newBoostTestSnake(t, 8, BoostConfig{
+		Enable:      true,
+		Duration:    time.Minute,
+		SpeedFactor: 2,
+		MinLength:   5,
+		ShedEvery:   2,
+	})
+
+	_, ok, err := s.shed()
+	require.Nil(t, err)
+	require.False(t, ok, "snake is not boosted")
+
+	require.Nil(t, s.boost())
+
+	var shed []engine.Dot
+
+	for i := 0; i < 10; i++ {
+		dot, ok, err := s.shed()
+		require.Nil(t, err)
+		if ok {
+			shed = append(shed, dot)
+			require.Nil(t, w.GetObjectByDot(dot))
+		}
+	}
+
+	require.Equal(t, []engine.Dot{{X: 1, Y: 0}, {X: 2, Y: 0}, {X: 3, Y: 0}}, shed)
+	require.Equal(t, uint16(5), s.length)
+	require.Len(t, s.GetLocation(), 5)
+	require.False(t, s.unsafeIsBoosted(time.Now()), "boost must stop at min length")
+}
+
+func Test_Snake_dropFood_CreatesCorpse(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	w, err := world.NewWorld(10, 10)
+	require.Nil(t, err)
+
+	s := &Snake{
+		world: w,
+		mux:   &sync.RWMutex{},
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	dot := engine.Dot{X: 3, Y: 4}
+	s.dropFood(stop, engine.Location{dot}, logger)
+
+	_, ok := w.GetObjectByDot(dot).(*corpse.Corpse)
+	require.True(t, ok, "food has not been dropped")
+}
@@ -0,0 +1,313 @@
+package snake
+
+import (
+	"math"
+	"sort"
+
+	"github.com/ivan1993spb/snake-server/engine"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+// HeadToHeadRule decides the outcome of a collision of two snake heads
+type HeadToHeadRule uint8
+
+const (
+	// HeadToHeadForce resolves the collision as any other hit: the hit snake
+	// loses only if the attacker is strong enough
+	HeadToHeadForce HeadToHeadRule = iota
+	// HeadToHeadBothDie kills both snakes
+	HeadToHeadBothDie
+	// HeadToHeadLongerWins kills the shorter snake or both snakes if they
+	// have the same length
+	HeadToHeadLongerWins
+)
+
+// HeadToBodyRule decides the outcome of a collision of a snake head with the
+// body of another snake
+type HeadToBodyRule uint8
+
+const (
+	// HeadToBodyForce resolves the collision as any other hit: the hit snake
+	// loses only if the attacker is strong enough
+	HeadToBodyForce HeadToBodyRule = iota
+	// HeadToBodyAttackerDies kills the snake which has run into the body
+	HeadToBodyAttackerDies
+	// HeadToBodyDefenderCut cuts the hit snake at the bitten dot. The front
+	// part survives and the tail behind the dot disappears
+	HeadToBodyDefenderCut
+	// HeadToBodySever cuts the hit snake at the bitten dot. The front part
+	// survives and the severed tail becomes a corpse
+	HeadToBodySever
+)
+
+// SelfBiteRule decides the outcome of a collision of a snake head with the
+// body of the same snake
+type SelfBiteRule uint8
+
+const (
+	// SelfBiteDie kills the snake
+	SelfBiteDie SelfBiteRule = iota
+	// SelfBiteTrim cuts the snake at the bitten dot. The trimmed tail becomes
+	// a corpse
+	SelfBiteTrim
+)
+
+const (
+	CollisionRuleForce        = "force"
+	CollisionRuleBothDie      = "both_die"
+	CollisionRuleLongerWins   = "longer_wins"
+	CollisionRuleAttackerDies = "attacker_dies"
+	CollisionRuleDefenderCut  = "defender_cut"
+	CollisionRuleSever        = "sever"
+	CollisionRuleDie          = "die"
+	CollisionRuleTrim         = "trim"
+)
+
+var headToHeadRules = map[string]HeadToHeadRule{
+	CollisionRuleForce:      HeadToHeadForce,
+	CollisionRuleBothDie:    HeadToHeadBothDie,
+	CollisionRuleLongerWins: HeadToHeadLongerWins,
+}
+
+var headToBodyRules = map[string]HeadToBodyRule{
+	CollisionRuleForce:        HeadToBodyForce,
+	CollisionRuleAttackerDies: HeadToBodyAttackerDies,
+	CollisionRuleDefenderCut:  HeadToBodyDefenderCut,
+	CollisionRuleSever:        HeadToBodySever,
+}
+
+var selfBiteRules = map[string]SelfBiteRule{
+	CollisionRuleDie:  SelfBiteDie,
+	CollisionRuleTrim: SelfBiteTrim,
+}
+
+// GetHeadToHeadRule returns a head-to-head collision rule by the name
+func GetHeadToHeadRule(name string) (HeadToHeadRule, bool) {
+	rule, ok := headToHeadRules[name]
+	return rule, ok
+}
+
+// GetHeadToBodyRule returns a head-to-body collision rule by the name
+func GetHeadToBodyRule(name string) (HeadToBodyRule, bool) {
+	rule, ok := headToBodyRules[name]
+	return rule, ok
+}
+
+// GetSelfBiteRule returns a self-bite rule by the name
+func GetSelfBiteRule(name string) (SelfBiteRule, bool) {
+	rule, ok := selfBiteRules[name]
+	return rule, ok
+}
+
+// HeadToHeadRuleNames returns the sorted names of head-to-head collision rules
+func HeadToHeadRuleNames() []string {
+	names := make([]string, 0, len(headToHeadRules))
+	for name := range headToHeadRules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// HeadToBodyRuleNames returns the sorted names of head-to-body collision rules
+func HeadToBodyRuleNames() []string {
+	names := make([]string, 0, len(headToBodyRules))
+	for name := range headToBodyRules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SelfBiteRuleNames returns the sorted names of self-bite rules
+func SelfBiteRuleNames() []string {
+	names := make([]string, 0, len(selfBiteRules))
+	for name := range selfBiteRules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CollisionConfig contains the rules of collisions between snakes. The zero
+// CollisionConfig resolves all collisions by force and kills snakes which
+// bite themselves
+type CollisionConfig struct {
+	HeadToHead HeadToHeadRule
+	HeadToBody HeadToBodyRule
+	SelfBite   SelfBiteRule
+}
+
+// resolve returns the outcome of a collision of the attacker snake with the
+// defender snake. If neither snake dies, the defender is cut or severed by the
+// head-to-body rule. The outcome
+// of a head-to-head collision does not depend on which snake has moved first
+// unless the collision is resolved by force
+func (c CollisionConfig) resolve(head bool, attacker, defender uint16) (attackerDies, defenderDies bool) {
+	if head {
+		switch c.HeadToHead {
+		case HeadToHeadBothDie:
+			return true, true
+		case HeadToHeadLongerWins:
+			return attacker <= defender, defender <= attacker
+		}
+	} else {
+		switch c.HeadToBody {
+		case HeadToBodyAttackerDies:
+			return true, false
+		case HeadToBodyDefenderCut, HeadToBodySever:
+			return false, false
+		}
+	}
+
+	defenderDies = float64(attacker) >= math.Pow(float64(defender), hitStrengthExp)
+
+	return !defenderDies, defenderDies
+}
+
+// collide resolves a collision of the attacker snake with the given
+// identifier and length with the snake at the dot. It returns success flag
+// true if the dot has been released
+func (s *Snake) collide(dot engine.Dot, attackerID world.Identifier, attacker uint16, rules CollisionConfig) (success bool, err error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if !s.location.Contains(dot) {
+		return false, errSnakeHit("snake does not contain dot")
+	}
+
+	head := s.location[0] == dot
+
+	attackerDies, defenderDies := rules.resolve(head, attacker, s.length)
+
+	if defenderDies {
+		if err := s.unsafeCut(dot); err != nil {
+			return false, err
+		}
+	} else if !attackerDies && rules.HeadToBody == HeadToBodyDefenderCut {
+		if _, err := s.unsafeCutOff(dot); err != nil {
+			return false, err
+		}
+	} else if !attackerDies {
+		if err := s.unsafeSever(dot); err != nil {
+			return false, err
+		}
+	}
+
+	if defenderDies || !attackerDies {
+		s.world.ReportEvent(world.EventTypeObjectBite, Bite{
+			SnakeID:    s.id,
+			AttackerID: attackerID,
+			Dot:        dot,
+			Kill:       s.unsafeIsStopped(),
+		})
+	}
+
+	return !attackerDies, nil
+}
+
+// biteSelf resolves a collision of the snake with its own body. It returns
+// success flag true if the dot has been released
+func (s *Snake) biteSelf(dot engine.Dot) (success bool, err error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	i := s.location.IndexOf(dot)
+	if i < 0 {
+		return false, errSnakeHit("snake does not contain dot")
+	}
+
+	trim := s.config.Collision.SelfBite == SelfBiteTrim && i >= snakeMinSeveredLength
+
+	if trim {
+		if err := s.unsafeSever(dot); err != nil {
+			return false, err
+		}
+	}
+
+	s.world.ReportEvent(world.EventTypeObjectBite, Bite{
+		SnakeID:    s.id,
+		AttackerID: s.id,
+		Dot:        dot,
+		Self:       true,
+		Kill:       !trim,
+	})
+
+	return trim, nil
+}
+
+func (s *Snake) unsafeIsStopped() bool {
+	select {
+	case <-s.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// unsafeCut deletes the dot from the snake and stops the snake
+func (s *Snake) unsafeCut(dot engine.Dot) error {
+	newLocation := s.location.Delete(dot)
+	if err := s.world.UpdateObject(s, s.location, newLocation); err != nil {
+		return errSnakeHit(err.Error())
+	}
+
+	s.location = newLocation
+
+	s.stopper.Do(func() {
+		close(s.stop)
+	})
+
+	return nil
+}
+
+// snakeMinSeveredLength is the length of the shortest front part of a snake
+// which survives severing
+const snakeMinSeveredLength = 2
+
+// unsafeCutOff cuts the snake at the dot. The front part of the snake keeps
+// moving and the tail behind the dot is released and returned. A snake which
+// would be too short dies
+func (s *Snake) unsafeCutOff(dot engine.Dot) (engine.Location, error) {
+	i := s.location.IndexOf(dot)
+	if i < 0 {
+		return nil, errSnakeHit("snake does not contain dot")
+	}
+
+	if i < snakeMinSeveredLength {
+		return nil, s.unsafeCut(dot)
+	}
+
+	front := s.location[:i].Copy()
+	tail := s.location[i+1:].Copy()
+
+	if err := s.world.UpdateObject(s, s.location, front); err != nil {
+		return nil, errSnakeHit(err.Error())
+	}
+
+	s.location = front
+	s.length = uint16(len(front))
+
+	return tail, nil
+}
+
+// unsafeSever cuts the snake at the dot. The tail behind the dot is kept by
+// the snake to be dropped as a corpse
+func (s *Snake) unsafeSever(dot engine.Dot) error {
+	tail, err := s.unsafeCutOff(dot)
+	if err != nil {
+		return err
+	}
+
+	if len(tail) > 0 {
+		s.severed = append(s.severed, tail)
+
+		select {
+		case s.chSevered <- struct{}{}:
+		default:
+			// The snake has been notified already
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,551 @@
+package snake
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivan1993spb/snake-server/engine"
+	"github.com/ivan1993spb/snake-server/objects/corpse"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+func Test_CollisionConfig_resolve(t *testing.T) {
+	tests := []struct {
+		rules        CollisionConfig
+		head         bool
+		attacker     uint16
+		defender     uint16
+		attackerDies bool
+		defenderDies bool
+	}{
+		// Test case 0
+		{
+			rules:        CollisionConfig{},
+			head:         true,
+			attacker:     10,
+			defender:     5,
+			attackerDies: true,
+			defenderDies: false,
+		},
+		// Test case 1
+		{
+			rules:        CollisionConfig{},
+			head:         false,
+			attacker:     25,
+			defender:     5,
+			attackerDies: false,
+			defenderDies: true,
+		},
+		// Test case 2
+		{
+			rules:        CollisionConfig{HeadToHead: HeadToHeadBothDie},
+			head:         true,
+			attacker:     10,
+			defender:     5,
+			attackerDies: true,
+			defenderDies: true,
+		},
+		// Test case 3
+		{
+			rules:        CollisionConfig{HeadToHead: HeadToHeadLongerWins},
+			head:         true,
+			attacker:     10,
+			defender:     5,
+			attackerDies: false,
+			defenderDies: true,
+		},
+		// Test case 4
+		{
+			rules:        CollisionConfig{HeadToHead: HeadToHeadLongerWins},
+			head:         true,
+			attacker:     5,
+			defender:     10,
+			attackerDies: true,
+			defenderDies: false,
+		},
+		// Test case 5
+		{
+			rules:        CollisionConfig{HeadToHead: HeadToHeadLongerWins},
+			head:         true,
+			attacker:     7,
+			defender:     7,
+			attackerDies: true,
+			defenderDies: true,
+		},
+		// Test case 6
+		{
+			rules:        CollisionConfig{HeadToHead: HeadToHeadLongerWins},
+			head:         false,
+			attacker:     10,
+			defender:     5,
+			attackerDies: true,
+			defenderDies: false,
+		},
+		// Test case 7
+		{
+			rules:        CollisionConfig{HeadToBody: HeadToBodyAttackerDies},
+			head:         false,
+			attacker:     100,
+			defender:     3,
+			attackerDies: true,
+			defenderDies: false,
+		},
+		// Test case 8
+		{
+			rules:        CollisionConfig{HeadToBody: HeadToBodyDefenderCut},
+			head:         false,
+			attacker:     3,
+			defender:     100,
+			attackerDies: false,
+			defenderDies: false,
+		},
+		// Test case 9
+		{
+			rules:        CollisionConfig{HeadToBody: HeadToBodyDefenderCut},
+			head:         true,
+			attacker:     3,
+			defender:     100,
+			attackerDies: true,
+			defenderDies: false,
+		},
+		// Test case 10
+		{
+			rules:        CollisionConfig{HeadToBody: HeadToBodySever},
+			head:         false,
+			attacker:     3,
+			defender:     100,
+			attackerDies: false,
+			defenderDies: false,
+		},
+	}
+
+	for i, test := range tests {
+		attackerDies, defenderDies := test.rules.resolve(test.head, test.attacker, test.defender)
+		require.Equal(t, test.attackerDies, attackerDies, "test case %d", i)
+		require.Equal(t, test.defenderDies, defenderDies, "test case %d", i)
+	}
+}
+
+func Test_CollisionConfig_resolve_HeadToHeadIsFair(t *testing.T) {
+	rules := []HeadToHeadRule{
+		HeadToHeadBothDie,
+		HeadToHeadLongerWins,
+	}
+
+	for _, rule := range rules {
+		config := CollisionConfig{HeadToHead: rule}
+
+		for first := uint16(1); first < 20; first++ {
+			for second := uint16(1); second < 20; second++ {
+				firstDies, secondDies := config.resolve(true, first, second)
+				secondDies2, firstDies2 := config.resolve(true, second, first)
+
+				require.Equal(t, firstDies, firstDies2, "rule %d, lengths %d and %d", rule, first, second)
+				require.Equal(t, secondDies, secondDies2, "rule %d, lengths %d and %d", rule, first, second)
+			}
+		}
+	}
+}
+
+func newCollisionTestSnake(t *testing.T, w world.Interface, rules CollisionConfig, location engine.Location) *Snake {
+	s := &Snake{
+		world:    w,
+		length:   uint16(len(location)),
+		location: location,
+		config: Config{
+			Collision: rules,
+		},
+		direction: engine.DirectionEast,
+		mux:       &sync.RWMutex{},
+		chSevered: make(chan struct{}, 1),
+		stopper:   &sync.Once{},
+		stop:      make(chan struct{}),
+	}
+
+	require.Nil(t, w.CreateObject(s, location.Copy()))
+
+	return s
+}
+
+func isStopped(s *Snake) bool {
+	select {
+	case <-s.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+func Test_Snake_move_CollisionRules(t *testing.T) {
+	tests := []struct {
+		rules CollisionConfig
+		// The attacker moves east into the defender
+		attacker engine.Location
+		defender engine.Location
+
+		attackerMoves bool
+		defenderDies  bool
+	}{
+		// Test case 0
+		{
+			rules: CollisionConfig{HeadToHead: HeadToHeadLongerWins},
+			attacker: engine.Location{
+				engine.Dot{X: 10, Y: 10},
+				engine.Dot{X: 9, Y: 10},
+				engine.Dot{X: 8, Y: 10},
+				engine.Dot{X: 7, Y: 10},
+			},
+			defender: engine.Location{
+				engine.Dot{X: 11, Y: 10},
+				engine.Dot{X: 12, Y: 10},
+				engine.Dot{X: 13, Y: 10},
+			},
+			attackerMoves: true,
+			defenderDies:  true,
+		},
+		// Test case 1
+		{
+			rules: CollisionConfig{HeadToHead: HeadToHeadLongerWins},
+			attacker: engine.Location{
+				engine.Dot{X: 10, Y: 10},
+				engine.Dot{X: 9, Y: 10},
+				engine.Dot{X: 8, Y: 10},
+			},
+			defender: engine.Location{
+				engine.Dot{X: 11, Y: 10},
+				engine.Dot{X: 12, Y: 10},
+				engine.Dot{X: 13, Y: 10},
+				engine.Dot{X: 14, Y: 10},
+			},
+			attackerMoves: false,
+			defenderDies:  false,
+		},
+		// Test case 2
+		{
+			rules: CollisionConfig{HeadToHead: HeadToHeadBothDie},
+			attacker: engine.Location{
+				engine.Dot{X: 10, Y: 10},
+				engine.Dot{X: 9, Y: 10},
+				engine.Dot{X: 8, Y: 10},
+				engine.Dot{X: 7, Y: 10},
+			},
+			defender: engine.Location{
+				engine.Dot{X: 11, Y: 10},
+				engine.Dot{X: 12, Y: 10},
+				engine.Dot{X: 13, Y: 10},
+			},
+			attackerMoves: false,
+			defenderDies:  true,
+		},
+		// Test case 3
+		{
+			rules: CollisionConfig{HeadToBody: HeadToBodyDefenderCut},
+			attacker: engine.Location{
+				engine.Dot{X: 10, Y: 10},
+				engine.Dot{X: 9, Y: 10},
+				engine.Dot{X: 8, Y: 10},
+			},
+			defender: engine.Location{
+				engine.Dot{X: 11, Y: 9},
+				engine.Dot{X: 11, Y: 10},
+				engine.Dot{X: 11, Y: 11},
+			},
+			attackerMoves: true,
+			defenderDies:  true,
+		},
+		// Test case 4
+		{
+			rules: CollisionConfig{HeadToBody: HeadToBodyAttackerDies},
+			attacker: engine.Location{
+				engine.Dot{X: 10, Y: 10},
+				engine.Dot{X: 9, Y: 10},
+				engine.Dot{X: 8, Y: 10},
+			},
+			defender: engine.Location{
+				engine.Dot{X: 11, Y: 9},
+				engine.Dot{X: 11, Y: 10},
+				engine.Dot{X: 11, Y: 11},
+			},
+			attackerMoves: false,
+			defenderDies:  false,
+		},
+	}
+
+	for i, test := range tests {
+		w, err := world.NewWorld(100, 100)
+		require.Nil(t, err)
+
+		attacker := newCollisionTestSnake(t, w, test.rules, test.attacker)
+		defender := newCollisionTestSnake(t, w, test.rules, test.defender)
+
+		err = attacker.move()
+		if test.attackerMoves {
+			require.Nil(t, err, "test case %d", i)
+			require.Equal(t, engine.Dot{X: 11, Y: 10}, attacker.GetLocation()[0], "test case %d", i)
+			require.Equal(t, uint16(len(test.attacker))+snakeHitAward, attacker.getLength(), "test case %d", i)
+		} else {
+			require.Equal(t, errUnsuccessfulInteraction, err, "test case %d", i)
+		}
+
+		require.Equal(t, test.defenderDies, isStopped(defender), "test case %d", i)
+		require.Equal(t, test.defenderDies, !defender.GetLocation().Contains(engine.Dot{X: 11, Y: 10}), "test case %d", i)
+	}
+}
+
+func Test_Snake_move_CutsDefender(t *testing.T) {
+	w, err := world.NewWorld(100, 100)
+	require.Nil(t, err)
+
+	rules := CollisionConfig{HeadToBody: HeadToBodyDefenderCut}
+
+	attacker := newCollisionTestSnake(t, w, rules, engine.Location{
+		engine.Dot{X: 10, Y: 10},
+		engine.Dot{X: 9, Y: 10},
+		engine.Dot{X: 8, Y: 10},
+	})
+	defender := newCollisionTestSnake(t, w, rules, engine.Location{
+		engine.Dot{X: 11, Y: 8},
+		engine.Dot{X: 11, Y: 9},
+		engine.Dot{X: 11, Y: 10},
+		engine.Dot{X: 11, Y: 11},
+		engine.Dot{X: 11, Y: 12},
+	})
+
+	require.Nil(t, attacker.move())
+	require.Equal(t, engine.Dot{X: 11, Y: 10}, attacker.GetLocation()[0])
+
+	require.False(t, isStopped(defender), "defender must survive")
+	require.Equal(t, engine.Location{
+		engine.Dot{X: 11, Y: 8},
+		engine.Dot{X: 11, Y: 9},
+	}, defender.GetLocation())
+	require.Equal(t, uint16(2), defender.getLength())
+
+	require.Empty(t, defender.takeSevered(), "cut tail must not become a corpse")
+	require.Nil(t, w.GetObjectByDot(engine.Dot{X: 11, Y: 11}))
+	require.Nil(t, w.GetObjectByDot(engine.Dot{X: 11, Y: 12}))
+}
+
+func Test_Snake_move_SeversDefender(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	w, err := world.NewWorld(100, 100)
+	require.Nil(t, err)
+
+	rules := CollisionConfig{HeadToBody: HeadToBodySever}
+
+	attacker := newCollisionTestSnake(t, w, rules, engine.Location{
+		engine.Dot{X: 10, Y: 10},
+		engine.Dot{X: 9, Y: 10},
+		engine.Dot{X: 8, Y: 10},
+	})
+	defender := newCollisionTestSnake(t, w, rules, engine.Location{
+		engine.Dot{X: 11, Y: 8},
+		engine.Dot{X: 11, Y: 9},
+		engine.Dot{X: 11, Y: 10},
+		engine.Dot{X: 11, Y: 11},
+		engine.Dot{X: 11, Y: 12},
+	})
+
+	require.Nil(t, attacker.move())
+	require.Equal(t, engine.Dot{X: 11, Y: 10}, attacker.GetLocation()[0])
+
+	require.False(t, isStopped(defender), "defender must survive")
+	require.Equal(t, engine.Location{
+		engine.Dot{X: 11, Y: 8},
+		engine.Dot{X: 11, Y: 9},
+	}, defender.GetLocation())
+	require.Equal(t, uint16(2), defender.getLength())
+
+	tails := defender.takeSevered()
+	require.Len(t, tails, 1)
+
+	tail := tails[0]
+	require.Equal(t, engine.Location{
+		engine.Dot{X: 11, Y: 11},
+		engine.Dot{X: 11, Y: 12},
+	}, tail)
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	defender.dropFood(stop, tail, logger)
+
+	c, ok := w.GetObjectByDot(engine.Dot{X: 11, Y: 12}).(*corpse.Corpse)
+	require.True(t, ok, "severed tail has not become a corpse")
+	require.Equal(t, c, w.GetObjectByDot(engine.Dot{X: 11, Y: 11}))
+}
+
+func Test_Snake_move_SeverKillsTooShortDefender(t *testing.T) {
+	w, err := world.NewWorld(100, 100)
+	require.Nil(t, err)
+
+	rules := CollisionConfig{HeadToBody: HeadToBodySever}
+
+	attacker := newCollisionTestSnake(t, w, rules, engine.Location{
+		engine.Dot{X: 10, Y: 10},
+		engine.Dot{X: 9, Y: 10},
+		engine.Dot{X: 8, Y: 10},
+	})
+	defender := newCollisionTestSnake(t, w, rules, engine.Location{
+		engine.Dot{X: 11, Y: 9},
+		engine.Dot{X: 11, Y: 10},
+		engine.Dot{X: 11, Y: 11},
+	})
+
+	require.Nil(t, attacker.move())
+	require.True(t, isStopped(defender), "defender must die")
+}
+
+func Test_Snake_collide_KeepsAllSeveredTails(t *testing.T) {
+	w, err := world.NewWorld(100, 100)
+	require.Nil(t, err)
+
+	rules := CollisionConfig{HeadToBody: HeadToBodySever}
+
+	location := make(engine.Location, 0, 22)
+	for x := uint8(10); x < 32; x++ {
+		location = append(location, engine.Dot{X: x, Y: 10})
+	}
+
+	s := newCollisionTestSnake(t, w, rules, location)
+
+	const severs = 10
+
+	for i := 0; i < severs; i++ {
+		location := s.GetLocation()
+		success, err := s.collide(location[len(location)-2], 0, 3, rules)
+		require.Nil(t, err)
+		require.True(t, success)
+	}
+
+	require.False(t, isStopped(s), "snake must survive")
+	require.Equal(t, uint16(2), s.getLength())
+	require.Len(t, s.takeSevered(), severs)
+}
+
+// newSelfBiteTestSnake returns a snake which bites itself at the dot
+// {X: 10, Y: 11} on the next move
+func newSelfBiteTestSnake(t *testing.T, w world.Interface, rule SelfBiteRule) *Snake {
+	s := newCollisionTestSnake(t, w, CollisionConfig{SelfBite: rule}, engine.Location{
+		engine.Dot{X: 10, Y: 10},
+		engine.Dot{X: 11, Y: 10},
+		engine.Dot{X: 11, Y: 11},
+		engine.Dot{X: 10, Y: 11},
+		engine.Dot{X: 9, Y: 11},
+		engine.Dot{X: 9, Y: 12},
+	})
+	s.direction = engine.DirectionSouth
+	return s
+}
+
+func nextBite(t *testing.T, events <-chan world.Event) Bite {
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case event := <-events:
+			if event.Type == world.EventTypeObjectBite {
+				bite, ok := event.Payload.(Bite)
+				require.True(t, ok, "unexpected bite payload")
+				return bite
+			}
+		case <-timeout:
+			t.Fatal("bite event has not been sent")
+		}
+	}
+}
+
+func Test_Snake_move_SelfBiteTrimsSnake(t *testing.T) {
+	w, err := world.NewWorld(100, 100)
+	require.Nil(t, err)
+
+	stop := make(chan struct{})
+	defer close(stop)
+	w.Start(stop)
+	events := w.Events(stop, 64)
+
+	s := newSelfBiteTestSnake(t, w, SelfBiteTrim)
+
+	require.Nil(t, s.move())
+	require.False(t, isStopped(s), "snake must survive")
+	require.Equal(t, engine.Location{
+		engine.Dot{X: 10, Y: 11},
+		engine.Dot{X: 10, Y: 10},
+		engine.Dot{X: 11, Y: 10},
+	}, s.GetLocation())
+	require.Equal(t, uint16(3), s.getLength())
+
+	require.Equal(t, []engine.Location{
+		{
+			engine.Dot{X: 9, Y: 11},
+			engine.Dot{X: 9, Y: 12},
+		},
+	}, s.takeSevered())
+
+	require.Equal(t, Bite{
+		Dot:  engine.Dot{X: 10, Y: 11},
+		Self: true,
+		Kill: false,
+	}, nextBite(t, events))
+}
+
+func Test_Snake_move_SelfBiteKillsSnake(t *testing.T) {
+	w, err := world.NewWorld(100, 100)
+	require.Nil(t, err)
+
+	stop := make(chan struct{})
+	defer close(stop)
+	w.Start(stop)
+	events := w.Events(stop, 64)
+
+	s := newSelfBiteTestSnake(t, w, SelfBiteDie)
+
+	require.Equal(t, errUnsuccessfulInteraction, s.move())
+	require.Len(t, s.GetLocation(), 6)
+
+	require.Equal(t, Bite{
+		Dot:  engine.Dot{X: 10, Y: 11},
+		Self: true,
+		Kill: true,
+	}, nextBite(t, events))
+}
+
+func Test_Snake_move_ReportsKill(t *testing.T) {
+	w, err := world.NewWorld(100, 100)
+	require.Nil(t, err)
+
+	stop := make(chan struct{})
+	defer close(stop)
+	w.Start(stop)
+	events := w.Events(stop, 64)
+
+	rules := CollisionConfig{HeadToBody: HeadToBodyDefenderCut}
+
+	attacker := newCollisionTestSnake(t, w, rules, engine.Location{
+		engine.Dot{X: 10, Y: 10},
+		engine.Dot{X: 9, Y: 10},
+		engine.Dot{X: 8, Y: 10},
+	})
+	attacker.id = 1
+	defender := newCollisionTestSnake(t, w, rules, engine.Location{
+		engine.Dot{X: 11, Y: 9},
+		engine.Dot{X: 11, Y: 10},
+		engine.Dot{X: 11, Y: 11},
+	})
+	defender.id = 2
+
+	require.Nil(t, attacker.move())
+
+	require.Equal(t, Bite{
+		SnakeID:    2,
+		AttackerID: 1,
+		Dot:        engine.Dot{X: 11, Y: 10},
+		Self:       false,
+		Kill:       true,
+	}, nextBite(t, events))
+}
@@ -11,7 +11,9 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/ivan1993spb/snake-server/engine"
+	"github.com/ivan1993spb/snake-server/logfields"
 	"github.com/ivan1993spb/snake-server/objects"
+	"github.com/ivan1993spb/snake-server/objects/corpse"
 	"github.com/ivan1993spb/snake-server/world"
 )
 
@@ -28,6 +30,10 @@ const (
 
 	hitStrengthExp = 2
 
+	// snakeMaxPendingTurns limits the queue of turns which are applied one
+	// per move
+	snakeMaxPendingTurns = 3
+
 	snakeHitAward = 3
 )
 
@@ -38,6 +44,11 @@ const (
 	CommandToEast  Command = "east"
 	CommandToSouth Command = "south"
 	CommandToWest  Command = "west"
+
+	CommandTurnLeft  Command = "left"
+	CommandTurnRight Command = "right"
+
+	CommandBoost Command = "boost"
 )
 
 var snakeCommands = map[Command]engine.Direction{
@@ -47,33 +58,56 @@ var snakeCommands = map[Command]engine.Direction{
 	CommandToWest:  engine.DirectionWest,
 }
 
+// Config contains the rules of a game for snakes. The zero Config is valid
+// ffjson: skip
+type Config struct {
+	Speed     SpeedCurve
+	Boost     BoostConfig
+	Collision CollisionConfig
+
+	// Corpse contains the rules of decay of corpses which snakes leave
+	Corpse corpse.Decay
+}
+
 // Snake object
 // ffjson: skip
 type Snake struct {
 	id world.Identifier
 
-	world world.Interface
+	world  world.Interface
+	config Config
 
 	location engine.Location
 	length   uint16
 
 	direction engine.Direction
+	turns     []Command
+
+	boostEnds  time.Time
+	boostMoves uint16
 
 	mux *sync.RWMutex
 
+	// severed keeps the tails which have been cut off the snake until the
+	// snake drops them. chSevered notifies the snake of new tails
+	severed   []engine.Location
+	chSevered chan struct{}
+
 	stopper *sync.Once
 	stop    chan struct{}
 }
 
 // NewSnake creates new snake
-func NewSnake(world world.Interface) (*Snake, error) {
+func NewSnake(world world.Interface, config Config) (*Snake, error) {
 	snake := &Snake{
 		id:        world.IdentifierRegistry().Obtain(),
 		world:     world,
+		config:    config,
 		location:  make(engine.Location, snakeStartLength),
 		length:    snakeStartLength,
 		direction: engine.RandomDirection(),
 		mux:       &sync.RWMutex{},
+		chSevered: make(chan struct{}, 1),
 		stopper:   &sync.Once{},
 		stop:      make(chan struct{}),
 	}
@@ -156,6 +190,33 @@ func (s *Snake) feed(f uint16) {
 	}
 }
 
+// poison shortens the snake by the damage, but a snake does not become
+// shorter than a new snake
+func (s *Snake) poison(damage uint16) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if s.length <= snakeStartLength {
+		return nil
+	}
+
+	if s.length-snakeStartLength > damage {
+		s.length -= damage
+	} else {
+		s.length = snakeStartLength
+	}
+
+	if uint16(len(s.location)) > s.length {
+		newLocation := s.location[:s.length].Copy()
+		if err := s.world.UpdateObject(s, s.location, newLocation); err != nil {
+			return fmt.Errorf("poison snake error: %s", err)
+		}
+		s.location = newLocation
+	}
+
+	return nil
+}
+
 type errSnakeHit string
 
 func (e errSnakeHit) Error() string {
@@ -168,17 +229,10 @@ func (s *Snake) Hit(dot engine.Dot, force float64) (success bool, err error) {
 
 	if s.location.Contains(dot) {
 		if force >= math.Pow(s.unsafeGetForce(), hitStrengthExp) {
-			newLocation := s.location.Delete(dot)
-			if err := s.world.UpdateObject(s, s.location, newLocation); err != nil {
-				return false, errSnakeHit(err.Error())
+			if err := s.unsafeCut(dot); err != nil {
+				return false, err
 			}
 
-			s.location = newLocation
-
-			s.stopper.Do(func() {
-				close(s.stop)
-			})
-
 			return true, nil
 		}
 
@@ -192,6 +246,19 @@ func (s *Snake) unsafeGetForce() float64 {
 	return float64(s.length)
 }
 
+func (s *Snake) getLength() uint16 {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.length
+}
+
+// GetLength returns the number of dots of the snake
+func (s *Snake) GetLength() uint16 {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return uint16(len(s.location))
+}
+
 func (s *Snake) getForce() float64 {
 	s.mux.RLock()
 	defer s.mux.RUnlock()
@@ -200,10 +267,11 @@ func (s *Snake) getForce() float64 {
 
 func (s *Snake) Run(stop <-chan struct{}, logger logrus.FieldLogger) <-chan struct{} {
 	snakeStop := make(chan struct{})
-	logger = logger.WithField("id", s.id)
+	logger = logger.WithField(logfields.SnakeID, s.id)
 
 	go func() {
-		var ticker = time.NewTicker(s.calculateDelay())
+		var delay = s.calculateDelay()
+		var ticker = time.NewTicker(delay)
 		defer ticker.Stop()
 		defer close(snakeStop)
 		defer func() {
@@ -222,13 +290,29 @@ func (s *Snake) Run(stop <-chan struct{}, logger logrus.FieldLogger) <-chan stru
 					if err != errUnsuccessfulInteraction {
 						logger.WithError(err).Error("snake move error")
 					}
+					s.dropSevered(stop, logger)
 					return
 				}
+
+				if dot, ok, err := s.shed(); err != nil {
+					logger.WithError(err).Error("snake shed error")
+				} else if ok {
+					s.dropFood(stop, engine.Location{dot}, logger)
+				}
+
+				// The speed depends on the length which may have changed
+				if nextDelay := s.calculateDelay(); nextDelay != delay {
+					delay = nextDelay
+					ticker.Reset(delay)
+				}
+			case <-s.chSevered:
+				s.dropSevered(stop, logger)
 			case <-stop:
 				// Global stop
 				return
 			case <-s.stop:
 				// Local snake stop
+				s.dropSevered(stop, logger)
 				return
 			}
 		}
@@ -246,6 +330,8 @@ func (e errSnakeMove) Error() string {
 var errUnsuccessfulInteraction = errSnakeMove("unsuccessful interaction")
 
 func (s *Snake) move() error {
+	s.applyTurn()
+
 	// Calculate next position
 	dot, err := s.getNextHeadDot()
 	if err != nil {
@@ -301,6 +387,19 @@ func (e errInteractObject) Error() string {
 var errInteractObjectUnexpectedType = errInteractObject("unexpected object type")
 
 func (s *Snake) interactObject(object interface{}, dot engine.Dot) (success bool, err error) {
+	if poisonous, ok := object.(objects.Poisonous); ok {
+		damage, success, err := poisonous.Poison(dot)
+		if err != nil {
+			return false, errInteractObject(err.Error())
+		}
+		if success {
+			if err := s.poison(damage); err != nil {
+				return false, errInteractObject(err.Error())
+			}
+		}
+		return success, nil
+	}
+
 	if food, ok := object.(objects.Food); ok {
 		nv, success, err := food.Bite(dot)
 		if err != nil {
@@ -308,6 +407,27 @@ func (s *Snake) interactObject(object interface{}, dot engine.Dot) (success bool
 		}
 		if success {
 			s.feed(nv)
+			s.world.ReportEvent(world.EventTypeObjectEat, object)
+		}
+		return success, nil
+	}
+
+	// Collisions of snakes are resolved by the rules of the game
+	if other, ok := object.(*Snake); ok {
+		if other == s {
+			success, err := s.biteSelf(dot)
+			if err != nil {
+				return false, errInteractObject(err.Error())
+			}
+			return success, nil
+		}
+
+		success, err := other.collide(dot, s.GetID(), s.getLength(), s.config.Collision)
+		if err != nil {
+			return false, errInteractObject(err.Error())
+		}
+		if success {
+			s.feed(snakeHitAward)
 		}
 		return success, nil
 	}
@@ -337,7 +457,47 @@ func (s *Snake) interactObject(object interface{}, dot engine.Dot) (success bool
 func (s *Snake) calculateDelay() time.Duration {
 	s.mux.RLock()
 	defer s.mux.RUnlock()
-	return time.Duration(math.Pow(snakeSpeedFactor, float64(s.length)) * float64(snakeStartSpeed))
+	return s.unsafeCalculateDelay()
+}
+
+func (s *Snake) unsafeCalculateDelay() time.Duration {
+	delay := s.config.Speed.delay(s.length)
+	if s.unsafeIsBoosted(time.Now()) {
+		delay = time.Duration(float64(delay) / s.config.Boost.SpeedFactor)
+	}
+	return delay
+}
+
+// dropFood leaves a shed dot or a severed tail as a corpse behind the snake
+func (s *Snake) dropFood(stop <-chan struct{}, location engine.Location, logger logrus.FieldLogger) {
+	if c, err := corpse.NewCorpse(s.world, location, s.config.Corpse); err != nil {
+		logger.WithError(err).Debug("cannot drop food")
+	} else {
+		c.Run(stop, logger)
+	}
+}
+
+// dropSevered drops the tails which have been cut off the snake as corpses
+func (s *Snake) dropSevered(stop <-chan struct{}, logger logrus.FieldLogger) {
+	for _, tail := range s.takeSevered() {
+		s.dropFood(stop, tail, logger)
+	}
+}
+
+// takeSevered returns and forgets the tails which have been cut off the snake
+func (s *Snake) takeSevered() []engine.Location {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	tails := s.severed
+	s.severed = nil
+
+	return tails
+}
+
+// GetSpeed returns the current speed of the snake in moves per second
+func (s *Snake) GetSpeed() float64 {
+	return speed(s.calculateDelay())
 }
 
 // getNextHeadDot calculates new position of snake's head by its direction and current head position
@@ -353,8 +513,15 @@ func (s *Snake) getNextHeadDot() (engine.Dot, error) {
 }
 
 func (s *Snake) Command(cmd Command) error {
-	if direction, ok := snakeCommands[cmd]; ok {
-		if err := s.setMovementDirection(direction); err != nil {
+	if cmd == CommandBoost {
+		if err := s.boost(); err != nil {
+			return fmt.Errorf("cannot execute command: %s", err)
+		}
+		return nil
+	}
+
+	if _, ok := snakeCommands[cmd]; ok || cmd == CommandTurnLeft || cmd == CommandTurnRight {
+		if err := s.queueTurn(cmd); err != nil {
 			return fmt.Errorf("cannot execute command: %s", err)
 		}
 		return nil
@@ -363,29 +530,98 @@ func (s *Snake) Command(cmd Command) error {
 	return errors.New("cannot execute command: unknown command")
 }
 
+var errTooManyPendingTurns = errors.New("too many pending turns")
+
+// queueTurn adds a turn command to the queue of turns. The turn is validated
+// when it is applied
+func (s *Snake) queueTurn(cmd Command) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if len(s.turns) >= snakeMaxPendingTurns {
+		return errTooManyPendingTurns
+	}
+
+	s.turns = append(s.turns, cmd)
+
+	return nil
+}
+
+// applyTurn applies the first pending turn which changes the movement
+// direction. Turns which are not valid at the moment are discarded
+func (s *Snake) applyTurn() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	for len(s.turns) > 0 {
+		cmd := s.turns[0]
+		s.turns = s.turns[1:]
+
+		currentDir, err := s.unsafeMovementDirection()
+		if err != nil {
+			return
+		}
+
+		var nextDir engine.Direction
+
+		switch cmd {
+		case CommandTurnLeft:
+			nextDir, err = currentDir.TurnLeft()
+		case CommandTurnRight:
+			nextDir, err = currentDir.TurnRight()
+		default:
+			nextDir = snakeCommands[cmd]
+		}
+
+		if err != nil || nextDir == currentDir {
+			continue
+		}
+
+		if err := s.unsafeSetMovementDirection(nextDir); err != nil {
+			continue
+		}
+
+		return
+	}
+}
+
 type errSetMovementDirection string
 
 func (e errSetMovementDirection) Error() string {
 	return "set movement direction error: " + string(e)
 }
 
-func (s *Snake) setMovementDirection(nextDir engine.Direction) error {
-	if engine.ValidDirection(nextDir) {
-		s.mux.Lock()
-		defer s.mux.Unlock()
+// unsafeMovementDirection calculates the current movement direction by the
+// head of the snake
+func (s *Snake) unsafeMovementDirection() (engine.Direction, error) {
+	if len(s.location) < 2 {
+		return 0, errSetMovementDirection("cannot calculate current movement direction")
+	}
 
-		if len(s.location) < 2 {
-			return errSetMovementDirection("cannot calculate current movement direction")
+	currentDir := engine.CalculateDirection(s.location[1], s.location[0])
+	// If the dots are not nearby, reverse the direction
+	if s.location[1].DistanceTo(s.location[0]) > 1 {
+		if dir, err := currentDir.Reverse(); err != nil {
+			return 0, errSetMovementDirection("cannot calculate current movement direction")
+		} else {
+			currentDir = dir
 		}
+	}
 
-		currentDir := engine.CalculateDirection(s.location[1], s.location[0])
-		// If the dots are not nearby, reverse the direction
-		if s.location[1].DistanceTo(s.location[0]) > 1 {
-			if dir, err := currentDir.Reverse(); err != nil {
-				return errSetMovementDirection("cannot calculate current movement direction")
-			} else {
-				currentDir = dir
-			}
+	return currentDir, nil
+}
+
+func (s *Snake) setMovementDirection(nextDir engine.Direction) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.unsafeSetMovementDirection(nextDir)
+}
+
+func (s *Snake) unsafeSetMovementDirection(nextDir engine.Direction) error {
+	if engine.ValidDirection(nextDir) {
+		currentDir, err := s.unsafeMovementDirection()
+		if err != nil {
+			return err
 		}
 
 		rNextDir, err := nextDir.Reverse()
@@ -416,9 +652,10 @@ func (s *Snake) MarshalJSON() ([]byte, error) {
 	s.mux.RLock()
 	defer s.mux.RUnlock()
 	return ffjson.Marshal(&snake{
-		ID:   s.id,
-		Dots: s.location,
-		Type: snakeTypeLabel,
+		ID:    s.id,
+		Dots:  s.location,
+		Speed: speed(s.unsafeCalculateDelay()),
+		Type:  snakeTypeLabel,
 	})
 }
 
@@ -426,7 +663,8 @@ func (s *Snake) MarshalJSON() ([]byte, error) {
 
 // ffjson: nodecoder
 type snake struct {
-	ID   world.Identifier `json:"id"`
-	Dots []engine.Dot     `json:"dots,omitempty"`
-	Type string           `json:"type"`
+	ID    world.Identifier `json:"id"`
+	Dots  []engine.Dot     `json:"dots,omitempty"`
+	Speed float64          `json:"speed"`
+	Type  string           `json:"type"`
 }
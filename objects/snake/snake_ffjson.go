@@ -59,7 +59,9 @@ func (j *snake) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
 		}
 		buf.WriteByte(',')
 	}
-	buf.WriteString(`"type":`)
+	buf.WriteString(`"speed":`)
+	fflib.AppendFloat(buf, float64(j.Speed), 'g', -1, 64)
+	buf.WriteString(`,"type":`)
 	fflib.WriteJsonString(buf, string(j.Type))
 	buf.WriteByte('}')
 	return nil
@@ -3,10 +3,12 @@ package snake
 import (
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/ivan1993spb/snake-server/engine"
+	"github.com/ivan1993spb/snake-server/objects/corpse"
 	"github.com/ivan1993spb/snake-server/world"
 )
 
@@ -236,3 +238,159 @@ func Test_Snake_move_validLocation(t *testing.T) {
 		{11, 0},
 	}, snake.location)
 }
+
+func newTurnTestSnake(t *testing.T) *Snake {
+	w, err := world.NewWorld(100, 100)
+	require.Nil(t, err, "cannot initialize world")
+
+	location := engine.Location{
+		engine.Dot{X: 10, Y: 10},
+		engine.Dot{X: 9, Y: 10},
+		engine.Dot{X: 8, Y: 10},
+	}
+
+	s := &Snake{
+		world:     w,
+		length:    3,
+		location:  location,
+		direction: engine.DirectionEast,
+		mux:       &sync.RWMutex{},
+	}
+
+	require.Nil(t, w.CreateObject(s, location.Copy()), "cannot create object")
+
+	return s
+}
+
+func Test_Snake_Command_QueuesTurns(t *testing.T) {
+	s := newTurnTestSnake(t)
+
+	// The second turn would be a reverse if both were applied at once
+	require.Nil(t, s.Command(CommandToNorth))
+	require.Nil(t, s.Command(CommandToWest))
+
+	require.Nil(t, s.move())
+	require.Equal(t, engine.DirectionNorth, s.direction)
+	require.Equal(t, engine.Dot{X: 10, Y: 9}, s.location[0])
+
+	require.Nil(t, s.move())
+	require.Equal(t, engine.DirectionWest, s.direction)
+	require.Equal(t, engine.Dot{X: 9, Y: 9}, s.location[0])
+
+	require.Empty(t, s.turns)
+}
+
+func Test_Snake_Command_RelativeTurns(t *testing.T) {
+	s := newTurnTestSnake(t)
+
+	require.Nil(t, s.Command(CommandTurnLeft))
+	require.Nil(t, s.move())
+	require.Equal(t, engine.DirectionNorth, s.direction)
+
+	require.Nil(t, s.Command(CommandTurnRight))
+	require.Nil(t, s.Command(CommandTurnRight))
+	require.Nil(t, s.move())
+	require.Equal(t, engine.DirectionEast, s.direction)
+	require.Nil(t, s.move())
+	require.Equal(t, engine.DirectionSouth, s.direction)
+	require.Equal(t, engine.Dot{X: 11, Y: 10}, s.location[0])
+}
+
+func Test_Snake_Command_DiscardsInvalidTurns(t *testing.T) {
+	s := newTurnTestSnake(t)
+
+	// Reverse and no-op turns are skipped in favor of the next turn
+	require.Nil(t, s.Command(CommandToWest))
+	require.Nil(t, s.Command(CommandToEast))
+	require.Nil(t, s.Command(CommandToSouth))
+
+	require.Nil(t, s.move())
+	require.Equal(t, engine.DirectionSouth, s.direction)
+	require.Equal(t, engine.Dot{X: 10, Y: 11}, s.location[0])
+	require.Empty(t, s.turns)
+}
+
+func Test_Snake_Command_LimitsPendingTurns(t *testing.T) {
+	s := newTurnTestSnake(t)
+
+	for i := 0; i < snakeMaxPendingTurns; i++ {
+		require.Nil(t, s.Command(CommandTurnLeft))
+	}
+	require.NotNil(t, s.Command(CommandTurnLeft))
+	require.NotNil(t, s.Command(Command("up")))
+}
+
+func Test_Snake_poison_ShortensSnake(t *testing.T) {
+	w, err := world.NewWorld(100, 100)
+	require.Nil(t, err, "cannot initialize world")
+
+	location := engine.Location{
+		engine.Dot{X: 10, Y: 10},
+		engine.Dot{X: 9, Y: 10},
+		engine.Dot{X: 8, Y: 10},
+		engine.Dot{X: 7, Y: 10},
+		engine.Dot{X: 6, Y: 10},
+		engine.Dot{X: 5, Y: 10},
+	}
+
+	s := &Snake{
+		world:     w,
+		length:    6,
+		location:  location,
+		direction: engine.DirectionEast,
+		mux:       &sync.RWMutex{},
+	}
+
+	require.Nil(t, w.CreateObject(s, location.Copy()), "cannot create object")
+
+	require.Nil(t, s.poison(2))
+	require.Equal(t, uint16(4), s.length)
+	require.Equal(t, location[:4], s.GetLocation())
+	require.Nil(t, w.GetObjectByDot(engine.Dot{X: 5, Y: 10}))
+	require.Nil(t, w.GetObjectByDot(engine.Dot{X: 6, Y: 10}))
+
+	// A snake does not become shorter than a new snake
+	require.Nil(t, s.poison(2))
+	require.Equal(t, uint16(snakeStartLength), s.length)
+	require.Equal(t, location[:snakeStartLength], s.GetLocation())
+
+	require.Nil(t, s.poison(2))
+	require.Equal(t, uint16(snakeStartLength), s.length)
+}
+
+func Test_Snake_move_ReportsEatenFood(t *testing.T) {
+	w, err := world.NewWorld(100, 100)
+	require.Nil(t, err, "cannot initialize world")
+
+	stop := make(chan struct{})
+	defer close(stop)
+	w.Start(stop)
+	events := w.Events(stop, 64)
+
+	s := newCollisionTestSnake(t, w, CollisionConfig{}, engine.Location{
+		engine.Dot{X: 10, Y: 10},
+		engine.Dot{X: 9, Y: 10},
+		engine.Dot{X: 8, Y: 10},
+	})
+
+	c, err := corpse.NewCorpse(w, engine.Location{
+		engine.Dot{X: 11, Y: 10},
+		engine.Dot{X: 12, Y: 10},
+	}, corpse.Decay{})
+	require.Nil(t, err)
+
+	require.Nil(t, s.move())
+
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case event := <-events:
+			if event.Type == world.EventTypeObjectEat {
+				require.Equal(t, c, event.Payload)
+				return
+			}
+		case <-timeout:
+			t.Fatal("eat event has not been sent")
+		}
+	}
+}
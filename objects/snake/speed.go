@@ -0,0 +1,95 @@
+package snake
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	SpeedCurveConstant     = "constant"
+	SpeedCurveSlowing      = "slowing"
+	SpeedCurveAccelerating = "accelerating"
+)
+
+// SpeedCurve defines the delay between moves of a snake depending on its
+// length. The zero SpeedCurve moves snakes with the constant start speed
+type SpeedCurve struct {
+	// Delay is the delay between moves of a snake of the start length
+	Delay time.Duration
+
+	// Factor multiplies the delay for every dot by which the snake is longer
+	// than the start length. Factors greater than 1 slow long snakes down,
+	// factors less than 1 speed them up
+	Factor float64
+
+	// MinDelay and MaxDelay limit the delay if they are not zero
+	MinDelay time.Duration
+	MaxDelay time.Duration
+}
+
+var speedCurves = map[string]SpeedCurve{
+	SpeedCurveConstant: {
+		Delay:  snakeStartSpeed,
+		Factor: 1,
+	},
+	SpeedCurveSlowing: {
+		Delay:    time.Millisecond * 300,
+		Factor:   1.02,
+		MaxDelay: time.Millisecond * 800,
+	},
+	SpeedCurveAccelerating: {
+		Delay:    snakeStartSpeed,
+		Factor:   0.98,
+		MinDelay: time.Millisecond * 150,
+	},
+}
+
+// GetSpeedCurve returns a predefined speed curve by the name
+func GetSpeedCurve(name string) (SpeedCurve, bool) {
+	curve, ok := speedCurves[name]
+	return curve, ok
+}
+
+// SpeedCurveNames returns the sorted names of predefined speed curves
+func SpeedCurveNames() []string {
+	names := make([]string, 0, len(speedCurves))
+	for name := range speedCurves {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// delay returns the delay between moves of a snake with the given length
+func (c SpeedCurve) delay(length uint16) time.Duration {
+	delay := c.Delay
+	if delay <= 0 {
+		delay = snakeStartSpeed
+	}
+
+	factor := c.Factor
+	if factor <= 0 {
+		factor = snakeSpeedFactor
+	}
+
+	delay = time.Duration(math.Pow(factor, float64(length)-snakeStartLength) * float64(delay))
+
+	if c.MinDelay > 0 && delay < c.MinDelay {
+		delay = c.MinDelay
+	}
+	if c.MaxDelay > 0 && delay > c.MaxDelay {
+		delay = c.MaxDelay
+	}
+
+	return delay
+}
+
+// speed converts a delay between moves to moves per second rounded to
+// hundredths
+func speed(delay time.Duration) float64 {
+	if delay <= 0 {
+		return 0
+	}
+	return math.Round(float64(time.Second)/float64(delay)*100) / 100
+}
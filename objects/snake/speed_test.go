@@ -0,0 +1,112 @@
+package snake
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivan1993spb/snake-server/engine"
+)
+
+func Test_SpeedCurve_delay(t *testing.T) {
+	tests := []struct {
+		curve  SpeedCurve
+		length uint16
+		delay  time.Duration
+	}{
+		// Test case 1
+		{
+			curve:  SpeedCurve{},
+			length: 100,
+			delay:  snakeStartSpeed,
+		},
+		// Test case 2
+		{
+			curve: SpeedCurve{
+				Delay:  time.Millisecond * 100,
+				Factor: 2,
+			},
+			length: snakeStartLength + 2,
+			delay:  time.Millisecond * 400,
+		},
+		// Test case 3
+		{
+			curve: SpeedCurve{
+				Delay:    time.Millisecond * 100,
+				Factor:   2,
+				MaxDelay: time.Millisecond * 300,
+			},
+			length: snakeStartLength + 2,
+			delay:  time.Millisecond * 300,
+		},
+		// Test case 4
+		{
+			curve: SpeedCurve{
+				Delay:  time.Millisecond * 400,
+				Factor: 0.5,
+			},
+			length: snakeStartLength + 1,
+			delay:  time.Millisecond * 200,
+		},
+		// Test case 5
+		{
+			curve: SpeedCurve{
+				Delay:    time.Millisecond * 400,
+				Factor:   0.5,
+				MinDelay: time.Millisecond * 300,
+			},
+			length: snakeStartLength + 1,
+			delay:  time.Millisecond * 300,
+		},
+	}
+
+	for i, test := range tests {
+		require.Equal(t, test.delay, test.curve.delay(test.length), "test case %d", i+1)
+	}
+}
+
+func Test_SpeedCurves_ChangeSpeedWithLength(t *testing.T) {
+	for _, name := range SpeedCurveNames() {
+		curve, ok := GetSpeedCurve(name)
+		require.True(t, ok, name)
+		require.NotZero(t, curve.delay(snakeStartLength), name)
+	}
+
+	slowing, _ := GetSpeedCurve(SpeedCurveSlowing)
+	require.True(t, slowing.delay(20) > slowing.delay(snakeStartLength))
+
+	accelerating, _ := GetSpeedCurve(SpeedCurveAccelerating)
+	require.True(t, accelerating.delay(20) < accelerating.delay(snakeStartLength))
+
+	_, ok := GetSpeedCurve("unknown")
+	require.False(t, ok)
+}
+
+func Test_Snake_MarshalJSON_ContainsSpeed(t *testing.T) {
+	s := &Snake{
+		id:       7,
+		length:   snakeStartLength,
+		location: engine.Location{{X: 1, Y: 0}, {X: 0, Y: 0}},
+		config: Config{
+			Speed: SpeedCurve{
+				Delay: time.Millisecond * 250,
+			},
+		},
+		mux: &sync.RWMutex{},
+	}
+
+	data, err := s.MarshalJSON()
+	require.Nil(t, err)
+	require.Equal(t, `{"id":7,"dots":[[1,0],[0,0]],"speed":4,"type":"snake"}`, string(data))
+
+	s.feed(1)
+	require.Equal(t, float64(4), s.GetSpeed())
+}
+
+func Test_speed(t *testing.T) {
+	require.Equal(t, float64(2), speed(time.Millisecond*500))
+	require.Equal(t, 3.33, speed(time.Millisecond*300))
+	require.Equal(t, float64(0), speed(0))
+}
@@ -108,6 +108,32 @@ func (w *Wall) Break(dot engine.Dot, force float64) (success bool, err error) {
 	return false, errWallBreak("wall does not contain dot")
 }
 
+type errWallDestroy string
+
+func (e errWallDestroy) Error() string {
+	return "wall destroy error: " + string(e)
+}
+
+// Destroy deletes the whole wall from the world
+func (w *Wall) Destroy() error {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+
+	if len(w.location) == 0 {
+		return nil
+	}
+
+	w.world.IdentifierRegistry().Release(w.id)
+
+	if err := w.world.DeleteObject(w, w.location); err != nil {
+		return errWallDestroy(err.Error())
+	}
+
+	w.location = w.location[:0]
+
+	return nil
+}
+
 func (w *Wall) String() string {
 	w.mux.RLock()
 	defer w.mux.RUnlock()
@@ -2,6 +2,7 @@ package apple_observer
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
@@ -12,13 +13,26 @@ import (
 
 const chanAppleObserverEventsBuffer = 64
 
-const defaultAppleCount = 1
+// appleSpawnPolicy gives an apple per 50 cells but no more than 64 apples to
+// an empty map and more apples for every snake and for long snakes
+var appleSpawnPolicy = observers.SpawnPolicy{
+	AreaPerObject:      50,
+	AreaMax:            64,
+	PerSnake:           2,
+	LengthPerObject:    15,
+	DenseAreaPerObject: 20,
+	Min:                1,
+}
 
-const oneAppleArea = 50
+// addApplesPerEventLimit limits the number of apples which are added at once
+const addApplesPerEventLimit = 4
 
 type AppleObserver struct {
 	world  world.Interface
 	logger logrus.FieldLogger
+
+	population *observers.Population
+	appleCount int
 }
 
 func NewAppleObserver(w world.Interface, logger logrus.FieldLogger) observers.Observer {
@@ -38,48 +52,81 @@ func (ao *AppleObserver) run(stop <-chan struct{}) {
 }
 
 func (ao *AppleObserver) init() {
-	for i := 0; i < ao.calcAppleCount(); i++ {
-		// TODO: Create abstraction layer for adding of objects.
-		if _, err := apple.NewApple(ao.world); err != nil {
+	ao.population = observers.NewPopulation(ao.world)
+
+	for ao.appleCount < ao.calcAppleCount() {
+		if err := ao.addApple(); err != nil {
 			ao.logger.WithError(err).Error("cannot create apple")
+			return
 		}
 	}
 }
 
 func (ao *AppleObserver) listen(stop <-chan struct{}) {
-	for event := range ao.world.Events(stop, chanAppleObserverEventsBuffer) {
-		if err := ao.handleEvent(event); err != nil {
-			ao.logger.WithError(err).Error("handling event error")
+	ticker := time.NewTicker(observers.PopulationSyncPeriod)
+	defer ticker.Stop()
+
+	events := ao.world.Events(stop, chanAppleObserverEventsBuffer)
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := ao.handleEvent(event); err != nil {
+				ao.logger.WithError(err).Error("handling event error")
+			}
+		case <-ticker.C:
+			if ao.population.Sync(ao.world) {
+				if err := ao.addApples(); err != nil {
+					ao.logger.WithError(err).Error("cannot add apples")
+				}
+			}
 		}
 	}
 }
 
+// calcAppleCount returns the number of apples which should be on the map
 func (ao *AppleObserver) calcAppleCount() int {
-	appleCount := defaultAppleCount
-	size := ao.world.Area().Size()
-
-	if size > oneAppleArea {
-		appleCount = int(size / oneAppleArea)
-	}
-
-	return appleCount
+	return appleSpawnPolicy.Target(int(ao.world.Area().Size()), ao.population.Snakes(), ao.population.Length())
 }
 
 func (ao *AppleObserver) handleEvent(event world.Event) error {
-	// Event type is only delete
-	if event.Type != world.EventTypeObjectDelete {
-		return nil
+	changed := ao.population.HandleEvent(event)
+
+	if event.Type == world.EventTypeObjectDelete {
+		if _, ok := event.Payload.(*apple.Apple); ok {
+			ao.appleCount--
+			changed = true
+		}
 	}
 
-	// Object is only apple
-	if _, ok := event.Payload.(*apple.Apple); !ok {
+	if !changed {
 		return nil
 	}
 
+	return ao.addApples()
+}
+
+// addApples adds missing apples but no more than addApplesPerEventLimit
+func (ao *AppleObserver) addApples() error {
+	for added := 0; added < addApplesPerEventLimit && ao.appleCount < ao.calcAppleCount(); added++ {
+		if err := ao.addApple(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ao *AppleObserver) addApple() error {
 	// TODO: Create abstraction layer for adding of objects.
 	if _, err := apple.NewApple(ao.world); err != nil {
 		return fmt.Errorf("cannot create apple: %s", err)
 	}
 
+	ao.appleCount++
+
 	return nil
 }
@@ -1,3 +1,11 @@
 package apple_observer
 
-// TODO: Create tests.
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_appleSpawnPolicy_DoesNotFloodEmptyBigMap(t *testing.T) {
+	require.Equal(t, 64, appleSpawnPolicy.Target(255*255, 0, 0))
+}
@@ -0,0 +1,346 @@
+package arena_observer
+
+import (
+	"math"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ivan1993spb/snake-server/engine"
+	"github.com/ivan1993spb/snake-server/objects"
+	"github.com/ivan1993spb/snake-server/objects/snake"
+	"github.com/ivan1993spb/snake-server/objects/wall"
+	"github.com/ivan1993spb/snake-server/observers"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+const chanArenaObserverEventsBuffer = 64
+
+// A match starts when there are enough snakes on the map. Every interval the
+// arena shrinks by a ring of walls until the playable area is as small as
+// allowed. The last seconds before a shrink are counted down
+const (
+	arenaMinParticipants = 2
+	arenaShrinkInterval  = time.Second * 30
+	arenaCountdown       = 5
+	arenaRestartDelay    = time.Second * 10
+	arenaMinSide         = 8
+)
+
+const arenaTickInterval = time.Second
+
+// arenaRingAttempts is the number of attempts to fill up the dots of a ring
+// which are taken by moving snakes
+const arenaRingAttempts = 3
+
+// arenaKillForce is the force of walls of a ring. It kills any snake
+const arenaKillForce = math.MaxFloat64
+
+type arenaState uint8
+
+const (
+	arenaStateWaiting arenaState = iota
+	arenaStateRunning
+	arenaStateOver
+)
+
+// ArenaObserver runs battle royale matches: it shrinks the arena from the
+// border inward and ends the match when one snake remains. Snakes which
+// appear during a match do not take part in it
+type ArenaObserver struct {
+	world  world.Interface
+	logger logrus.FieldLogger
+
+	state arenaState
+
+	snakes       map[*snake.Snake]struct{}
+	participants map[*snake.Snake]struct{}
+
+	rings         []*wall.Wall
+	ringCount     uint8
+	nextShrink    time.Time
+	lastCountdown uint8
+	restart       time.Time
+}
+
+func NewArenaObserver(w world.Interface, logger logrus.FieldLogger) observers.Observer {
+	return &ArenaObserver{
+		world:        w,
+		logger:       logger,
+		snakes:       make(map[*snake.Snake]struct{}),
+		participants: make(map[*snake.Snake]struct{}),
+	}
+}
+
+func (ao *ArenaObserver) Observe(stop <-chan struct{}) {
+	go ao.run(stop)
+}
+
+func (ao *ArenaObserver) run(stop <-chan struct{}) {
+	events := ao.world.Events(stop, chanArenaObserverEventsBuffer)
+
+	for _, object := range ao.world.GetObjects() {
+		if s, ok := object.(*snake.Snake); ok {
+			ao.snakes[s] = struct{}{}
+		}
+	}
+
+	ticker := time.NewTicker(arenaTickInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			ao.handleEvent(event)
+		case now := <-ticker.C:
+			ao.tick(now)
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (ao *ArenaObserver) handleEvent(event world.Event) {
+	s, ok := event.Payload.(*snake.Snake)
+	if !ok {
+		return
+	}
+
+	switch event.Type {
+	case world.EventTypeObjectCreate:
+		ao.snakes[s] = struct{}{}
+	case world.EventTypeObjectDelete:
+		delete(ao.snakes, s)
+
+		if _, ok := ao.participants[s]; ok {
+			delete(ao.participants, s)
+
+			if ao.state == arenaStateRunning && len(ao.participants) < arenaMinParticipants {
+				ao.finish(time.Now())
+			}
+		}
+	}
+}
+
+func (ao *ArenaObserver) tick(now time.Time) {
+	switch ao.state {
+	case arenaStateWaiting:
+		if len(ao.snakes) >= arenaMinParticipants {
+			ao.start(now)
+		}
+	case arenaStateRunning:
+		if ao.nextShrink.IsZero() {
+			return
+		}
+
+		countdown := math.Ceil(ao.nextShrink.Sub(now).Seconds())
+
+		if countdown <= 0 {
+			ao.shrink(now)
+			return
+		}
+
+		if countdown <= arenaCountdown && uint8(countdown) != ao.lastCountdown {
+			ao.announceCountdown(uint8(countdown))
+		}
+	case arenaStateOver:
+		if !now.Before(ao.restart) {
+			ao.reset()
+		}
+	}
+}
+
+// start starts a match of the snakes which are on the map
+func (ao *ArenaObserver) start(now time.Time) {
+	for s := range ao.snakes {
+		ao.participants[s] = struct{}{}
+	}
+
+	ao.state = arenaStateRunning
+
+	ao.logger.WithField("participants", len(ao.participants)).Info("arena match started")
+
+	ao.scheduleShrink(now)
+}
+
+// scheduleShrink sets the time of the next shrink if the arena can shrink
+func (ao *ArenaObserver) scheduleShrink(now time.Time) {
+	if ao.ringCount >= maxRings(ao.world.Area()) {
+		ao.nextShrink = time.Time{}
+		return
+	}
+
+	ao.nextShrink = now.Add(arenaShrinkInterval)
+	ao.announceCountdown(uint8(arenaShrinkInterval / time.Second))
+}
+
+func (ao *ArenaObserver) announceCountdown(countdown uint8) {
+	ao.lastCountdown = countdown
+
+	ao.world.ReportEvent(world.EventTypeArena, world.Arena{
+		State:        world.ArenaStateCountdown,
+		Ring:         ao.ringCount + 1,
+		Countdown:    countdown,
+		Participants: len(ao.participants),
+	})
+}
+
+// shrink places the next ring of walls and kills the snakes under it
+func (ao *ArenaObserver) shrink(now time.Time) {
+	ring := ringDots(ao.world.Area(), ao.ringCount)
+
+	for attempt := 0; attempt < arenaRingAttempts && len(ring) > 0; attempt++ {
+		ao.clearDots(ring)
+
+		w, err := wall.NewWallLocation(ao.world, ring)
+		if err != nil {
+			ao.logger.WithError(err).Error("cannot create arena ring")
+			continue
+		}
+
+		ao.rings = append(ao.rings, w)
+
+		// Dots which have been taken in the meantime are filled up again
+		ring = missingDots(ring, ao.world)
+	}
+
+	ao.ringCount++
+
+	ao.logger.WithField("ring", ao.ringCount).Info("arena shrank")
+
+	ao.world.ReportEvent(world.EventTypeArena, world.Arena{
+		State:        world.ArenaStateShrink,
+		Ring:         ao.ringCount,
+		Participants: len(ao.participants),
+	})
+
+	ao.scheduleShrink(now)
+}
+
+// clearDots releases the dots for a ring: snakes are killed and food is
+// removed. Other walls stay in place
+func (ao *ArenaObserver) clearDots(dots []engine.Dot) {
+	for _, dot := range dots {
+		var err error
+
+		switch object := ao.world.GetObjectByDot(dot).(type) {
+		case objects.Alive:
+			_, err = object.Hit(dot, arenaKillForce)
+		case objects.Food:
+			_, _, err = object.Bite(dot)
+		case objects.Poisonous:
+			_, _, err = object.Poison(dot)
+		}
+
+		if err != nil {
+			ao.logger.WithError(err).Error("cannot clear arena ring")
+		}
+	}
+}
+
+// finish ends the match. The last participant wins
+func (ao *ArenaObserver) finish(now time.Time) {
+	var winner world.Identifier
+	for s := range ao.participants {
+		winner = s.GetID()
+	}
+
+	ao.state = arenaStateOver
+	ao.nextShrink = time.Time{}
+	ao.restart = now.Add(arenaRestartDelay)
+
+	ao.logger.WithField("winner", winner).Info("arena match is over")
+
+	ao.world.ReportEvent(world.EventTypeArena, world.Arena{
+		State:        world.ArenaStateOver,
+		Ring:         ao.ringCount,
+		Participants: len(ao.participants),
+		WinnerID:     winner,
+	})
+}
+
+// reset removes the rings and lets the next match start
+func (ao *ArenaObserver) reset() {
+	for _, w := range ao.rings {
+		if err := w.Destroy(); err != nil {
+			ao.logger.WithError(err).Error("cannot destroy arena ring")
+		}
+	}
+
+	ao.rings = ao.rings[:0]
+	ao.ringCount = 0
+	ao.lastCountdown = 0
+	ao.participants = make(map[*snake.Snake]struct{})
+	ao.state = arenaStateWaiting
+
+	ao.world.ReportEvent(world.EventTypeArena, world.Arena{
+		State: world.ArenaStateReset,
+	})
+}
+
+// maxRings returns the number of rings which leave the playable area not
+// smaller than arenaMinSide
+func maxRings(area engine.Area) uint8 {
+	side := area.Width()
+	if area.Height() < side {
+		side = area.Height()
+	}
+
+	if side <= arenaMinSide {
+		return 0
+	}
+
+	return (side - arenaMinSide) / 2
+}
+
+// ringDots returns the dots of the ring with the given index. The ring 0 is
+// the border of the area
+func ringDots(area engine.Area, ring uint8) []engine.Dot {
+	if uint16(ring)*2 >= uint16(area.Width()) || uint16(ring)*2 >= uint16(area.Height()) {
+		return nil
+	}
+
+	var (
+		minX = ring
+		minY = ring
+		maxX = area.Width() - 1 - ring
+		maxY = area.Height() - 1 - ring
+	)
+
+	dots := make([]engine.Dot, 0)
+
+	for x := minX; ; x++ {
+		dots = append(dots, engine.Dot{X: x, Y: minY})
+		if maxY != minY {
+			dots = append(dots, engine.Dot{X: x, Y: maxY})
+		}
+		if x == maxX {
+			break
+		}
+	}
+
+	for y := minY + 1; y < maxY; y++ {
+		dots = append(dots, engine.Dot{X: minX, Y: y})
+		if maxX != minX {
+			dots = append(dots, engine.Dot{X: maxX, Y: y})
+		}
+	}
+
+	return dots
+}
+
+// missingDots returns the dots which are not taken by walls
+func missingDots(dots []engine.Dot, w world.Interface) []engine.Dot {
+	missing := make([]engine.Dot, 0)
+
+	for _, dot := range dots {
+		if _, ok := w.GetObjectByDot(dot).(*wall.Wall); !ok {
+			missing = append(missing, dot)
+		}
+	}
+
+	return missing
+}
@@ -0,0 +1,196 @@
+package arena_observer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivan1993spb/snake-server/engine"
+	"github.com/ivan1993spb/snake-server/objects/snake"
+	"github.com/ivan1993spb/snake-server/objects/wall"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+// waitArena returns the next arena event with the given state
+func waitArena(t *testing.T, events <-chan world.Event, state string) world.Arena {
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case event := <-events:
+			if arena, ok := event.Payload.(world.Arena); ok && arena.State == state {
+				return arena
+			}
+		case <-timeout:
+			t.Fatal("arena event has not been sent")
+		}
+	}
+}
+
+func Test_ringDots(t *testing.T) {
+	tests := []struct {
+		area engine.Area
+		ring uint8
+		dots []engine.Dot
+	}{
+		// Test case 1
+		{
+			area: engine.MustArea(3, 3),
+			ring: 0,
+			dots: []engine.Dot{
+				{X: 0, Y: 0}, {X: 0, Y: 2},
+				{X: 1, Y: 0}, {X: 1, Y: 2},
+				{X: 2, Y: 0}, {X: 2, Y: 2},
+				{X: 0, Y: 1}, {X: 2, Y: 1},
+			},
+		},
+		// Test case 2
+		{
+			area: engine.MustArea(3, 3),
+			ring: 1,
+			dots: []engine.Dot{
+				{X: 1, Y: 1},
+			},
+		},
+		// Test case 3
+		{
+			area: engine.MustArea(3, 3),
+			ring: 2,
+			dots: nil,
+		},
+		// Test case 4
+		{
+			area: engine.MustArea(4, 3),
+			ring: 1,
+			dots: []engine.Dot{
+				{X: 1, Y: 1}, {X: 2, Y: 1},
+			},
+		},
+	}
+
+	for i, test := range tests {
+		require.Equal(t, test.dots, ringDots(test.area, test.ring), "test case %d", i+1)
+	}
+
+	dots := ringDots(engine.MustArea(20, 10), 2)
+	require.Len(t, dots, 2*16+2*6-4)
+	for _, dot := range dots {
+		require.True(t, dot.X == 2 || dot.X == 17 || dot.Y == 2 || dot.Y == 7, "dot %s", dot)
+	}
+}
+
+func Test_maxRings(t *testing.T) {
+	require.Equal(t, uint8(0), maxRings(engine.MustArea(8, 8)))
+	require.Equal(t, uint8(0), maxRings(engine.MustArea(100, 9)))
+	require.Equal(t, uint8(1), maxRings(engine.MustArea(10, 12)))
+	require.Equal(t, uint8(46), maxRings(engine.MustArea(100, 100)))
+}
+
+func Test_ArenaObserver_shrink_KillsSnakesUnderRing(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	w, err := world.NewWorld(40, 40)
+	require.Nil(t, err)
+
+	stop := make(chan struct{})
+	defer close(stop)
+	w.Start(stop)
+
+	s, err := snake.NewSnake(w, snake.Config{})
+	require.Nil(t, err)
+	snakeStop := s.Run(stop, logger)
+
+	ao := NewArenaObserver(w, logger).(*ArenaObserver)
+
+	// The ring goes through the head of the snake
+	head := s.GetLocation()[0]
+	ring := head.X
+	for _, d := range []uint8{head.Y, 39 - head.X, 39 - head.Y} {
+		if d < ring {
+			ring = d
+		}
+	}
+	ao.ringCount = ring
+
+	events := w.Events(stop, 4096)
+
+	ao.shrink(time.Now())
+
+	select {
+	case <-snakeStop:
+	case <-time.After(time.Second):
+		t.Fatal("snake under the ring is alive")
+	}
+
+	require.Equal(t, ring+1, ao.ringCount)
+	require.Empty(t, missingDots(ringDots(w.Area(), ring), w))
+	_, ok := w.GetObjectByDot(head).(*wall.Wall)
+	require.True(t, ok, "ring does not cover the head of the snake")
+
+	arena := waitArena(t, events, world.ArenaStateShrink)
+	require.Equal(t, ring+1, arena.Ring)
+}
+
+func Test_ArenaObserver_Match(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	w, err := world.NewWorld(20, 20)
+	require.Nil(t, err)
+
+	stop := make(chan struct{})
+	defer close(stop)
+	w.Start(stop)
+
+	first, err := snake.NewSnake(w, snake.Config{})
+	require.Nil(t, err)
+	second, err := snake.NewSnake(w, snake.Config{})
+	require.Nil(t, err)
+
+	ao := NewArenaObserver(w, logger).(*ArenaObserver)
+	for _, s := range []*snake.Snake{first, second} {
+		ao.handleEvent(world.Event{
+			Type:    world.EventTypeObjectCreate,
+			Payload: s,
+		})
+	}
+
+	now := time.Now()
+
+	ao.tick(now)
+	require.Equal(t, arenaStateRunning, ao.state)
+	require.Len(t, ao.participants, 2)
+	require.Equal(t, now.Add(arenaShrinkInterval), ao.nextShrink)
+
+	ao.tick(now.Add(arenaShrinkInterval))
+	require.Equal(t, uint8(1), ao.ringCount)
+	require.Len(t, ao.rings, 1)
+
+	// A snake which appears during the match does not take part in it
+	late, err := snake.NewSnake(w, snake.Config{})
+	require.Nil(t, err)
+	ao.handleEvent(world.Event{
+		Type:    world.EventTypeObjectCreate,
+		Payload: late,
+	})
+	require.Len(t, ao.participants, 2)
+
+	events := w.Events(stop, 4096)
+
+	ao.handleEvent(world.Event{
+		Type:    world.EventTypeObjectDelete,
+		Payload: first,
+	})
+	require.Equal(t, arenaStateOver, ao.state)
+
+	arena := waitArena(t, events, world.ArenaStateOver)
+	require.Equal(t, second.GetID(), arena.WinnerID)
+
+	ao.tick(ao.restart)
+	require.Equal(t, arenaStateWaiting, ao.state)
+	require.Equal(t, uint8(0), ao.ringCount)
+	require.NotEmpty(t, missingDots(ringDots(w.Area(), 0), w), "rings have not been removed")
+	require.Empty(t, ao.participants)
+}
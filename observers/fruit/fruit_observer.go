@@ -0,0 +1,124 @@
+package fruit_observer
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ivan1993spb/snake-server/objects/fruit"
+	"github.com/ivan1993spb/snake-server/observers"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+const chanFruitObserverEventsBuffer = 64
+
+const addFruitDelay = time.Second * 10
+
+const addFruitsDuringTickLimit = 2
+
+const oneFruitArea = 150
+
+// FruitObserver adds fruits on the map from time to time. Fruits rot and
+// disappear if nobody eats them
+type FruitObserver struct {
+	world  world.Interface
+	logger logrus.FieldLogger
+
+	fruitCount    int32
+	maxFruitCount int32
+}
+
+func NewFruitObserver(w world.Interface, logger logrus.FieldLogger) observers.Observer {
+	return &FruitObserver{
+		world:  w,
+		logger: logger,
+	}
+}
+
+func (fo *FruitObserver) Observe(stop <-chan struct{}) {
+	go fo.run(stop)
+}
+
+func (fo *FruitObserver) run(stop <-chan struct{}) {
+	fo.init()
+
+	if fo.maxFruitCount > 0 {
+		go fo.schedule(stop)
+
+		fo.listen(stop)
+	}
+}
+
+func (fo *FruitObserver) init() {
+	maxFruitCount := fo.calcMaxFruitCount()
+
+	fo.logger.WithFields(logrus.Fields{
+		"fruit_count": maxFruitCount,
+	}).Debug("fruit observer")
+
+	fo.maxFruitCount = maxFruitCount
+}
+
+// calcMaxFruitCount returns max possible fruit count
+func (fo *FruitObserver) calcMaxFruitCount() int32 {
+	var size = int32(fo.world.Area().Size())
+	var maxFruitCount = size / oneFruitArea
+	return maxFruitCount
+}
+
+func (fo *FruitObserver) listen(stop <-chan struct{}) {
+	for event := range fo.world.Events(stop, chanFruitObserverEventsBuffer) {
+		fo.handleEvent(event)
+	}
+}
+
+func (fo *FruitObserver) handleEvent(event world.Event) {
+	if event.Type != world.EventTypeObjectDelete {
+		return
+	}
+
+	if _, ok := event.Payload.(*fruit.Fruit); ok {
+		atomic.AddInt32(&fo.fruitCount, -1)
+	}
+}
+
+func (fo *FruitObserver) schedule(stop <-chan struct{}) {
+	ticker := time.NewTicker(addFruitDelay)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			fo.addFruits(stop)
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (fo *FruitObserver) addFruits(stop <-chan struct{}) {
+	var fruitsAdded = 0
+
+	for {
+		if atomic.LoadInt32(&fo.fruitCount) >= fo.maxFruitCount {
+			return
+		}
+
+		if fruitsAdded >= addFruitsDuringTickLimit {
+			return
+		}
+
+		// TODO: Create abstraction layer for adding of objects.
+		f, err := fruit.NewFruit(fo.world)
+		if err != nil {
+			fo.logger.WithError(err).Error("cannot create fruit")
+			return
+		}
+
+		atomic.AddInt32(&fo.fruitCount, 1)
+		fruitsAdded++
+
+		f.Run(stop, fo.logger)
+	}
+}
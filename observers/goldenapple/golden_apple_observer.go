@@ -0,0 +1,97 @@
+package goldenapple_observer
+
+import (
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ivan1993spb/snake-server/objects/goldenapple"
+	"github.com/ivan1993spb/snake-server/observers"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+const chanGoldenAppleObserverEventsBuffer = 64
+
+// A golden apple has a chance to appear once in the interval if there is no
+// golden apple on the map
+const (
+	goldenAppleSpawnInterval = time.Second * 20
+	goldenAppleSpawnChance   = 0.25
+	maxGoldenAppleCount      = 1
+)
+
+// GoldenAppleObserver spawns rare golden apples
+type GoldenAppleObserver struct {
+	world  world.Interface
+	logger logrus.FieldLogger
+
+	goldenAppleCount int32
+}
+
+func NewGoldenAppleObserver(w world.Interface, logger logrus.FieldLogger) observers.Observer {
+	return &GoldenAppleObserver{
+		world:  w,
+		logger: logger,
+	}
+}
+
+func (gao *GoldenAppleObserver) Observe(stop <-chan struct{}) {
+	go gao.run(stop)
+}
+
+func (gao *GoldenAppleObserver) run(stop <-chan struct{}) {
+	go gao.schedule(stop)
+
+	gao.listen(stop)
+}
+
+func (gao *GoldenAppleObserver) listen(stop <-chan struct{}) {
+	for event := range gao.world.Events(stop, chanGoldenAppleObserverEventsBuffer) {
+		gao.handleEvent(event)
+	}
+}
+
+func (gao *GoldenAppleObserver) handleEvent(event world.Event) {
+	if event.Type != world.EventTypeObjectDelete {
+		return
+	}
+
+	if _, ok := event.Payload.(*goldenapple.GoldenApple); ok {
+		atomic.AddInt32(&gao.goldenAppleCount, -1)
+	}
+}
+
+func (gao *GoldenAppleObserver) schedule(stop <-chan struct{}) {
+	ticker := time.NewTicker(goldenAppleSpawnInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if rand.Float64() < goldenAppleSpawnChance {
+				gao.addGoldenApple(stop)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (gao *GoldenAppleObserver) addGoldenApple(stop <-chan struct{}) {
+	if atomic.LoadInt32(&gao.goldenAppleCount) >= maxGoldenAppleCount {
+		return
+	}
+
+	// TODO: Create abstraction layer for adding of objects.
+	goldenApple, err := goldenapple.NewGoldenApple(gao.world)
+	if err != nil {
+		gao.logger.WithError(err).Error("cannot create golden apple")
+		return
+	}
+
+	atomic.AddInt32(&gao.goldenAppleCount, 1)
+
+	goldenApple.Run(stop, gao.logger)
+}
@@ -15,12 +15,21 @@ const mouseTickerDelay = time.Minute
 
 const chanMouseObserverEventsBuffer = 64
 
-const oneMouseArea = 400
+// mouseSpawnPolicy gives a mouse per 400 cells but no more than 8 mice to an
+// empty map and more mice to maps with many snakes
+var mouseSpawnPolicy = observers.SpawnPolicy{
+	AreaPerObject:      400,
+	AreaMax:            8,
+	PerSnake:           0.5,
+	DenseAreaPerObject: 200,
+}
 
 type MouseObserver struct {
 	world  world.Interface
 	logger logrus.FieldLogger
 
+	population *observers.Population
+
 	mouseNumber    int32
 	maxMouseNumber int32
 }
@@ -39,27 +48,25 @@ func (mo *MouseObserver) Observe(stop <-chan struct{}) {
 func (mo *MouseObserver) run(stop <-chan struct{}) {
 	mo.init()
 
-	if mo.maxMouseNumber > 0 {
-		go mo.schedule(stop)
+	go mo.schedule(stop)
 
-		mo.listen(stop)
-	}
+	mo.listen(stop)
 }
 
 func (mo *MouseObserver) init() {
+	mo.population = observers.NewPopulation(mo.world)
+
 	maxMouseNumber := mo.calcMaxMouseCount()
 
 	mo.logger.WithFields(logrus.Fields{
 		"mouse_count": maxMouseNumber,
 	}).Debug("mouse observer")
 
-	mo.maxMouseNumber = maxMouseNumber
+	atomic.StoreInt32(&mo.maxMouseNumber, maxMouseNumber)
 }
 
 func (mo *MouseObserver) calcMaxMouseCount() int32 {
-	var size = int32(mo.world.Area().Size())
-	var maxMouseNumber = size / oneMouseArea
-	return maxMouseNumber
+	return int32(mouseSpawnPolicy.Target(int(mo.world.Area().Size()), mo.population.Snakes(), mo.population.Length()))
 }
 
 func (mo *MouseObserver) schedule(stop <-chan struct{}) {
@@ -82,7 +89,7 @@ func (mo *MouseObserver) addMouse(stop <-chan struct{}) {
 	var mouseAdded = 0
 
 	for {
-		if atomic.LoadInt32(&mo.mouseNumber) >= mo.maxMouseNumber {
+		if atomic.LoadInt32(&mo.mouseNumber) >= atomic.LoadInt32(&mo.maxMouseNumber) {
 			break
 		}
 
@@ -104,12 +111,31 @@ func (mo *MouseObserver) addMouse(stop <-chan struct{}) {
 }
 
 func (mo *MouseObserver) listen(stop <-chan struct{}) {
-	for event := range mo.world.Events(stop, chanMouseObserverEventsBuffer) {
-		mo.handleEvent(event)
+	ticker := time.NewTicker(observers.PopulationSyncPeriod)
+	defer ticker.Stop()
+
+	events := mo.world.Events(stop, chanMouseObserverEventsBuffer)
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			mo.handleEvent(event)
+		case <-ticker.C:
+			if mo.population.Sync(mo.world) {
+				atomic.StoreInt32(&mo.maxMouseNumber, mo.calcMaxMouseCount())
+			}
+		}
 	}
 }
 
 func (mo *MouseObserver) handleEvent(event world.Event) {
+	if mo.population.HandleEvent(event) {
+		atomic.StoreInt32(&mo.maxMouseNumber, mo.calcMaxMouseCount())
+	}
+
 	if event.Type != world.EventTypeObjectDelete {
 		return
 	}
@@ -1,3 +1,11 @@
 package mouse_observer
 
-// TODO: Create tests.
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_mouseSpawnPolicy_DoesNotFloodEmptyBigMap(t *testing.T) {
+	require.Equal(t, 8, mouseSpawnPolicy.Target(255*255, 0, 0))
+}
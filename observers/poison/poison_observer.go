@@ -0,0 +1,85 @@
+package poison_observer
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ivan1993spb/snake-server/objects/poison"
+	"github.com/ivan1993spb/snake-server/observers"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+const chanPoisonObserverEventsBuffer = 64
+
+const defaultPoisonCount = 1
+
+const onePoisonArea = 400
+
+// PoisonObserver keeps a constant amount of poison on the map: eaten poison
+// is replaced at once
+type PoisonObserver struct {
+	world  world.Interface
+	logger logrus.FieldLogger
+}
+
+func NewPoisonObserver(w world.Interface, logger logrus.FieldLogger) observers.Observer {
+	return &PoisonObserver{
+		world:  w,
+		logger: logger,
+	}
+}
+
+func (po *PoisonObserver) Observe(stop <-chan struct{}) {
+	go po.run(stop)
+}
+
+func (po *PoisonObserver) run(stop <-chan struct{}) {
+	po.init()
+	po.listen(stop)
+}
+
+func (po *PoisonObserver) init() {
+	for i := 0; i < po.calcPoisonCount(); i++ {
+		// TODO: Create abstraction layer for adding of objects.
+		if _, err := poison.NewPoison(po.world); err != nil {
+			po.logger.WithError(err).Error("cannot create poison")
+		}
+	}
+}
+
+func (po *PoisonObserver) listen(stop <-chan struct{}) {
+	for event := range po.world.Events(stop, chanPoisonObserverEventsBuffer) {
+		if err := po.handleEvent(event); err != nil {
+			po.logger.WithError(err).Error("handling event error")
+		}
+	}
+}
+
+func (po *PoisonObserver) calcPoisonCount() int {
+	poisonCount := defaultPoisonCount
+	size := po.world.Area().Size()
+
+	if size > onePoisonArea {
+		poisonCount = int(size / onePoisonArea)
+	}
+
+	return poisonCount
+}
+
+func (po *PoisonObserver) handleEvent(event world.Event) error {
+	if event.Type != world.EventTypeObjectDelete {
+		return nil
+	}
+
+	if _, ok := event.Payload.(*poison.Poison); !ok {
+		return nil
+	}
+
+	// TODO: Create abstraction layer for adding of objects.
+	if _, err := poison.NewPoison(po.world); err != nil {
+		return fmt.Errorf("cannot create poison: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package observers
+
+import (
+	"time"
+
+	"github.com/ivan1993spb/snake-server/objects/snake"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+// SpawnPolicy defines how many objects of a kind should be on the map
+// depending on the size of the map and the snakes on it. The target is
+//
+//	min(area/AreaPerObject, AreaMax) + snakes*PerSnake + length/LengthPerObject
+//
+// limited by Min and by the density area/DenseAreaPerObject. Zero parameters
+// are ignored
+type SpawnPolicy struct {
+	// AreaPerObject is the area of the map per object on a map without snakes
+	AreaPerObject int
+
+	// AreaMax limits the number of objects which are given by the area. It
+	// keeps big maps without snakes from being flooded with objects
+	AreaMax int
+
+	// PerSnake is the number of objects added for every live snake
+	PerSnake float64
+
+	// LengthPerObject is the total length of snakes per added object
+	LengthPerObject int
+
+	// DenseAreaPerObject is the smallest area of the map per object. It keeps
+	// crowded small maps from being filled up with objects
+	DenseAreaPerObject int
+
+	// Min is the least target
+	Min int
+}
+
+// Target returns the number of objects which should be on the map of the
+// given area with the given number of snakes and their total length
+func (p SpawnPolicy) Target(area, snakes, length int) int {
+	var target float64
+
+	if p.AreaPerObject > 0 {
+		byArea := area / p.AreaPerObject
+		if p.AreaMax > 0 && byArea > p.AreaMax {
+			byArea = p.AreaMax
+		}
+		target += float64(byArea)
+	}
+
+	target += float64(snakes) * p.PerSnake
+
+	if p.LengthPerObject > 0 {
+		target += float64(length / p.LengthPerObject)
+	}
+
+	result := int(target)
+
+	if p.DenseAreaPerObject > 0 {
+		if max := area / p.DenseAreaPerObject; result > max {
+			result = max
+		}
+	}
+
+	if result < p.Min {
+		result = p.Min
+	}
+
+	return result
+}
+
+// PopulationSyncPeriod is the period of synchronization of a population with
+// the world. The world drops update events which are not received in time, so
+// the total length of snakes drifts between synchronizations
+const PopulationSyncPeriod = time.Second * 10
+
+// Population keeps the number of live snakes and their total length up to
+// date by world events. It is not safe for concurrent use
+type Population struct {
+	snakes map[*snake.Snake]int
+	length int
+}
+
+// NewPopulation creates a population of the snakes which are in the world
+func NewPopulation(w world.Interface) *Population {
+	p := &Population{}
+	p.Sync(w)
+	return p
+}
+
+// Sync counts the snakes which are in the world again. It returns true if the
+// population has changed
+func (p *Population) Sync(w world.Interface) bool {
+	snakes := make(map[*snake.Snake]int)
+	length := 0
+
+	for _, object := range w.GetObjects() {
+		if s, ok := object.(*snake.Snake); ok {
+			snakes[s] = int(s.GetLength())
+			length += snakes[s]
+		}
+	}
+
+	changed := len(snakes) != len(p.snakes) || length != p.length
+
+	p.snakes = snakes
+	p.length = length
+
+	return changed
+}
+
+// HandleEvent updates the population by the world event. It returns true if
+// the population has changed
+func (p *Population) HandleEvent(event world.Event) bool {
+	s, ok := event.Payload.(*snake.Snake)
+	if !ok {
+		return false
+	}
+
+	switch event.Type {
+	case world.EventTypeObjectCreate, world.EventTypeObjectUpdate:
+		return p.set(s, int(s.GetLength()))
+	case world.EventTypeObjectDelete:
+		if length, ok := p.snakes[s]; ok {
+			p.length -= length
+			delete(p.snakes, s)
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *Population) set(s *snake.Snake, length int) bool {
+	if prev, ok := p.snakes[s]; ok && prev == length {
+		return false
+	}
+
+	p.length += length - p.snakes[s]
+	p.snakes[s] = length
+
+	return true
+}
+
+// Snakes returns the number of live snakes
+func (p *Population) Snakes() int {
+	return len(p.snakes)
+}
+
+// Length returns the total length of live snakes
+func (p *Population) Length() int {
+	return p.length
+}
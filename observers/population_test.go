@@ -0,0 +1,180 @@
+package observers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivan1993spb/snake-server/objects/snake"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+func Test_SpawnPolicy_Target(t *testing.T) {
+	policy := SpawnPolicy{
+		AreaPerObject:      200,
+		PerSnake:           2,
+		LengthPerObject:    15,
+		DenseAreaPerObject: 20,
+		Min:                1,
+	}
+
+	tests := []struct {
+		policy SpawnPolicy
+		area   int
+		snakes int
+		length int
+		target int
+	}{
+		// Test case 1
+		{
+			policy: policy,
+			area:   100,
+			snakes: 0,
+			length: 0,
+			target: 1,
+		},
+		// Test case 2
+		{
+			policy: policy,
+			area:   2000,
+			snakes: 0,
+			length: 0,
+			target: 10,
+		},
+		// Test case 3
+		{
+			policy: policy,
+			area:   2000,
+			snakes: 3,
+			length: 45,
+			target: 19,
+		},
+		// Test case 4
+		{
+			policy: policy,
+			area:   200,
+			snakes: 10,
+			length: 300,
+			target: 10,
+		},
+		// Test case 5
+		{
+			policy: SpawnPolicy{
+				AreaPerObject: 800,
+				PerSnake:      0.25,
+			},
+			area:   400,
+			snakes: 3,
+			length: 0,
+			target: 0,
+		},
+		// Test case 6
+		{
+			policy: SpawnPolicy{
+				AreaPerObject: 800,
+				PerSnake:      0.25,
+			},
+			area:   400,
+			snakes: 4,
+			length: 0,
+			target: 1,
+		},
+		// Test case 7
+		{
+			policy: SpawnPolicy{},
+			area:   10000,
+			snakes: 10,
+			length: 1000,
+			target: 0,
+		},
+		// Test case 8
+		{
+			policy: SpawnPolicy{
+				AreaPerObject: 50,
+				AreaMax:       64,
+			},
+			area:   255 * 255,
+			snakes: 0,
+			length: 0,
+			target: 64,
+		},
+		// Test case 9
+		{
+			policy: SpawnPolicy{
+				AreaPerObject: 50,
+				AreaMax:       64,
+				PerSnake:      2,
+			},
+			area:   255 * 255,
+			snakes: 3,
+			length: 0,
+			target: 70,
+		},
+	}
+
+	for i, test := range tests {
+		target := test.policy.Target(test.area, test.snakes, test.length)
+		require.Equal(t, test.target, target, "test case %d", i+1)
+	}
+}
+
+func Test_Population_HandleEvent(t *testing.T) {
+	w, err := world.NewWorld(100, 100)
+	require.Nil(t, err)
+
+	first, err := snake.NewSnake(w, snake.Config{})
+	require.Nil(t, err)
+
+	p := NewPopulation(w)
+	require.Equal(t, 1, p.Snakes())
+	require.Equal(t, int(first.GetLength()), p.Length())
+
+	second, err := snake.NewSnake(w, snake.Config{})
+	require.Nil(t, err)
+
+	require.True(t, p.HandleEvent(world.Event{
+		Type:    world.EventTypeObjectCreate,
+		Payload: second,
+	}))
+	require.Equal(t, 2, p.Snakes())
+	require.Equal(t, int(first.GetLength()+second.GetLength()), p.Length())
+
+	require.False(t, p.HandleEvent(world.Event{
+		Type:    world.EventTypeObjectUpdate,
+		Payload: second,
+	}), "length has not changed")
+
+	require.False(t, p.HandleEvent(world.Event{
+		Type:    world.EventTypeObjectDelete,
+		Payload: "not a snake",
+	}))
+
+	require.True(t, p.HandleEvent(world.Event{
+		Type:    world.EventTypeObjectDelete,
+		Payload: first,
+	}))
+	require.Equal(t, 1, p.Snakes())
+	require.Equal(t, int(second.GetLength()), p.Length())
+
+	require.False(t, p.HandleEvent(world.Event{
+		Type:    world.EventTypeObjectDelete,
+		Payload: first,
+	}), "snake has already been deleted")
+}
+
+func Test_Population_Sync(t *testing.T) {
+	w, err := world.NewWorld(100, 100)
+	require.Nil(t, err)
+
+	p := NewPopulation(w)
+	require.Equal(t, 0, p.Snakes())
+	require.False(t, p.Sync(w), "world has not changed")
+
+	s, err := snake.NewSnake(w, snake.Config{})
+	require.Nil(t, err)
+
+	// The population has missed the creation of the snake
+	require.True(t, p.Sync(w))
+	require.Equal(t, 1, p.Snakes())
+	require.Equal(t, int(s.GetLength()), p.Length())
+}
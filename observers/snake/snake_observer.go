@@ -3,6 +3,7 @@ package snake_observer
 import (
 	"github.com/sirupsen/logrus"
 
+	"github.com/ivan1993spb/snake-server/logfields"
 	"github.com/ivan1993spb/snake-server/objects/corpse"
 	"github.com/ivan1993spb/snake-server/objects/snake"
 	"github.com/ivan1993spb/snake-server/observers"
@@ -14,12 +15,16 @@ const chanSnakeObserverEventsBuffer = 64
 type SnakeObserver struct {
 	world  world.Interface
 	logger logrus.FieldLogger
+	decay  corpse.Decay
 }
 
-func NewSnakeObserver(w world.Interface, logger logrus.FieldLogger) observers.Observer {
+// NewSnakeObserver creates an observer which turns dead snakes into corpses
+// decaying by the given rules
+func NewSnakeObserver(w world.Interface, logger logrus.FieldLogger, decay corpse.Decay) observers.Observer {
 	return &SnakeObserver{
 		world:  w,
 		logger: logger,
+		decay:  decay,
 	}
 }
 
@@ -43,17 +48,19 @@ func (so *SnakeObserver) handleEvent(event world.Event, stop <-chan struct{}) {
 	}
 
 	if s, ok := event.Payload.(*snake.Snake); ok {
+		logger := so.logger.WithField(logfields.SnakeID, s.GetID())
+
 		location := s.GetLocation().Copy()
 		if location.Empty() {
-			so.logger.Warn("snake dies and returns empty location")
+			logger.Warn("snake dies and returns empty location")
 			return
 		}
 
 		// TODO: Create abstraction layer for adding of objects.
-		if c, err := corpse.NewCorpse(so.world, location); err != nil {
-			so.logger.WithError(err).Error("cannot create corpse")
+		if c, err := corpse.NewCorpse(so.world, location, so.decay); err != nil {
+			logger.WithError(err).Error("cannot create corpse")
 		} else {
-			c.Run(stop, so.logger)
+			c.Run(stop, logger)
 		}
 	}
 }
@@ -17,12 +17,23 @@ const addWatermelonDelay = time.Second * 15
 
 const addWatermelonsDuringTickLimit = 2
 
-const oneWatermelonArea = 200
+// watermelonSpawnPolicy gives a watermelon per 200 cells but no more than 16
+// watermelons to an empty map and more watermelons to maps with many long
+// snakes
+var watermelonSpawnPolicy = observers.SpawnPolicy{
+	AreaPerObject:      200,
+	AreaMax:            16,
+	PerSnake:           0.25,
+	LengthPerObject:    100,
+	DenseAreaPerObject: 100,
+}
 
 type WatermelonObserver struct {
 	world  world.Interface
 	logger logrus.FieldLogger
 
+	population *observers.Population
+
 	watermelonCount    int32
 	maxWatermelonCount int32
 }
@@ -41,37 +52,54 @@ func (wo *WatermelonObserver) Observe(stop <-chan struct{}) {
 func (wo *WatermelonObserver) run(stop <-chan struct{}) {
 	wo.init()
 
-	if wo.maxWatermelonCount > 0 {
-		go wo.schedule(stop)
+	go wo.schedule(stop)
 
-		wo.listen(stop)
-	}
+	wo.listen(stop)
 }
 
 func (wo *WatermelonObserver) init() {
+	wo.population = observers.NewPopulation(wo.world)
+
 	maxWatermelonCount := wo.calcMaxWatermelonCount()
 
 	wo.logger.WithFields(logrus.Fields{
 		"watermelon_count": maxWatermelonCount,
 	}).Debug("watermelon observer")
 
-	wo.maxWatermelonCount = maxWatermelonCount
+	atomic.StoreInt32(&wo.maxWatermelonCount, maxWatermelonCount)
 }
 
 // calcMaxWatermelonCount returns max possible watermelon count
 func (wo *WatermelonObserver) calcMaxWatermelonCount() int32 {
-	var size = int32(wo.world.Area().Size())
-	var maxWatermelonCount = size / oneWatermelonArea
-	return maxWatermelonCount
+	return int32(watermelonSpawnPolicy.Target(int(wo.world.Area().Size()), wo.population.Snakes(), wo.population.Length()))
 }
 
 func (wo *WatermelonObserver) listen(stop <-chan struct{}) {
-	for event := range wo.world.Events(stop, chanWatermelonObserverEventsBuffer) {
-		wo.handleEvent(event)
+	ticker := time.NewTicker(observers.PopulationSyncPeriod)
+	defer ticker.Stop()
+
+	events := wo.world.Events(stop, chanWatermelonObserverEventsBuffer)
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			wo.handleEvent(event)
+		case <-ticker.C:
+			if wo.population.Sync(wo.world) {
+				atomic.StoreInt32(&wo.maxWatermelonCount, wo.calcMaxWatermelonCount())
+			}
+		}
 	}
 }
 
 func (wo *WatermelonObserver) handleEvent(event world.Event) {
+	if wo.population.HandleEvent(event) {
+		atomic.StoreInt32(&wo.maxWatermelonCount, wo.calcMaxWatermelonCount())
+	}
+
 	if event.Type != world.EventTypeObjectDelete {
 		return
 	}
@@ -99,7 +127,7 @@ func (wo *WatermelonObserver) addWatermelons() {
 	var watermelonsAdded = 0
 
 	for {
-		if atomic.LoadInt32(&wo.watermelonCount) >= wo.maxWatermelonCount {
+		if atomic.LoadInt32(&wo.watermelonCount) >= atomic.LoadInt32(&wo.maxWatermelonCount) {
 			return
 		}
 
@@ -1,3 +1,11 @@
 package watermelon_observer
 
-// TODO: Create tests.
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_watermelonSpawnPolicy_DoesNotFloodEmptyBigMap(t *testing.T) {
+	require.Equal(t, 16, watermelonSpawnPolicy.Target(255*255, 0, 0))
+}
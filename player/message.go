@@ -13,15 +13,17 @@ const (
 	MessageTypeError
 	MessageTypeCountdown
 	MessageTypeObjects
+	MessageTypeGameClosed
 )
 
 var messageTypeJSONs = map[MessageType][]byte{
-	MessageTypeSize:      []byte(`"size"`),
-	MessageTypeSnake:     []byte(`"snake"`),
-	MessageTypeNotice:    []byte(`"notice"`),
-	MessageTypeError:     []byte(`"error"`),
-	MessageTypeCountdown: []byte(`"countdown"`),
-	MessageTypeObjects:   []byte(`"objects"`),
+	MessageTypeSize:       []byte(`"size"`),
+	MessageTypeSnake:      []byte(`"snake"`),
+	MessageTypeNotice:     []byte(`"notice"`),
+	MessageTypeError:      []byte(`"error"`),
+	MessageTypeCountdown:  []byte(`"countdown"`),
+	MessageTypeObjects:    []byte(`"objects"`),
+	MessageTypeGameClosed: []byte(`"game_closed"`),
 }
 
 func (t MessageType) MarshalJSON() ([]byte, error) {
@@ -32,12 +34,13 @@ func (t MessageType) MarshalJSON() ([]byte, error) {
 }
 
 var messageTypeLabels = map[MessageType]string{
-	MessageTypeSize:      "size",
-	MessageTypeSnake:     "snake",
-	MessageTypeNotice:    "notice",
-	MessageTypeError:     "error",
-	MessageTypeCountdown: "countdown",
-	MessageTypeObjects:   "objects",
+	MessageTypeSize:       "size",
+	MessageTypeSnake:      "snake",
+	MessageTypeNotice:     "notice",
+	MessageTypeError:      "error",
+	MessageTypeCountdown:  "countdown",
+	MessageTypeObjects:    "objects",
+	MessageTypeGameClosed: "game_closed",
 }
 
 func (t MessageType) String() string {
@@ -113,3 +116,12 @@ func NewMessageObjects(objects interface{}) Message {
 		Payload: MessageObjects(objects),
 	}
 }
+
+type MessageGameClosed string
+
+func NewMessageGameClosed(reason string) Message {
+	return Message{
+		Type:    MessageTypeGameClosed,
+		Payload: MessageGameClosed(reason),
+	}
+}
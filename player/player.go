@@ -6,6 +6,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/ivan1993spb/snake-server/logfields"
 	"github.com/ivan1993spb/snake-server/objects/snake"
 	"github.com/ivan1993spb/snake-server/world"
 )
@@ -17,14 +18,16 @@ const chanMessageBuffer = 128
 const chanErrorBuffer = 32
 
 type Player struct {
-	world  world.Interface
-	logger logrus.FieldLogger
+	world       world.Interface
+	snakeConfig snake.Config
+	logger      logrus.FieldLogger
 }
 
-func NewPlayer(logger logrus.FieldLogger, world world.Interface) *Player {
+func NewPlayer(logger logrus.FieldLogger, world world.Interface, snakeConfig snake.Config) *Player {
 	return &Player{
-		logger: logger,
-		world:  world,
+		logger:      logger,
+		world:       world,
+		snakeConfig: snakeConfig,
 	}
 }
 
@@ -64,7 +67,7 @@ func (p *Player) Start(stop <-chan struct{}, chin <-chan string) <-chan Message
 
 			chout <- NewMessageNotice("start")
 
-			s, err := snake.NewSnake(p.world)
+			s, err := snake.NewSnake(p.world, p.snakeConfig)
 			if err != nil {
 				chout <- NewMessageError("cannot create snake")
 				p.logger.Errorln("cannot create snake to player:", err)
@@ -73,14 +76,17 @@ func (p *Player) Start(stop <-chan struct{}, chin <-chan string) <-chan Message
 
 			p.emptyInputChan(localStopper, chin)
 
-			snakeStop := s.Run(localStopper, p.logger)
+			logger := p.logger.WithField(logfields.SnakeID, s.GetID())
+			logger.Info("snake created")
+
+			snakeStop := s.Run(localStopper, logger)
 
 			chout <- NewMessageSnake(s.GetID())
 
 			wg.Add(1)
 			go func() {
 				defer wg.Done()
-				errch := p.processSnakeCommands(snakeStop, chin, s)
+				errch := p.processSnakeCommands(snakeStop, chin, s, logger)
 
 				for {
 					select {
@@ -106,7 +112,7 @@ func (p *Player) Start(stop <-chan struct{}, chin <-chan string) <-chan Message
 	return chout
 }
 
-func (p *Player) processSnakeCommands(stop <-chan struct{}, chin <-chan string, s *snake.Snake) <-chan error {
+func (p *Player) processSnakeCommands(stop <-chan struct{}, chin <-chan string, s *snake.Snake, logger logrus.FieldLogger) <-chan error {
 	errch := make(chan error, chanErrorBuffer)
 
 	go func() {
@@ -120,7 +126,7 @@ func (p *Player) processSnakeCommands(stop <-chan struct{}, chin <-chan string,
 					return
 				}
 
-				p.logger.WithField("command", command).Debug("received snake command")
+				logger.WithField("command", command).Debug("received snake command")
 
 				if err := s.Command(snake.Command(command)); err != nil {
 					errch <- err
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ivan1993spb/snake-server/config"
+	"github.com/ivan1993spb/snake-server/connections"
+	"github.com/ivan1993spb/snake-server/handlers"
+	"github.com/ivan1993spb/snake-server/middlewares"
+)
+
+const configFilePollInterval = time.Second * 5
+
+// reloader applies configuration changes on SIGHUP and on changes of the
+// config file without restarting the server
+type reloader struct {
+	logger       *logrus.Logger
+	groupManager *connections.ConnectionGroupManager
+	cors         *middlewares.SwitchableCORS
+	broadcast    *handlers.Switch
+	admin        *handlers.Switch
+
+	config    config.Config
+	configMux *sync.RWMutex
+}
+
+func newReloader(logger *logrus.Logger, groupManager *connections.ConnectionGroupManager,
+	cors *middlewares.SwitchableCORS, broadcast, admin *handlers.Switch, cfg config.Config) *reloader {
+	return &reloader{
+		logger:       logger,
+		groupManager: groupManager,
+		cors:         cors,
+		broadcast:    broadcast,
+		admin:        admin,
+		config:       cfg,
+		configMux:    &sync.RWMutex{},
+	}
+}
+
+// Config returns the current configuration
+func (r *reloader) Config() config.Config {
+	r.configMux.RLock()
+	defer r.configMux.RUnlock()
+	return r.config
+}
+
+func (r *reloader) Start(ctx context.Context) {
+	chSignals := make(chan os.Signal, 1)
+	signal.Notify(chSignals, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(chSignals)
+
+		ticker := time.NewTicker(configFilePollInterval)
+		defer ticker.Stop()
+
+		modTime := configFileModTime()
+
+		for {
+			select {
+			case <-chSignals:
+				r.logger.Info("SIGHUP received: reload config")
+				r.reload()
+			case <-ticker.C:
+				if t := configFileModTime(); !t.Equal(modTime) {
+					modTime = t
+					r.logger.Info("config file changed: reload config")
+					r.reload()
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func configFileModTime() time.Time {
+	path, ok := config.FilePath()
+	if !ok {
+		return time.Time{}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return info.ModTime()
+}
+
+// reload applies the live settings of the new configuration. Only the applied
+// settings are stored: settings which require a restart keep their values
+func (r *reloader) reload() {
+	next, err := configurate()
+	if err != nil {
+		r.logger.Errorln("cannot reload config:", err)
+		return
+	}
+
+	current := r.Config()
+	applied := current
+
+	if level, err := logrus.ParseLevel(next.Server.Log.Level); err != nil {
+		r.logger.Errorln("cannot apply log level:", err)
+	} else {
+		r.logger.SetLevel(level)
+		applied.Server.Log.Level = next.Server.Log.Level
+	}
+
+	if err := r.groupManager.SetLimits(next.Server.Limits.Groups, next.Server.Limits.Conns); err != nil {
+		r.logger.Errorln("cannot apply limits:", err)
+	} else {
+		applied.Server.Limits = next.Server.Limits
+	}
+
+	r.cors.SetEnabled(!next.Server.Flags.ForbidCORS)
+	applied.Server.Flags.ForbidCORS = next.Server.Flags.ForbidCORS
+
+	if next.Server.Flags.EnableBroadcast && !current.Server.Flags.EnableBroadcast {
+		r.logger.Warning("broadcasting API method is enabled!")
+	}
+	r.broadcast.SetEnabled(next.Server.Flags.EnableBroadcast)
+	applied.Server.Flags.EnableBroadcast = next.Server.Flags.EnableBroadcast
+
+	r.admin.SetEnabled(next.Server.Flags.EnableAdmin)
+	applied.Server.Flags.EnableAdmin = next.Server.Flags.EnableAdmin
+
+	if current.Server.Sentry != next.Server.Sentry {
+		r.logger.ReplaceHooks(sentryHooks(r.logger, next.Server.Sentry))
+		applied.Server.Sentry = next.Server.Sentry
+	}
+
+	applied.Server.Drain = next.Server.Drain
+
+	r.configMux.Lock()
+	r.config = applied
+	r.configMux.Unlock()
+
+	live, _ := config.Changes(current, applied)
+	_, restart := config.Changes(current, next)
+
+	r.logger.WithField("fields", live).Info("config reloaded")
+
+	if len(restart) > 0 {
+		r.logger.WithField("fields", restart).Warn("changed settings require a restart")
+	}
+}
@@ -0,0 +1,389 @@
+package rpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative snake.proto
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/pquerna/ffjson/ffjson"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ivan1993spb/snake-server/broadcast"
+	"github.com/ivan1993spb/snake-server/connections"
+	"github.com/ivan1993spb/snake-server/game"
+	"github.com/ivan1993spb/snake-server/logfields"
+	"github.com/ivan1993spb/snake-server/player"
+)
+
+const defaultEnableWalls = true
+
+const (
+	broadcastDelay   = time.Second * 15
+	broadcastTimeout = time.Millisecond
+)
+
+const (
+	chanGameEventsBuffer    = 512
+	chanBroadcastBuffer     = 64
+	chanSnakeCommandsBuffer = 64
+)
+
+type server struct {
+	UnimplementedGamesServer
+
+	logger       logrus.FieldLogger
+	groupManager *connections.ConnectionGroupManager
+}
+
+// NewServer creates a gRPC games server backed by the group manager
+func NewServer(logger logrus.FieldLogger, groupManager *connections.ConnectionGroupManager) GamesServer {
+	return &server{
+		logger:       logger,
+		groupManager: groupManager,
+	}
+}
+
+func (s *server) CreateGame(ctx context.Context, request *CreateGameRequest) (*Game, error) {
+	enableWalls := defaultEnableWalls
+	if request.EnableWalls != nil {
+		enableWalls = request.GetEnableWalls()
+	}
+
+	group, err := connections.NewConnectionGroupParams(s.logger, connections.GroupParams{
+		Limit:              int(request.GetLimit()),
+		Width:              int(request.GetWidth()),
+		Height:             int(request.GetHeight()),
+		EnableWalls:        enableWalls,
+		EnablePoison:       request.GetEnablePoison(),
+		EnableGoldenApples: request.GetEnableGoldenApples(),
+		EnableFruits:       request.GetEnableFruits(),
+		BattleRoyale:       request.GetBattleRoyale(),
+		BatchWindow:        time.Duration(request.GetBatchWindowMs()) * time.Millisecond,
+		SpeedCurve:         request.GetSpeedCurve(),
+		EnableBoost:        request.GetEnableBoost(),
+		HeadToHead:         request.GetHeadToHead(),
+		HeadToBody:         request.GetHeadToBody(),
+		SelfBite:           request.GetSelfBite(),
+		CorpseLifetime:     time.Duration(request.GetCorpseLifetimeS()) * time.Second,
+		CorpseDecay:        request.GetCorpseDecay(),
+	})
+	if err != nil {
+		if errParams, ok := err.(connections.ErrGroupParams); ok {
+			return nil, status.Error(codes.InvalidArgument, errParams.Error())
+		}
+		s.logger.WithError(err).Error("rpc: cannot create game")
+		return nil, status.Error(codes.Internal, "cannot create game")
+	}
+
+	id, err := s.groupManager.Add(group)
+	if err != nil {
+		s.logger.WithError(err).Error("rpc: cannot add game")
+
+		switch err {
+		case connections.ErrGroupLimitReached:
+			return nil, status.Error(codes.ResourceExhausted, "groups limit reached")
+		case connections.ErrConnsLimitReached:
+			return nil, status.Error(codes.ResourceExhausted, "connections limit reached")
+		case connections.ErrServerDraining:
+			return nil, status.Error(codes.Unavailable, "server is shutting down")
+		}
+		return nil, status.Error(codes.Internal, "unknown error")
+	}
+
+	group.Start()
+
+	s.logger.WithField(logfields.GameID, id).Info("rpc: created group")
+
+	return newGame(id, group), nil
+}
+
+func (s *server) GetGames(ctx context.Context, request *GetGamesRequest) (*GetGamesResponse, error) {
+	if request.GetLimit() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid limit value")
+	}
+
+	limit := int(request.GetLimit())
+	if limit == 0 {
+		limit = -1
+	}
+
+	infos, err := s.groupManager.ListGroups(request.GetSorting(), limit)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid sorting")
+	}
+
+	games := make([]*Game, 0, len(infos))
+
+	for _, info := range infos {
+		games = append(games, &Game{
+			Id:     int32(info.ID),
+			Limit:  int32(info.Limit),
+			Count:  int32(info.Count),
+			Width:  uint32(info.Width),
+			Height: uint32(info.Height),
+			Rate:   info.Rate,
+		})
+	}
+
+	return &GetGamesResponse{
+		Games: games,
+		Limit: int32(s.groupManager.GroupLimit()),
+		Count: int32(s.groupManager.GroupCount()),
+	}, nil
+}
+
+func (s *server) GetGame(ctx context.Context, request *GetGameRequest) (*Game, error) {
+	group, err := s.getGroup(request.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	return newGame(int(request.GetId()), group), nil
+}
+
+func (s *server) DeleteGame(ctx context.Context, request *DeleteGameRequest) (*DeleteGameResponse, error) {
+	reason := request.GetReason()
+	if len(reason) > connections.MaxCloseReasonLength {
+		return nil, status.Error(codes.InvalidArgument, "reason is too long")
+	}
+	if len(reason) == 0 {
+		reason = connections.DefaultCloseReason
+	}
+
+	group, err := s.getGroup(request.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if !group.IsEmpty() && request.GetForce() {
+		s.logger.WithFields(logrus.Fields{
+			logfields.GameID: request.GetId(),
+			"count":          group.GetCount(),
+			"reason":         reason,
+		}).Warn("rpc: force delete not empty group")
+
+		// The group is stopped if it has not drained, so it is deleted anyway
+		if !group.Close(reason, connections.ForceDeleteDrainTimeout) {
+			s.logger.WithField(logfields.GameID, request.GetId()).Warn("rpc: group has not drained")
+		}
+	}
+
+	if err := s.groupManager.Delete(group); err != nil {
+		s.logger.WithError(err).Error("rpc: cannot delete game")
+
+		switch err {
+		case connections.ErrDeleteNotFoundGroup:
+			return nil, status.Error(codes.NotFound, "game not found")
+		case connections.ErrDeleteNotEmptyGroup:
+			return nil, status.Error(codes.FailedPrecondition, "cannot delete not empty game")
+		}
+		return nil, status.Error(codes.Internal, "unknown error")
+	}
+
+	group.Stop()
+
+	s.logger.WithField(logfields.GameID, request.GetId()).Info("rpc: group deleted")
+
+	return &DeleteGameResponse{
+		Id: request.GetId(),
+	}, nil
+}
+
+func (s *server) GetCapacity(ctx context.Context, request *GetCapacityRequest) (*Capacity, error) {
+	return &Capacity{
+		Capacity: s.groupManager.Capacity(),
+	}, nil
+}
+
+func (s *server) GameEvents(request *GameEventsRequest, stream Games_GameEventsServer) error {
+	group, err := s.getGroup(request.GetId())
+	if err != nil {
+		return err
+	}
+
+	stop := stream.Context().Done()
+
+	if err := sendPlayerMessage(stream, player.NewMessageSize(group.GetWorldWidth(), group.GetWorldHeight())); err != nil {
+		return err
+	}
+	if err := sendPlayerMessage(stream, player.NewMessageObjects(group.GetObjects())); err != nil {
+		return err
+	}
+
+	for event := range group.ListenGameEvents(stop, chanGameEventsBuffer) {
+		if err := sendMessage(stream, connections.OutputMessageTypeGame, event); err != nil {
+			return err
+		}
+	}
+
+	return stream.Context().Err()
+}
+
+func (s *server) Play(stream Games_PlayServer) error {
+	request, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	join := request.GetJoin()
+	if join == nil {
+		return status.Error(codes.InvalidArgument, "first request must be join")
+	}
+
+	group, err := s.getGroup(join.GetId())
+	if err != nil {
+		return err
+	}
+
+	logger := s.logger.WithField(logfields.GameID, join.GetId())
+
+	err = group.HandleFunc(func(stop <-chan struct{}, g *game.Game, b *broadcast.GroupBroadcast) error {
+		return s.play(logger, stream, stop, group, g, b)
+	})
+	if err != nil {
+		var errHandle *connections.ErrHandleConnection
+		if errors.As(err, &errHandle) {
+			switch errHandle.Err {
+			case connections.ErrGroupIsFull:
+				return status.Error(codes.ResourceExhausted, "group is full")
+			case connections.ErrGroupIsClosed:
+				return status.Error(codes.Unavailable, "group is closed")
+			}
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (s *server) play(logger logrus.FieldLogger, stream Games_PlayServer, stop <-chan struct{},
+	group *connections.ConnectionGroup, g *game.Game, b *broadcast.GroupBroadcast) error {
+	b.BroadcastMessage("user joined your game group")
+	defer b.BroadcastMessage("user left your game group")
+
+	chStop := make(chan struct{})
+	chErr := make(chan error, 1)
+	chCommands := make(chan string, chanSnakeCommandsBuffer)
+
+	go func() {
+		defer close(chStop)
+
+		var lastBroadcastTime time.Time
+
+		for {
+			request, err := stream.Recv()
+			if err != nil {
+				if err != io.EOF {
+					chErr <- err
+				}
+				return
+			}
+
+			switch r := request.GetRequest().(type) {
+			case *PlayRequest_Command:
+				select {
+				case chCommands <- r.Command:
+				default:
+					logger.Warn("rpc: snake commands buffer is full")
+				}
+			case *PlayRequest_Broadcast:
+				if time.Since(lastBroadcastTime) > broadcastDelay {
+					b.BroadcastMessageTimeout(broadcast.Message(r.Broadcast), broadcastTimeout)
+					lastBroadcastTime = time.Now()
+				} else {
+					logger.Warn("rpc: ignore broadcast: delay")
+				}
+			}
+		}
+	}()
+
+	chPlayerStop := make(chan struct{})
+	defer close(chPlayerStop)
+
+	chPlayer := player.NewPlayer(logger, g.World(), g.Config().Snake).Start(chPlayerStop, chCommands)
+	chBroadcast := b.ListenMessages(chPlayerStop, chanBroadcastBuffer)
+	chEvents := group.ListenGameEvents(chPlayerStop, chanGameEventsBuffer)
+
+	for {
+		var err error
+
+		select {
+		case message, ok := <-chPlayer:
+			if !ok {
+				return nil
+			}
+			err = sendMessage(stream, connections.OutputMessageTypePlayer, message)
+		case message, ok := <-chBroadcast:
+			if !ok {
+				return nil
+			}
+			err = sendMessage(stream, connections.OutputMessageTypeBroadcast, message)
+		case event, ok := <-chEvents:
+			if !ok {
+				return nil
+			}
+			err = sendMessage(stream, connections.OutputMessageTypeGame, event)
+		case <-chStop:
+			select {
+			case err := <-chErr:
+				return err
+			default:
+				return nil
+			}
+		case <-stop:
+			return status.Error(codes.Unavailable, "game is closed")
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (s *server) getGroup(id int32) (*connections.ConnectionGroup, error) {
+	group, err := s.groupManager.Get(int(id))
+	if err != nil {
+		if err == connections.ErrNotFoundGroup {
+			return nil, status.Error(codes.NotFound, "game not found")
+		}
+		s.logger.WithError(err).Error("rpc: cannot get group")
+		return nil, status.Error(codes.Internal, "unknown error")
+	}
+	return group, nil
+}
+
+func newGame(id int, group *connections.ConnectionGroup) *Game {
+	return &Game{
+		Id:     int32(id),
+		Limit:  int32(group.GetLimit()),
+		Count:  int32(group.GetCount()),
+		Width:  uint32(group.GetWorldWidth()),
+		Height: uint32(group.GetWorldHeight()),
+		Rate:   group.GetRate(),
+	}
+}
+
+type messageSender interface {
+	Send(*Message) error
+}
+
+func sendPlayerMessage(stream messageSender, message player.Message) error {
+	return sendMessage(stream, connections.OutputMessageTypePlayer, message)
+}
+
+func sendMessage(stream messageSender, messageType connections.OutputMessageType, payload interface{}) error {
+	data, err := ffjson.Marshal(payload)
+	if err != nil {
+		return status.Error(codes.Internal, "encode message error")
+	}
+
+	return stream.Send(&Message{
+		Type:    messageType.String(),
+		Payload: data,
+	})
+}
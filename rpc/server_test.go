@@ -0,0 +1,166 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/ivan1993spb/snake-server/connections"
+)
+
+const bufconnSize = 1024 * 1024
+
+func newTestClient(t *testing.T, groupManager *connections.ConnectionGroupManager) GamesClient {
+	logger, _ := test.NewNullLogger()
+
+	listener := bufconn.Listen(bufconnSize)
+	server := grpc.NewServer()
+	RegisterGamesServer(server, NewServer(logger, groupManager))
+
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufconn",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.Nil(t, err)
+	t.Cleanup(func() {
+		conn.Close()
+	})
+
+	return NewGamesClient(conn)
+}
+
+func Test_Server_CreateGame_CreatesAndDeletesGroup(t *testing.T) {
+	const groupsLimit = 5
+	const connsLimit = 10
+
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	groupManager, err := connections.NewConnectionGroupManager(logger, groupsLimit, connsLimit)
+	require.Nil(t, err)
+
+	client := newTestClient(t, groupManager)
+	ctx := context.Background()
+
+	game, err := client.CreateGame(ctx, &CreateGameRequest{
+		Limit:  10,
+		Width:  40,
+		Height: 30,
+	})
+	require.Nil(t, err)
+	require.Equal(t, int32(1), game.GetId())
+	require.Equal(t, int32(10), game.GetLimit())
+	require.Equal(t, uint32(40), game.GetWidth())
+	require.Equal(t, uint32(30), game.GetHeight())
+
+	games, err := client.GetGames(ctx, &GetGamesRequest{})
+	require.Nil(t, err)
+	require.Len(t, games.GetGames(), 1)
+	require.Equal(t, int32(groupsLimit), games.GetLimit())
+
+	games, err = client.GetGames(ctx, &GetGamesRequest{Sorting: connections.GroupsSortingSmart})
+	require.Nil(t, err)
+	require.Len(t, games.GetGames(), 1)
+
+	_, err = client.GetGames(ctx, &GetGamesRequest{Sorting: "invalid"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	game, err = client.GetGame(ctx, &GetGameRequest{Id: 1})
+	require.Nil(t, err)
+	require.Equal(t, int32(1), game.GetId())
+
+	_, err = client.DeleteGame(ctx, &DeleteGameRequest{
+		Id:     1,
+		Force:  true,
+		Reason: strings.Repeat("a", connections.MaxCloseReasonLength+1),
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	deleted, err := client.DeleteGame(ctx, &DeleteGameRequest{Id: 1, Force: true})
+	require.Nil(t, err)
+	require.Equal(t, int32(1), deleted.GetId())
+
+	_, err = client.GetGame(ctx, &GetGameRequest{Id: 1})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func Test_Server_CreateGame_ReturnsInvalidArgument(t *testing.T) {
+	const groupsLimit = 5
+	const connsLimit = 10
+
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	groupManager, err := connections.NewConnectionGroupManager(logger, groupsLimit, connsLimit)
+	require.Nil(t, err)
+
+	client := newTestClient(t, groupManager)
+
+	requests := []*CreateGameRequest{
+		{Limit: 0, Width: 40, Height: 30},
+		{Limit: 10, Width: 4, Height: 30},
+		{Limit: 10, Width: 40, Height: 300},
+		{Limit: 10, Width: 40, Height: 30, BatchWindowMs: 1001},
+		{Limit: 10, Width: 40, Height: 30, SpeedCurve: "warp"},
+		{Limit: 10, Width: 40, Height: 30, HeadToHead: "nobody_dies"},
+		{Limit: 10, Width: 40, Height: 30, HeadToBody: "longer_wins"},
+		{Limit: 10, Width: 40, Height: 30, SelfBite: "sever"},
+		{Limit: 10, Width: 40, Height: 30, CorpseLifetimeS: 61},
+	}
+
+	for i, request := range requests {
+		_, err := client.CreateGame(context.Background(), request)
+		require.Equal(t, codes.InvalidArgument, status.Code(err), "case number %d", i+1)
+	}
+
+	require.Zero(t, groupManager.GroupCount())
+}
+
+func Test_Server_GameEvents_SendsSizeFirst(t *testing.T) {
+	const groupsLimit = 5
+	const connsLimit = 10
+
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	groupManager, err := connections.NewConnectionGroupManager(logger, groupsLimit, connsLimit)
+	require.Nil(t, err)
+
+	client := newTestClient(t, groupManager)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	game, err := client.CreateGame(ctx, &CreateGameRequest{
+		Limit:  10,
+		Width:  20,
+		Height: 20,
+	})
+	require.Nil(t, err)
+
+	group, err := groupManager.Get(int(game.GetId()))
+	require.Nil(t, err)
+	defer group.Stop()
+
+	stream, err := client.GameEvents(ctx, &GameEventsRequest{Id: game.GetId()})
+	require.Nil(t, err)
+
+	message, err := stream.Recv()
+	require.Nil(t, err)
+	require.Equal(t, "player", message.GetType())
+	require.JSONEq(t, `{"type":"size","payload":{"width":20,"height":20}}`, string(message.GetPayload()))
+}
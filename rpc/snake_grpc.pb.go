@@ -0,0 +1,409 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: snake.proto
+
+package rpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Games_CreateGame_FullMethodName  = "/snakeserver.Games/CreateGame"
+	Games_GetGames_FullMethodName    = "/snakeserver.Games/GetGames"
+	Games_GetGame_FullMethodName     = "/snakeserver.Games/GetGame"
+	Games_DeleteGame_FullMethodName  = "/snakeserver.Games/DeleteGame"
+	Games_GetCapacity_FullMethodName = "/snakeserver.Games/GetCapacity"
+	Games_GameEvents_FullMethodName  = "/snakeserver.Games/GameEvents"
+	Games_Play_FullMethodName        = "/snakeserver.Games/Play"
+)
+
+// GamesClient is the client API for Games service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type GamesClient interface {
+	// CreateGame creates a new game
+	CreateGame(ctx context.Context, in *CreateGameRequest, opts ...grpc.CallOption) (*Game, error)
+	// GetGames returns a list of games
+	GetGames(ctx context.Context, in *GetGamesRequest, opts ...grpc.CallOption) (*GetGamesResponse, error)
+	// GetGame returns a game by the identifier
+	GetGame(ctx context.Context, in *GetGameRequest, opts ...grpc.CallOption) (*Game, error)
+	// DeleteGame deletes an empty game. A game with players is deleted only
+	// if force is set: the players get the reason and are disconnected
+	DeleteGame(ctx context.Context, in *DeleteGameRequest, opts ...grpc.CallOption) (*DeleteGameResponse, error)
+	// GetCapacity returns the capacity of the server
+	GetCapacity(ctx context.Context, in *GetCapacityRequest, opts ...grpc.CallOption) (*Capacity, error)
+	// GameEvents streams the events of a game. A spectator does not occupy
+	// a place in the game
+	GameEvents(ctx context.Context, in *GameEventsRequest, opts ...grpc.CallOption) (Games_GameEventsClient, error)
+	// Play joins a game and controls a snake. The first request must be join
+	Play(ctx context.Context, opts ...grpc.CallOption) (Games_PlayClient, error)
+}
+
+type gamesClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGamesClient(cc grpc.ClientConnInterface) GamesClient {
+	return &gamesClient{cc}
+}
+
+func (c *gamesClient) CreateGame(ctx context.Context, in *CreateGameRequest, opts ...grpc.CallOption) (*Game, error) {
+	out := new(Game)
+	err := c.cc.Invoke(ctx, Games_CreateGame_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gamesClient) GetGames(ctx context.Context, in *GetGamesRequest, opts ...grpc.CallOption) (*GetGamesResponse, error) {
+	out := new(GetGamesResponse)
+	err := c.cc.Invoke(ctx, Games_GetGames_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gamesClient) GetGame(ctx context.Context, in *GetGameRequest, opts ...grpc.CallOption) (*Game, error) {
+	out := new(Game)
+	err := c.cc.Invoke(ctx, Games_GetGame_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gamesClient) DeleteGame(ctx context.Context, in *DeleteGameRequest, opts ...grpc.CallOption) (*DeleteGameResponse, error) {
+	out := new(DeleteGameResponse)
+	err := c.cc.Invoke(ctx, Games_DeleteGame_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gamesClient) GetCapacity(ctx context.Context, in *GetCapacityRequest, opts ...grpc.CallOption) (*Capacity, error) {
+	out := new(Capacity)
+	err := c.cc.Invoke(ctx, Games_GetCapacity_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gamesClient) GameEvents(ctx context.Context, in *GameEventsRequest, opts ...grpc.CallOption) (Games_GameEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Games_ServiceDesc.Streams[0], Games_GameEvents_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &gamesGameEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Games_GameEventsClient interface {
+	Recv() (*Message, error)
+	grpc.ClientStream
+}
+
+type gamesGameEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *gamesGameEventsClient) Recv() (*Message, error) {
+	m := new(Message)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *gamesClient) Play(ctx context.Context, opts ...grpc.CallOption) (Games_PlayClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Games_ServiceDesc.Streams[1], Games_Play_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &gamesPlayClient{stream}
+	return x, nil
+}
+
+type Games_PlayClient interface {
+	Send(*PlayRequest) error
+	Recv() (*Message, error)
+	grpc.ClientStream
+}
+
+type gamesPlayClient struct {
+	grpc.ClientStream
+}
+
+func (x *gamesPlayClient) Send(m *PlayRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *gamesPlayClient) Recv() (*Message, error) {
+	m := new(Message)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// GamesServer is the server API for Games service.
+// All implementations must embed UnimplementedGamesServer
+// for forward compatibility
+type GamesServer interface {
+	// CreateGame creates a new game
+	CreateGame(context.Context, *CreateGameRequest) (*Game, error)
+	// GetGames returns a list of games
+	GetGames(context.Context, *GetGamesRequest) (*GetGamesResponse, error)
+	// GetGame returns a game by the identifier
+	GetGame(context.Context, *GetGameRequest) (*Game, error)
+	// DeleteGame deletes an empty game. A game with players is deleted only
+	// if force is set: the players get the reason and are disconnected
+	DeleteGame(context.Context, *DeleteGameRequest) (*DeleteGameResponse, error)
+	// GetCapacity returns the capacity of the server
+	GetCapacity(context.Context, *GetCapacityRequest) (*Capacity, error)
+	// GameEvents streams the events of a game. A spectator does not occupy
+	// a place in the game
+	GameEvents(*GameEventsRequest, Games_GameEventsServer) error
+	// Play joins a game and controls a snake. The first request must be join
+	Play(Games_PlayServer) error
+	mustEmbedUnimplementedGamesServer()
+}
+
+// UnimplementedGamesServer must be embedded to have forward compatible implementations.
+type UnimplementedGamesServer struct {
+}
+
+func (UnimplementedGamesServer) CreateGame(context.Context, *CreateGameRequest) (*Game, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateGame not implemented")
+}
+func (UnimplementedGamesServer) GetGames(context.Context, *GetGamesRequest) (*GetGamesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGames not implemented")
+}
+func (UnimplementedGamesServer) GetGame(context.Context, *GetGameRequest) (*Game, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGame not implemented")
+}
+func (UnimplementedGamesServer) DeleteGame(context.Context, *DeleteGameRequest) (*DeleteGameResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteGame not implemented")
+}
+func (UnimplementedGamesServer) GetCapacity(context.Context, *GetCapacityRequest) (*Capacity, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCapacity not implemented")
+}
+func (UnimplementedGamesServer) GameEvents(*GameEventsRequest, Games_GameEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method GameEvents not implemented")
+}
+func (UnimplementedGamesServer) Play(Games_PlayServer) error {
+	return status.Errorf(codes.Unimplemented, "method Play not implemented")
+}
+func (UnimplementedGamesServer) mustEmbedUnimplementedGamesServer() {}
+
+// UnsafeGamesServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to GamesServer will
+// result in compilation errors.
+type UnsafeGamesServer interface {
+	mustEmbedUnimplementedGamesServer()
+}
+
+func RegisterGamesServer(s grpc.ServiceRegistrar, srv GamesServer) {
+	s.RegisterService(&Games_ServiceDesc, srv)
+}
+
+func _Games_CreateGame_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateGameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GamesServer).CreateGame(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Games_CreateGame_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GamesServer).CreateGame(ctx, req.(*CreateGameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Games_GetGames_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetGamesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GamesServer).GetGames(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Games_GetGames_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GamesServer).GetGames(ctx, req.(*GetGamesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Games_GetGame_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetGameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GamesServer).GetGame(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Games_GetGame_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GamesServer).GetGame(ctx, req.(*GetGameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Games_DeleteGame_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteGameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GamesServer).DeleteGame(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Games_DeleteGame_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GamesServer).DeleteGame(ctx, req.(*DeleteGameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Games_GetCapacity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCapacityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GamesServer).GetCapacity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Games_GetCapacity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GamesServer).GetCapacity(ctx, req.(*GetCapacityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Games_GameEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GameEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GamesServer).GameEvents(m, &gamesGameEventsServer{stream})
+}
+
+type Games_GameEventsServer interface {
+	Send(*Message) error
+	grpc.ServerStream
+}
+
+type gamesGameEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *gamesGameEventsServer) Send(m *Message) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Games_Play_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(GamesServer).Play(&gamesPlayServer{stream})
+}
+
+type Games_PlayServer interface {
+	Send(*Message) error
+	Recv() (*PlayRequest, error)
+	grpc.ServerStream
+}
+
+type gamesPlayServer struct {
+	grpc.ServerStream
+}
+
+func (x *gamesPlayServer) Send(m *Message) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *gamesPlayServer) Recv() (*PlayRequest, error) {
+	m := new(PlayRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Games_ServiceDesc is the grpc.ServiceDesc for Games service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Games_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "snakeserver.Games",
+	HandlerType: (*GamesServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateGame",
+			Handler:    _Games_CreateGame_Handler,
+		},
+		{
+			MethodName: "GetGames",
+			Handler:    _Games_GetGames_Handler,
+		},
+		{
+			MethodName: "GetGame",
+			Handler:    _Games_GetGame_Handler,
+		},
+		{
+			MethodName: "DeleteGame",
+			Handler:    _Games_DeleteGame_Handler,
+		},
+		{
+			MethodName: "GetCapacity",
+			Handler:    _Games_GetCapacity_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GameEvents",
+			Handler:       _Games_GameEvents_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Play",
+			Handler:       _Games_Play_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "snake.proto",
+}
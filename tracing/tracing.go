@@ -0,0 +1,110 @@
+// Package tracing sets up exporting of OpenTelemetry traces and helps to
+// correlate logs with traces
+package tracing
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ivan1993spb/snake-server/config"
+)
+
+const tracerName = "github.com/ivan1993spb/snake-server"
+
+const serviceName = "snake-server"
+
+// Attribute keys of the server's spans
+const (
+	AttributeGameID       = attribute.Key("snake.game_id")
+	AttributeSnakeID      = attribute.Key("snake.snake_id")
+	AttributeConnectionID = attribute.Key("snake.connection_id")
+)
+
+// Log field names of trace identifiers
+const (
+	logFieldTraceID = "trace_id"
+	logFieldSpanID  = "span_id"
+)
+
+type errSetup struct {
+	err error
+}
+
+func (e *errSetup) Error() string {
+	return "cannot setup tracing: " + e.err.Error()
+}
+
+// Setup installs a global tracer provider exporting spans through OTLP/HTTP
+// and returns a function to flush and stop the exporter. If tracing is
+// disabled, spans are not recorded
+func Setup(ctx context.Context, cfg config.Tracing, version string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if !cfg.Enable {
+		return func(context.Context) error {
+			return nil
+		}, nil
+	}
+
+	options := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(cfg.Endpoint),
+	}
+	if cfg.Insecure {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+
+	exporter, err := otlptracehttp.New(ctx, options...)
+	if err != nil {
+		return nil, &errSetup{err}
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+		semconv.ServiceVersion(version),
+	))
+	if err != nil {
+		return nil, &errSetup{err}
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// Tracer returns the tracer of the server
+func Tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// LogFields returns logrus fields with identifiers of the span in the
+// context. The fields are empty if there is no recording span
+func LogFields(ctx context.Context) logrus.Fields {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return logrus.Fields{}
+	}
+
+	return logrus.Fields{
+		logFieldTraceID: spanContext.TraceID().String(),
+		logFieldSpanID:  spanContext.SpanID().String(),
+	}
+}
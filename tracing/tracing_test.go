@@ -0,0 +1,29 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/ivan1993spb/snake-server/config"
+)
+
+func Test_LogFields_ReturnsIdentifiersOfSpan(t *testing.T) {
+	require.Empty(t, LogFields(context.Background()))
+
+	provider := sdktrace.NewTracerProvider()
+	ctx, span := provider.Tracer(tracerName).Start(context.Background(), "test")
+	defer span.End()
+
+	fields := LogFields(ctx)
+	require.Equal(t, span.SpanContext().TraceID().String(), fields[logFieldTraceID])
+	require.Equal(t, span.SpanContext().SpanID().String(), fields[logFieldSpanID])
+}
+
+func Test_Setup_DoesNothingIfDisabled(t *testing.T) {
+	shutdown, err := Setup(context.Background(), config.Tracing{Enable: false}, "test")
+	require.Nil(t, err)
+	require.Nil(t, shutdown(context.Background()))
+}
@@ -0,0 +1,91 @@
+package webhooks
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ivan1993spb/snake-server/connections"
+)
+
+const deliveryLogSize = 128
+
+// Delivery is a record of the delivery log
+type Delivery struct {
+	ID         string                     `json:"id"`
+	URL        string                     `json:"url"`
+	EventType  connections.GroupEventType `json:"event_type"`
+	GameID     int                        `json:"game_id"`
+	Created    time.Time                  `json:"created"`
+	Attempts   int                        `json:"attempts"`
+	StatusCode int                        `json:"status_code,omitempty"`
+	Error      string                     `json:"error,omitempty"`
+	Success    bool                       `json:"success"`
+}
+
+// DeliveryLog keeps a limited number of the latest deliveries
+type DeliveryLog struct {
+	records []*Delivery
+	next    int
+	mux     *sync.RWMutex
+}
+
+func NewDeliveryLog(size int) *DeliveryLog {
+	return &DeliveryLog{
+		records: make([]*Delivery, 0, size),
+		mux:     &sync.RWMutex{},
+	}
+}
+
+// Add adds a new delivery to the log replacing the oldest record if the log
+// is full
+func (l *DeliveryLog) Add(event Event, url string) *Delivery {
+	delivery := &Delivery{
+		ID:        event.ID,
+		URL:       url,
+		EventType: event.Type,
+		GameID:    event.GameID,
+		Created:   event.Time,
+	}
+
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	if len(l.records) < cap(l.records) {
+		l.records = append(l.records, delivery)
+	} else {
+		l.records[l.next] = delivery
+	}
+	l.next = (l.next + 1) % cap(l.records)
+
+	return delivery
+}
+
+// Update records the result of a delivery attempt
+func (l *DeliveryLog) Update(delivery *Delivery, attempt, statusCode int, err error) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	delivery.Attempts = attempt
+	delivery.StatusCode = statusCode
+	delivery.Success = err == nil
+	if err != nil {
+		delivery.Error = err.Error()
+	} else {
+		delivery.Error = ""
+	}
+}
+
+// List returns copies of the records from the newest to the oldest
+func (l *DeliveryLog) List() []Delivery {
+	l.mux.RLock()
+	defer l.mux.RUnlock()
+
+	deliveries := make([]Delivery, 0, len(l.records))
+
+	for i := 1; i <= len(l.records); i++ {
+		index := (l.next - i + len(l.records)) % len(l.records)
+		deliveries = append(deliveries, *l.records[index])
+	}
+
+	return deliveries
+}
@@ -0,0 +1,395 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ivan1993spb/snake-server/connections"
+	"github.com/ivan1993spb/snake-server/logfields"
+)
+
+const (
+	HeaderEvent     = "X-Snake-Event"
+	HeaderDelivery  = "X-Snake-Delivery"
+	HeaderSignature = "X-Snake-Signature"
+
+	signaturePrefix = "sha256="
+)
+
+const (
+	chanGroupEventsBuffer = 512
+
+	deliveryTimeout = time.Second * 5
+
+	deliveryMaxAttempts  = 5
+	deliveryBackoffStart = time.Second
+	deliveryBackoffMax   = time.Second * 30
+
+	deliveriesMaxConcurrent = 16
+
+	gameURLsLimit = 8
+
+	gameSecretSize = 16
+
+	bootNonceSize = 8
+)
+
+// Event is a body of a webhook request
+type Event struct {
+	ID      string                     `json:"id"`
+	Type    connections.GroupEventType `json:"type"`
+	GameID  int                        `json:"game_id"`
+	Time    time.Time                  `json:"time"`
+	Payload interface{}                `json:"payload,omitempty"`
+}
+
+// gameWebhook is a URL registered for a particular game. Its requests are
+// signed with its own secret
+type gameWebhook struct {
+	url    string
+	secret []byte
+}
+
+// deliveryTarget is a URL to deliver an event to
+type deliveryTarget struct {
+	url    string
+	secret []byte
+	client *http.Client
+}
+
+// Dispatcher delivers lifecycle events of game groups to registered URLs
+type Dispatcher struct {
+	logger logrus.FieldLogger
+	client *http.Client
+	secret []byte
+	urls   []string
+
+	// Game webhooks are registered by clients. They are limited to the
+	// allowed hosts and cannot reach internal addresses
+	gameClient   *http.Client
+	allowedHosts []string
+	lookup       func(ctx context.Context, host string) ([]net.IPAddr, error)
+
+	gameURLs    map[int][]gameWebhook
+	gameURLsMux *sync.RWMutex
+
+	log *DeliveryLog
+
+	// Event identifiers are unique across restarts: a counter is prefixed
+	// with a random nonce of the process
+	nonce   string
+	counter uint64
+
+	semaphore chan struct{}
+	backoff   time.Duration
+}
+
+type ErrCreateDispatcher string
+
+func (e ErrCreateDispatcher) Error() string {
+	return "cannot create webhooks dispatcher: " + string(e)
+}
+
+// NewDispatcher creates a dispatcher which notifies the given URLs about
+// events of all games. Requests are signed with the secret. URLs of the
+// allowed hosts can be registered for particular games
+func NewDispatcher(logger logrus.FieldLogger, urls []string, secret string, allowedHosts []string) (*Dispatcher, error) {
+	for _, u := range urls {
+		if err := ValidateURL(u); err != nil {
+			return nil, ErrCreateDispatcher(err.Error())
+		}
+	}
+
+	nonce := make([]byte, bootNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, ErrCreateDispatcher(err.Error())
+	}
+
+	return &Dispatcher{
+		logger: logger,
+		client: &http.Client{
+			Timeout: deliveryTimeout,
+		},
+		secret: []byte(secret),
+		urls:   urls,
+
+		gameClient:   newGuardedClient(),
+		allowedHosts: allowedHosts,
+		lookup:       net.DefaultResolver.LookupIPAddr,
+
+		gameURLs:    map[int][]gameWebhook{},
+		gameURLsMux: &sync.RWMutex{},
+
+		log: NewDeliveryLog(deliveryLogSize),
+
+		nonce: hex.EncodeToString(nonce),
+
+		semaphore: make(chan struct{}, deliveriesMaxConcurrent),
+		backoff:   deliveryBackoffStart,
+	}, nil
+}
+
+var (
+	ErrInvalidURL        = errors.New("invalid webhook URL")
+	ErrGameURLsLimit     = errors.New("webhooks limit reached for the game")
+	ErrURLRegistered     = errors.New("webhook URL is already registered")
+	errUnexpectedStatus  = errors.New("unexpected response status")
+	errDeliveryCancelled = errors.New("delivery cancelled")
+)
+
+// ValidateURL returns an error if the URL cannot be used as a webhook
+func ValidateURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ErrInvalidURL
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ErrInvalidURL
+	}
+	if len(u.Host) == 0 {
+		return ErrInvalidURL
+	}
+	return nil
+}
+
+// GameWebhooksEnabled returns true if URLs can be registered for games
+func (d *Dispatcher) GameWebhooksEnabled() bool {
+	return len(d.allowedHosts) > 0
+}
+
+// Register registers the URL to be notified about events of the game. The
+// host of the URL must be allowed and must not resolve to an internal
+// address. It returns the secret which signs requests to the URL
+func (d *Dispatcher) Register(gameID int, rawURL string) (string, error) {
+	if err := ValidateURL(rawURL); err != nil {
+		return "", err
+	}
+
+	u, _ := url.Parse(rawURL)
+
+	if !hostAllowed(u.Hostname(), d.allowedHosts) {
+		return "", ErrHostNotAllowed
+	}
+
+	if err := checkHost(d.lookup, u.Hostname()); err != nil {
+		return "", err
+	}
+
+	secret := make([]byte, gameSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	d.gameURLsMux.Lock()
+	defer d.gameURLsMux.Unlock()
+
+	webhooks := d.gameURLs[gameID]
+
+	for _, webhook := range webhooks {
+		if webhook.url == rawURL {
+			return "", ErrURLRegistered
+		}
+	}
+
+	if len(webhooks) >= gameURLsLimit {
+		return "", ErrGameURLsLimit
+	}
+
+	encoded := hex.EncodeToString(secret)
+
+	d.gameURLs[gameID] = append(webhooks, gameWebhook{
+		url:    rawURL,
+		secret: []byte(encoded),
+	})
+
+	return encoded, nil
+}
+
+// GameURLs returns URLs registered for the game
+func (d *Dispatcher) GameURLs(gameID int) []string {
+	d.gameURLsMux.RLock()
+	defer d.gameURLsMux.RUnlock()
+
+	urls := make([]string, 0, len(d.gameURLs[gameID]))
+	for _, webhook := range d.gameURLs[gameID] {
+		urls = append(urls, webhook.url)
+	}
+
+	return urls
+}
+
+func (d *Dispatcher) unregister(gameID int) {
+	d.gameURLsMux.Lock()
+	defer d.gameURLsMux.Unlock()
+	delete(d.gameURLs, gameID)
+}
+
+func (d *Dispatcher) targets(gameID int) []deliveryTarget {
+	d.gameURLsMux.RLock()
+	defer d.gameURLsMux.RUnlock()
+
+	targets := make([]deliveryTarget, 0, len(d.urls)+len(d.gameURLs[gameID]))
+	for _, u := range d.urls {
+		targets = append(targets, deliveryTarget{
+			url:    u,
+			secret: d.secret,
+			client: d.client,
+		})
+	}
+	for _, webhook := range d.gameURLs[gameID] {
+		targets = append(targets, deliveryTarget{
+			url:    webhook.url,
+			secret: webhook.secret,
+			client: d.gameClient,
+		})
+	}
+
+	return targets
+}
+
+// Deliveries returns the latest deliveries
+func (d *Dispatcher) Deliveries() []Delivery {
+	return d.log.List()
+}
+
+// Start starts listening to group events of the manager
+func (d *Dispatcher) Start(stop <-chan struct{}, groupManager *connections.ConnectionGroupManager) {
+	go d.listen(stop, groupManager.ListenGroupEvents(stop, chanGroupEventsBuffer))
+}
+
+func (d *Dispatcher) listen(stop <-chan struct{}, chin <-chan connections.GroupEvent) {
+	for groupEvent := range chin {
+		d.dispatch(stop, groupEvent)
+
+		if groupEvent.Type == connections.GroupEventTypeGameDeleted {
+			d.unregister(groupEvent.GameID)
+		}
+	}
+}
+
+func (d *Dispatcher) dispatch(stop <-chan struct{}, groupEvent connections.GroupEvent) {
+	targets := d.targets(groupEvent.GameID)
+	if len(targets) == 0 {
+		return
+	}
+
+	event := Event{
+		ID:      d.nonce + "-" + strconv.FormatUint(atomic.AddUint64(&d.counter, 1), 10),
+		Type:    groupEvent.Type,
+		GameID:  groupEvent.GameID,
+		Time:    time.Now().UTC(),
+		Payload: groupEvent.Payload,
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		d.logger.WithError(err).Error("cannot encode webhook event")
+		return
+	}
+
+	for _, target := range targets {
+		delivery := d.log.Add(event, target.url)
+
+		select {
+		case d.semaphore <- struct{}{}:
+		case <-stop:
+			return
+		}
+
+		go func(delivery *Delivery, target deliveryTarget) {
+			defer func() {
+				<-d.semaphore
+			}()
+			d.deliver(stop, delivery, target, event, body)
+		}(delivery, target)
+	}
+}
+
+func (d *Dispatcher) deliver(stop <-chan struct{}, delivery *Delivery, target deliveryTarget, event Event, body []byte) {
+	logger := d.logger.WithFields(logrus.Fields{
+		"webhook_url":    target.url,
+		"event_type":     event.Type,
+		logfields.GameID: event.GameID,
+		"delivery":       event.ID,
+	})
+
+	backoff := d.backoff
+
+	for attempt := 1; attempt <= deliveryMaxAttempts; attempt++ {
+		statusCode, err := d.send(target, event, body)
+		d.log.Update(delivery, attempt, statusCode, err)
+
+		if err == nil {
+			logger.Debug("webhook delivered")
+			return
+		}
+
+		logger.WithError(err).WithField("attempt", attempt).Warn("webhook delivery failed")
+
+		if attempt == deliveryMaxAttempts {
+			break
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-stop:
+			timer.Stop()
+			d.log.Update(delivery, attempt, statusCode, errDeliveryCancelled)
+			return
+		}
+
+		if backoff *= 2; backoff > deliveryBackoffMax {
+			backoff = deliveryBackoffMax
+		}
+	}
+
+	logger.Error("webhook delivery failed: attempts limit reached")
+}
+
+func (d *Dispatcher) send(target deliveryTarget, event Event, body []byte) (int, error) {
+	request, err := http.NewRequest(http.MethodPost, target.url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	request.Header.Set("Content-Type", "application/json; charset=utf-8")
+	request.Header.Set(HeaderEvent, event.Type.String())
+	request.Header.Set(HeaderDelivery, event.ID)
+	request.Header.Set(HeaderSignature, Sign(target.secret, body))
+
+	response, err := target.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return response.StatusCode, fmt.Errorf("%w: %d", errUnexpectedStatus, response.StatusCode)
+	}
+
+	return response.StatusCode, nil
+}
+
+// Sign returns a signature of the body: sha256=<hex encoded HMAC-SHA256>
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,212 @@
+package webhooks
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivan1993spb/snake-server/connections"
+)
+
+func Test_Sign_ReturnsHMACSHA256(t *testing.T) {
+	require.Equal(t,
+		"sha256=f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8",
+		Sign([]byte("key"), []byte("The quick brown fox jumps over the lazy dog")),
+	)
+}
+
+func Test_ValidateURL(t *testing.T) {
+	require.Nil(t, ValidateURL("http://example.com/hook"))
+	require.Nil(t, ValidateURL("https://example.com:8443/hook?token=1"))
+	require.Equal(t, ErrInvalidURL, ValidateURL(""))
+	require.Equal(t, ErrInvalidURL, ValidateURL("ftp://example.com/hook"))
+	require.Equal(t, ErrInvalidURL, ValidateURL("https:///hook"))
+}
+
+// lookupStatic returns a resolver which resolves every host to the IP
+func lookupStatic(ip string) func(ctx context.Context, host string) ([]net.IPAddr, error) {
+	return func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		return []net.IPAddr{{IP: net.ParseIP(ip)}}, nil
+	}
+}
+
+func Test_Dispatcher_Register_LimitsGameURLs(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	d, err := NewDispatcher(logger, nil, "", []string{"example.com"})
+	require.Nil(t, err)
+	d.lookup = lookupStatic("93.184.216.34")
+
+	register := func(rawURL string) error {
+		_, err := d.Register(1, rawURL)
+		return err
+	}
+
+	require.Equal(t, ErrInvalidURL, register("invalid"))
+
+	secret, err := d.Register(1, "http://example.com/0")
+	require.Nil(t, err)
+	require.Len(t, secret, gameSecretSize*2)
+	require.Equal(t, ErrURLRegistered, register("http://example.com/0"))
+
+	for i := 1; i < gameURLsLimit; i++ {
+		require.Nil(t, register("http://example.com/"+string(rune('a'+i))))
+	}
+	require.Equal(t, ErrGameURLsLimit, register("http://example.com/z"))
+
+	require.Len(t, d.GameURLs(1), gameURLsLimit)
+	require.Empty(t, d.GameURLs(2))
+}
+
+func Test_Dispatcher_Register_RejectsForbiddenHosts(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	d, err := NewDispatcher(logger, nil, "", []string{"example.com", ".hooks.example.org", "127.0.0.1"})
+	require.Nil(t, err)
+	d.lookup = lookupStatic("93.184.216.34")
+
+	tests := []struct {
+		url string
+		err error
+	}{
+		// Test case 1
+		{
+			url: "https://example.com/hook",
+			err: nil,
+		},
+		// Test case 2
+		{
+			url: "https://a.hooks.example.org/hook",
+			err: nil,
+		},
+		// Test case 3
+		{
+			url: "https://hooks.example.org/hook",
+			err: ErrHostNotAllowed,
+		},
+		// Test case 4
+		{
+			url: "https://example.net/hook",
+			err: ErrHostNotAllowed,
+		},
+		// Test case 5
+		{
+			url: "http://127.0.0.1:8080/hook",
+			err: ErrForbiddenAddress,
+		},
+	}
+
+	for i, test := range tests {
+		_, err := d.Register(1, test.url)
+		require.Equal(t, test.err, err, "test case %d", i+1)
+	}
+
+	for _, ip := range []string{"127.0.0.1", "10.0.0.1", "192.168.1.1", "169.254.169.254", "100.64.0.1", "::1", "fd00::1", "::ffff:100.100.100.100"} {
+		d.lookup = lookupStatic(ip)
+		_, err := d.Register(2, "https://example.com/hook")
+		require.Equal(t, ErrForbiddenAddress, err, "ip %s", ip)
+	}
+
+	disabled, err := NewDispatcher(logger, nil, "", nil)
+	require.Nil(t, err)
+	require.False(t, disabled.GameWebhooksEnabled())
+	_, err = disabled.Register(1, "https://example.com/hook")
+	require.Equal(t, ErrHostNotAllowed, err)
+}
+
+func Test_Dispatcher_gameClient_RefusesInternalAddresses(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request to an internal address has been sent")
+	}))
+	defer server.Close()
+
+	d, err := NewDispatcher(logger, nil, "", []string{"example.com"})
+	require.Nil(t, err)
+
+	_, err = d.gameClient.Post(server.URL, "application/json", nil)
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), ErrForbiddenAddress.Error())
+}
+
+func Test_Dispatcher_dispatch_RetriesAndSignsRequests(t *testing.T) {
+	const secret = "secret"
+
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	var requests int32
+	chBody := make(chan []byte, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.Nil(t, err)
+		require.Equal(t, Sign([]byte(secret), body), r.Header.Get(HeaderSignature))
+		require.Equal(t, "player_joined", r.Header.Get(HeaderEvent))
+
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		chBody <- body
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	d, err := NewDispatcher(logger, []string{server.URL}, secret, nil)
+	require.Nil(t, err)
+	d.backoff = time.Millisecond
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	d.dispatch(stop, connections.GroupEvent{
+		Type:   connections.GroupEventTypePlayerJoined,
+		GameID: 3,
+		Payload: connections.GroupEventPlayers{
+			Count: 1,
+		},
+	})
+
+	select {
+	case body := <-chBody:
+		require.Contains(t, string(body), `"type":"player_joined"`)
+		require.Contains(t, string(body), `"game_id":3`)
+		require.Contains(t, string(body), `"payload":{"count":1}`)
+		require.Regexp(t, `"id":"[0-9a-f]{16}-1"`, string(body))
+	case <-time.After(time.Second):
+		t.Fatal("webhook has not been delivered")
+	}
+
+	require.Eventually(t, func() bool {
+		deliveries := d.Deliveries()
+		return len(deliveries) == 1 && deliveries[0].Success && deliveries[0].Attempts == 2
+	}, time.Second, time.Millisecond*10)
+}
+
+func Test_DeliveryLog_List_ReturnsNewestFirst(t *testing.T) {
+	l := NewDeliveryLog(3)
+
+	for _, id := range []string{"1", "2", "3", "4"} {
+		l.Add(Event{ID: id}, "http://example.com")
+	}
+
+	deliveries := l.List()
+	require.Len(t, deliveries, 3)
+	require.Equal(t, "4", deliveries[0].ID)
+	require.Equal(t, "3", deliveries[1].ID)
+	require.Equal(t, "2", deliveries[2].ID)
+}
@@ -0,0 +1,31 @@
+package world
+
+const (
+	ArenaStateCountdown = "countdown"
+	ArenaStateShrink    = "shrink"
+	ArenaStateOver      = "over"
+	ArenaStateReset     = "reset"
+)
+
+//go:generate ffjson -force-regenerate $GOFILE
+
+// Arena is the payload of the arena event which is sent when the state of the
+// shrinking arena of a battle royale game changes
+// ffjson: nodecoder
+type Arena struct {
+	State string `json:"state"`
+
+	// Ring is the number of wall rings around the playable area. A countdown
+	// announces the ring which is placed at the next shrink
+	Ring uint8 `json:"ring"`
+
+	// Countdown is the number of seconds until the next shrink
+	Countdown uint8 `json:"countdown"`
+
+	// Participants is the number of snakes which are still in the match
+	Participants int `json:"participants"`
+
+	// WinnerID is the identifier of the last snake of the match. It is zero
+	// if the match is not over or if nobody has survived
+	WinnerID Identifier `json:"winner_id"`
+}
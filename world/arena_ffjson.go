@@ -0,0 +1,46 @@
+// Code generated by ffjson <https://github.com/pquerna/ffjson>. DO NOT EDIT.
+// source: ./world/arena.go
+
+package world
+
+import (
+	fflib "github.com/pquerna/ffjson/fflib/v1"
+)
+
+// MarshalJSON marshal bytes to json - template
+func (j *Arena) MarshalJSON() ([]byte, error) {
+	var buf fflib.Buffer
+	if j == nil {
+		buf.WriteString("null")
+		return buf.Bytes(), nil
+	}
+	err := j.MarshalJSONBuf(&buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// MarshalJSONBuf marshal buff to json - template
+func (j *Arena) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
+	if j == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	var err error
+	var obj []byte
+	_ = obj
+	_ = err
+	buf.WriteString(`{"state":`)
+	fflib.WriteJsonString(buf, string(j.State))
+	buf.WriteString(`,"ring":`)
+	fflib.FormatBits2(buf, uint64(j.Ring), 10, false)
+	buf.WriteString(`,"countdown":`)
+	fflib.FormatBits2(buf, uint64(j.Countdown), 10, false)
+	buf.WriteString(`,"participants":`)
+	fflib.FormatBits2(buf, uint64(j.Participants), 10, j.Participants < 0)
+	buf.WriteString(`,"winner_id":`)
+	fflib.FormatBits2(buf, uint64(j.WinnerID), 10, false)
+	buf.WriteByte('}')
+	return nil
+}
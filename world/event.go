@@ -10,6 +10,9 @@ const (
 	EventTypeObjectDelete
 	EventTypeObjectUpdate
 	EventTypeObjectChecked
+	EventTypeObjectBite
+	EventTypeArena
+	EventTypeObjectEat
 )
 
 var eventsLabels = map[EventType]string{
@@ -18,6 +21,9 @@ var eventsLabels = map[EventType]string{
 	EventTypeObjectDelete:  "delete",
 	EventTypeObjectUpdate:  "update",
 	EventTypeObjectChecked: "checked",
+	EventTypeObjectBite:    "bite",
+	EventTypeArena:         "arena",
+	EventTypeObjectEat:     "eat",
 }
 
 func (event EventType) String() string {
@@ -33,6 +39,9 @@ var eventTypesJSONs = map[EventType][]byte{
 	EventTypeObjectDelete:  []byte(`"delete"`),
 	EventTypeObjectUpdate:  []byte(`"update"`),
 	EventTypeObjectChecked: []byte(`"checked"`),
+	EventTypeObjectBite:    []byte(`"bite"`),
+	EventTypeArena:         []byte(`"arena"`),
+	EventTypeObjectEat:     []byte(`"eat"`),
 }
 
 func (event EventType) MarshalJSON() ([]byte, error) {
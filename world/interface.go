@@ -8,5 +8,7 @@ type Interface interface {
 
 	IdentifierRegistry() *IdentifierRegistry
 
+	ReportEvent(eventType EventType, payload interface{})
+
 	playground.Playground
 }
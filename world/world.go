@@ -275,6 +275,15 @@ func (w *World) UpdateObject(object engine.Object, old, new engine.Location) err
 	return nil
 }
 
+// ReportEvent sends an event of the given type which is raised by an object or
+// an observer of the world rather than by the playground
+func (w *World) ReportEvent(eventType EventType, payload interface{}) {
+	w.event(Event{
+		Type:    eventType,
+		Payload: payload,
+	})
+}
+
 func (w *World) UpdateObjectAvailableDots(object engine.Object, old, new engine.Location) (engine.Location, error) {
 	location, err := w.pg.UpdateObjectAvailableDots(object, old, new)
 	if err != nil {
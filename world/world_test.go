@@ -94,3 +94,22 @@ func Benchmark_World_UpdateObject(b *testing.B) {
 	// TODO: Implement benchmark.
 	b.Skip("Not implemented")
 }
+
+func Test_World_ReportEvent(t *testing.T) {
+	world, err := NewWorld(10, 10)
+	require.Nil(t, err, "cannot initialize world")
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	world.Start(stop)
+
+	chEvents := world.Events(stop, 1)
+
+	payload := &struct{}{}
+	world.ReportEvent(EventTypeArena, payload)
+
+	event := <-chEvents
+	require.Equal(t, EventTypeArena, event.Type)
+	require.Equal(t, payload, event.Payload)
+}